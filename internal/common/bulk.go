@@ -0,0 +1,210 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"sync"
+)
+
+//bulkResponseBody is the generic shape of every bulk API response which is used to merge the responses of chunks
+type bulkResponseBody struct {
+	Status    common.Status     `json:"status"`
+	BulkItems []json.RawMessage `json:"requests"`
+}
+
+//bulkChunksError is given by sendBulkChunks together with the merged response if some chunks failed or were cancelled,
+//the sub-requests of these chunks have error statuses in the response and their errors are kept by the input index
+type bulkChunksError struct {
+	itemErrors map[int]*common.ErplyError
+	err        error //the error of the first failed chunk
+}
+
+func (bce *bulkChunksError) Error() string {
+	return bce.err.Error()
+}
+
+func (bce *bulkChunksError) Unwrap() error {
+	return bce.err
+}
+
+//SplitBulkInputs splits the sub-requests into chunks which the API accepts in one bulk call
+func SplitBulkInputs(bulkInputs []BulkInput, chunkSize int) [][]BulkInput {
+	if chunkSize <= 0 {
+		chunkSize = common.MaxBulkRequestsCount
+	}
+
+	chunks := make([][]BulkInput, 0, (len(bulkInputs)+chunkSize-1)/chunkSize)
+	for start := 0; start < len(bulkInputs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(bulkInputs) {
+			end = len(bulkInputs)
+		}
+		chunks = append(chunks, bulkInputs[start:end])
+	}
+
+	return chunks
+}
+
+//sendBulkChunks sends the sub-requests in chunks of MaxBulkRequestsCount items, at most bulkConcurrency chunks are sent
+//at the same time, each chunk goes through the throttler, retries and middlewares as a separate bulk call.
+//The sub-request responses are merged in the input order into one bulk response, its status is the status of the first
+//successful chunk with the total generation time. The first failed chunk cancels the remaining ones, the response of
+//the finished chunks is returned anyway with bulkChunksError, so the caller gets the results of the written records.
+//The error of the failed chunk is returned without a response only if no chunk succeeded.
+func (cli *Client) sendBulkChunks(ctx context.Context, bulkInputs []BulkInput, baseFilters map[string]string) (*common.Response, error) {
+	chunks := SplitBulkInputs(bulkInputs, common.MaxBulkRequestsCount)
+
+	concurrency := cli.bulkConcurrency
+	if concurrency <= 0 {
+		concurrency = common.DefaultBulkConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chunkResponses := make([]bulkResponseBody, len(chunks))
+	chunkErrors := make([]error, len(chunks))
+	semaphore := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+
+	for i := range chunks {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			chunkErrors[i] = ctx.Err()
+			break
+		}
+
+		wg.Add(1)
+		go func(chunkIndex int) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			chunkResp, err := cli.sendBulkChunk(ctx, chunks[chunkIndex], baseFilters)
+			if err != nil {
+				chunkErrors[chunkIndex] = err
+				cancel()
+				return
+			}
+			chunkResponses[chunkIndex] = chunkResp
+		}(i)
+	}
+	wg.Wait()
+
+	//the real failure is reported rather than the cancellation of the chunks which were sent after it
+	var firstErr error
+	for _, err := range chunkErrors {
+		if err == nil {
+			continue
+		}
+		if firstErr == nil || (firstErr == context.Canceled && err != context.Canceled) {
+			firstErr = err
+		}
+	}
+
+	merged := bulkResponseBody{
+		BulkItems: make([]json.RawMessage, 0, len(bulkInputs)),
+	}
+	hasStatus := false
+	chunksErr := &bulkChunksError{itemErrors: map[int]*common.ErplyError{}, err: firstErr}
+	offset := 0
+	for i, chunkResp := range chunkResponses {
+		if chunkErrors[i] != nil {
+			itemErr := getChunkItemError(chunkErrors[i])
+			for j, bulkInput := range chunks[i] {
+				chunksErr.itemErrors[offset+j] = itemErr
+				merged.BulkItems = append(merged.BulkItems, newFailedBulkItem(bulkInput, itemErr))
+			}
+			offset += len(chunks[i])
+			continue
+		}
+
+		if !hasStatus {
+			merged.Status = chunkResp.Status
+			hasStatus = true
+		} else {
+			merged.Status.GenerationTime += chunkResp.Status.GenerationTime
+		}
+		merged.BulkItems = append(merged.BulkItems, chunkResp.BulkItems...)
+		offset += len(chunks[i])
+	}
+
+	if !hasStatus {
+		return nil, firstErr
+	}
+
+	body, err := json.Marshal(merged)
+	if err != nil {
+		return nil, common.NewFromError("failed to merge bulk responses", err, 0)
+	}
+
+	resp := &common.Response{Body: body, Status: &merged.Status}
+	if firstErr != nil {
+		return resp, chunksErr
+	}
+
+	return resp, nil
+}
+
+//getChunkItemError gives the error of each sub-request of a failed or cancelled chunk
+func getChunkItemError(err error) *common.ErplyError {
+	var erplyErr *common.ErplyError
+	if errors.As(err, &erplyErr) {
+		return erplyErr
+	}
+	if errors.Is(err, context.Canceled) {
+		return common.NewFromError("bulk chunk was not sent because another chunk failed", err, 0)
+	}
+
+	return common.NewFromError("bulk chunk failed", err, 0)
+}
+
+//newFailedBulkItem gives the response of a sub-request of a failed chunk with an error status
+func newFailedBulkItem(bulkInput BulkInput, itemErr *common.ErplyError) json.RawMessage {
+	status := common.StatusBulk{RequestName: bulkInput.MethodName}
+	if requestID, ok := bulkInput.Filters["requestID"].(string); ok {
+		status.RequestID = requestID
+	}
+	status.ResponseStatus = "error"
+	status.ErrorCode = itemErr.Code
+
+	item, _ := json.Marshal(struct {
+		Status common.StatusBulk `json:"status"`
+	}{Status: status})
+
+	return item
+}
+
+func (cli *Client) sendBulkChunk(ctx context.Context, bulkInputs []BulkInput, baseFilters map[string]string) (bulkResponseBody, error) {
+	chunkResp := bulkResponseBody{}
+
+	resp, err := cli.handleRequest(ctx, &common.Request{BulkInputs: bulkInputs, Filters: baseFilters}, cli.sendBulkRequest)
+	if err != nil {
+		return chunkResp, err
+	}
+
+	if err := checkResponseStatus(getResponseStatus(resp)); err != nil {
+		return chunkResp, err
+	}
+
+	if err := json.Unmarshal(resp.Body, &chunkResp); err != nil {
+		return chunkResp, fmt.Errorf("ERPLY API: failed to unmarshal bulk response from '%s': %w", string(resp.Body), err)
+	}
+
+	if len(chunkResp.BulkItems) != len(bulkInputs) {
+		return chunkResp, fmt.Errorf(
+			"ERPLY API: bulk response has %d items for %d sub-requests",
+			len(chunkResp.BulkItems),
+			len(bulkInputs),
+		)
+	}
+
+	return chunkResp, nil
+}
@@ -0,0 +1,183 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type bulkTestResponse struct {
+	Status    common.Status `json:"status"`
+	BulkItems []struct {
+		Status  common.StatusBulk `json:"status"`
+		Records []struct {
+			ID int `json:"id"`
+		} `json:"records"`
+	} `json:"requests"`
+}
+
+//newBulkTestServer echoes the id filter of every sub-request as a record, the sub-request with the failingID gets an error status
+func newBulkTestServer(t *testing.T, failingID int, chunkSizes *[]int, maxParallel *int32) *httptest.Server {
+	var parallel int32
+	lock := sync.Mutex{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&parallel, 1)
+		defer atomic.AddInt32(&parallel, -1)
+		time.Sleep(time.Millisecond * 10)
+
+		var subRequests []map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(r.FormValue("requests")), &subRequests))
+
+		lock.Lock()
+		*chunkSizes = append(*chunkSizes, len(subRequests))
+		if current > *maxParallel {
+			*maxParallel = current
+		}
+		lock.Unlock()
+
+		if len(subRequests) > common.MaxBulkRequestsCount {
+			writeStatus(t, w, common.Status{ResponseStatus: "error", ErrorCode: common.TooManyBulkSubRequests})
+			return
+		}
+
+		items := make([]string, 0, len(subRequests))
+		for _, subRequest := range subRequests {
+			id := int(subRequest["id"].(float64))
+			if id == failingID {
+				items = append(items, `{"status":{"responseStatus":"error","errorCode":1011,"errorField":"id"},"records":[]}`)
+				continue
+			}
+			items = append(items, fmt.Sprintf(`{"status":{"responseStatus":"ok","requestName":"getProducts"},"records":[{"id":%d}]}`, id))
+		}
+		body := fmt.Sprintf(`{"status":{"responseStatus":"ok","generationTime":0.5},"requests":[%s]}`, strings.Join(items, ","))
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func getBulkTestFilters(count int) []map[string]interface{} {
+	bulkFilters := make([]map[string]interface{}, 0, count)
+	for i := 1; i <= count; i++ {
+		bulkFilters = append(bulkFilters, map[string]interface{}{"id": i})
+	}
+
+	return bulkFilters
+}
+
+func TestCallBulkSplitsOversizedRequests(t *testing.T) {
+	chunkSizes := []int{}
+	var maxParallel int32
+	srv := newBulkTestServer(t, 0, &chunkSizes, &maxParallel)
+	defer srv.Close()
+
+	constr := &ClientConstructor{}
+	constr.WithSessionKey("somesess")
+	constr.WithURL(srv.URL)
+	constr.WithBulkConcurrency(2)
+	cli := constr.Build()
+
+	resp, err := CallBulk[bulkTestResponse](context.Background(), cli, "getProducts", getBulkTestFilters(450), map[string]string{})
+	assert.NoError(t, err)
+
+	assert.ElementsMatch(t, []int{100, 100, 100, 100, 50}, chunkSizes)
+	assert.Equal(t, int32(2), maxParallel)
+
+	assert.Len(t, resp.BulkItems, 450)
+	for i, bulkItem := range resp.BulkItems {
+		assert.Equal(t, i+1, bulkItem.Records[0].ID)
+	}
+	assert.Equal(t, "ok", resp.Status.ResponseStatus)
+	assert.Equal(t, 2.5, resp.Status.GenerationTime)
+}
+
+func TestCallBulkChunksErrors(t *testing.T) {
+	chunkSizes := []int{}
+	var maxParallel int32
+	srv := newBulkTestServer(t, 150, &chunkSizes, &maxParallel)
+	defer srv.Close()
+
+	cli := NewClientWithURL("somesess", "someclient", "", srv.URL, nil, nil)
+
+	resp, err := CallBulk[bulkTestResponse](context.Background(), cli, "getProducts", getBulkTestFilters(250), map[string]string{})
+	assert.Error(t, err)
+	bulkErr, ok := err.(*common.BulkError)
+	assert.True(t, ok)
+	if ok {
+		assert.Equal(t, 250, bulkErr.TotalCount)
+		assert.Equal(t, []int{149}, bulkErr.FailedIndexes())
+		assert.Equal(t, common.InvalidClassifierID, bulkErr.Items[0].Err.Code)
+	}
+
+	//the responses of all chunks are given together with the error of the failed sub-request
+	assert.Len(t, resp.BulkItems, 250)
+	assert.Equal(t, "error", resp.BulkItems[149].Status.ResponseStatus)
+	assert.Equal(t, 151, resp.BulkItems[150].Records[0].ID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = CallBulk[bulkTestResponse](ctx, cli, "getProducts", getBulkTestFilters(250), map[string]string{})
+	assert.Error(t, err)
+}
+
+func TestCallBulkKeepsResponsesOfFinishedChunks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var subRequests []map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(r.FormValue("requests")), &subRequests))
+
+		//the second chunk fails as a whole
+		if subRequests[0]["id"].(float64) == 101 {
+			writeStatus(t, w, common.Status{ResponseStatus: "error", ErrorCode: common.InvalidValue})
+			return
+		}
+
+		items := make([]string, 0, len(subRequests))
+		for _, subRequest := range subRequests {
+			items = append(items, fmt.Sprintf(`{"status":{"responseStatus":"ok"},"records":[{"id":%d}]}`, int(subRequest["id"].(float64))))
+		}
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"status":{"responseStatus":"ok"},"requests":[%s]}`, strings.Join(items, ","))))
+	}))
+	defer srv.Close()
+
+	constr := &ClientConstructor{}
+	constr.WithSessionKey("somesess")
+	constr.WithURL(srv.URL)
+	constr.WithBulkConcurrency(1)
+	cli := constr.Build()
+
+	resp, err := CallBulk[bulkTestResponse](context.Background(), cli, "getProducts", getBulkTestFilters(250), map[string]string{})
+	bulkErr, ok := common.AsBulkError(err)
+	assert.True(t, ok)
+	if !ok {
+		return
+	}
+
+	//the records of the first chunk are given, the sub-requests of the failed and the cancelled chunks are reported
+	assert.Len(t, resp.BulkItems, 250)
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, i+1, resp.BulkItems[i].Records[0].ID)
+	}
+	assert.Equal(t, "error", resp.BulkItems[100].Status.ResponseStatus)
+	assert.Equal(t, "getProducts", resp.BulkItems[249].Status.RequestName)
+
+	assert.Equal(t, 250, bulkErr.TotalCount)
+	assert.Len(t, bulkErr.Items, 150)
+	assert.Equal(t, 100, bulkErr.Items[0].Index)
+	assert.Equal(t, common.InvalidValue, bulkErr.Items[0].Err.Code)
+	assert.Contains(t, bulkErr.Items[149].Err.Error(), "bulk chunk was not sent because another chunk failed")
+}
+
+func TestSplitBulkInputs(t *testing.T) {
+	inputs := make([]BulkInput, 5)
+	assert.Equal(t, [][]BulkInput{inputs[0:2], inputs[2:4], inputs[4:5]}, SplitBulkInputs(inputs, 2))
+	assert.Len(t, SplitBulkInputs(nil, 2), 0)
+	assert.Len(t, SplitBulkInputs(make([]BulkInput, 101), 0), 2)
+}
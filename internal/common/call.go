@@ -0,0 +1,159 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"reflect"
+)
+
+//bulkResponseStatuses is the part of every bulk API response which contains the statuses of sub-requests
+type bulkResponseStatuses struct {
+	BulkItems []struct {
+		Status common.StatusBulk `json:"status"`
+	} `json:"requests"`
+}
+
+//Call sends the request and decodes the response into T, the response body is always read and closed.
+//It fails with the API error if the response status is not ok, the decoded response is given in this case as well
+func Call[T any](ctx context.Context, cli *Client, apiMethod string, filters map[string]string) (T, error) {
+	var res T
+	resp, err := cli.handleRequest(ctx, &common.Request{Method: apiMethod, Filters: filters}, cli.sendSingleRequest)
+	if err != nil {
+		return res, err
+	}
+
+	if err := decodeResponse(resp, &res); err != nil {
+		return res, err
+	}
+
+	return res, checkResponseStatus(getResponseStatus(resp))
+}
+
+//CallBulk sends a bulk request where all sub-requests call the same API method with the given filters,
+//see CallBulkInputs
+func CallBulk[T any](ctx context.Context, cli *Client, apiMethod string, bulkFilters []map[string]interface{}, baseFilters map[string]string) (T, error) {
+	bulkInputs := make([]BulkInput, 0, len(bulkFilters))
+	for _, bulkFilterMap := range bulkFilters {
+		bulkInputs = append(bulkInputs, BulkInput{
+			MethodName: apiMethod,
+			Filters:    bulkFilterMap,
+		})
+	}
+
+	return CallBulkInputs[T](ctx, cli, bulkInputs, baseFilters)
+}
+
+//CallBulkInputs sends a bulk request and decodes the response into T, the response body is always read and closed.
+//More than MaxBulkRequestsCount sub-requests are sent in chunks in parallel, see sendBulkChunks, and T gets
+//the responses of all sub-requests in the input order.
+//It fails with the API error of the whole bulk response or with common.BulkError which has the errors
+//of all failed sub-requests, the decoded response is given in this case as well, so the results of the successful
+//sub-requests can be used
+func CallBulkInputs[T any](ctx context.Context, cli *Client, bulkInputs []BulkInput, baseFilters map[string]string) (T, error) {
+	var res T
+	if bulkInputs == nil {
+		bulkInputs = []BulkInput{}
+	}
+	if baseFilters == nil {
+		baseFilters = map[string]string{}
+	}
+
+	var resp *common.Response
+	var err error
+	if len(bulkInputs) > common.MaxBulkRequestsCount {
+		resp, err = cli.sendBulkChunks(ctx, bulkInputs, baseFilters)
+	} else {
+		resp, err = cli.handleRequest(ctx, &common.Request{BulkInputs: bulkInputs, Filters: baseFilters}, cli.sendBulkRequest)
+	}
+	var chunksErr *bulkChunksError
+	if err != nil && !errors.As(err, &chunksErr) {
+		return res, err
+	}
+
+	if err := decodeResponse(resp, &res); err != nil {
+		return res, err
+	}
+
+	if err := checkResponseStatus(getResponseStatus(resp)); err != nil {
+		return res, err
+	}
+
+	statuses := bulkResponseStatuses{}
+	if err := json.Unmarshal(resp.Body, &statuses); err != nil {
+		return res, fmt.Errorf("ERPLY API: failed to unmarshal bulk statuses from '%s': %w", string(resp.Body), err)
+	}
+
+	return res, newBulkError(bulkInputs, statuses, chunksErr)
+}
+
+//newBulkError gives common.BulkError if some sub-requests failed or nil otherwise,
+//the sub-requests of the failed chunks get the errors of their chunks
+func newBulkError(bulkInputs []BulkInput, statuses bulkResponseStatuses, chunksErr *bulkChunksError) error {
+	var itemErrors []common.BulkItemError
+	for i, bulkItem := range statuses.BulkItems {
+		if IsJSONResponseOK(&bulkItem.Status.Status) {
+			continue
+		}
+
+		requestName := bulkItem.Status.RequestName
+		if requestName == "" && i < len(bulkInputs) {
+			requestName = bulkInputs[i].MethodName
+		}
+		itemErr := common.NewFromResponseStatus(&bulkItem.Status.Status)
+		if chunksErr != nil && chunksErr.itemErrors[i] != nil {
+			itemErr = chunksErr.itemErrors[i]
+		}
+		itemErrors = append(itemErrors, common.BulkItemError{
+			Index:       i,
+			RequestID:   bulkItem.Status.RequestID,
+			RequestName: requestName,
+			Err:         itemErr,
+		})
+	}
+
+	if len(itemErrors) == 0 {
+		return nil
+	}
+
+	return &common.BulkError{Items: itemErrors, TotalCount: len(bulkInputs)}
+}
+
+func decodeResponse(resp *common.Response, dest interface{}) error {
+	if err := json.Unmarshal(resp.Body, dest); err != nil {
+		return fmt.Errorf("ERPLY API: failed to unmarshal %s from '%s': %w", getTypeName(dest), string(resp.Body), err)
+	}
+
+	return nil
+}
+
+func getResponseStatus(resp *common.Response) *common.Status {
+	if resp.Status != nil {
+		return resp.Status
+	}
+
+	return extractStatus(resp.Body)
+}
+
+func checkResponseStatus(status *common.Status) error {
+	if status == nil {
+		status = &common.Status{}
+	}
+
+	if !IsJSONResponseOK(status) {
+		return common.NewFromResponseStatus(status)
+	}
+
+	return nil
+}
+
+func getTypeName(dest interface{}) string {
+	t := reflect.TypeOf(dest)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
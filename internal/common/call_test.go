@@ -0,0 +1,148 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type callTestResponse struct {
+	Status  common.Status `json:"status"`
+	Records []struct {
+		ID int `json:"id"`
+	} `json:"records"`
+}
+
+type callTestResponseBulk struct {
+	Status    common.Status `json:"status"`
+	BulkItems []struct {
+		Status  common.StatusBulk `json:"status"`
+		Records []struct {
+			ID int `json:"id"`
+		} `json:"records"`
+	} `json:"requests"`
+}
+
+type callTestResponseWithStringTotal struct {
+	Status struct {
+		ResponseStatus string `json:"responseStatus"`
+		RecordsTotal   string `json:"recordsTotal"`
+	} `json:"status"`
+	Records []struct {
+		ID int `json:"id"`
+	} `json:"records"`
+}
+
+func newCallTestServer(t *testing.T, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+}
+
+func TestCall(t *testing.T) {
+	srv := newCallTestServer(t, `{"status":{"request":"getProducts","responseStatus":"ok"},"records":[{"id":1},{"id":2}]}`)
+	defer srv.Close()
+
+	cli := newTestClient(srv.URL, common.RetryPolicy{})
+
+	res, err := Call[callTestResponse](context.Background(), cli, "getProducts", map[string]string{})
+	assert.NoError(t, err)
+	assert.Len(t, res.Records, 2)
+	assert.Equal(t, 2, res.Records[1].ID)
+}
+
+func TestCallWithErrorStatus(t *testing.T) {
+	srv := newCallTestServer(t, `{"status":{"request":"getProducts","responseStatus":"error","errorCode":1016,"errorField":"someField"},"records":[]}`)
+	defer srv.Close()
+
+	cli := newTestClient(srv.URL, common.RetryPolicy{})
+
+	res, err := Call[callTestResponse](context.Background(), cli, "getProducts", map[string]string{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error field: someField")
+	assert.Equal(t, common.InvalidValue, res.Status.ErrorCode)
+}
+
+func TestCallWithStringRecordsTotal(t *testing.T) {
+	srv := newCallTestServer(t, `{"status":{"request":"getUserOperationsLog","responseStatus":"ok","recordsTotal":"2"},"records":[{"id":1},{"id":2}]}`)
+	defer srv.Close()
+
+	cli := newTestClient(srv.URL, common.RetryPolicy{})
+
+	res, err := Call[callTestResponseWithStringTotal](context.Background(), cli, "getUserOperationsLog", map[string]string{})
+	assert.NoError(t, err)
+	assert.Len(t, res.Records, 2)
+
+	resp, err := cli.handleRequest(context.Background(), &common.Request{Method: "getUserOperationsLog", Filters: map[string]string{}}, cli.sendSingleRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp.Status.ResponseStatus)
+	assert.Equal(t, 2, resp.Status.RecordsTotal)
+}
+
+func TestCallWithInvalidBody(t *testing.T) {
+	srv := newCallTestServer(t, `some invalid json`)
+	defer srv.Close()
+
+	cli := newTestClient(srv.URL, common.RetryPolicy{})
+
+	_, err := Call[callTestResponse](context.Background(), cli, "getProducts", map[string]string{})
+	assert.EqualError(t, err, "ERPLY API: failed to unmarshal callTestResponse from 'some invalid json': invalid character 's' looking for beginning of value")
+	var syntaxErr *json.SyntaxError
+	assert.True(t, errors.As(err, &syntaxErr))
+}
+
+func TestCallBulk(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		AssertFormValues(t, r, map[string]interface{}{
+			"someKey": "someValue",
+		})
+		_, err := w.Write([]byte(`{"status":{"responseStatus":"ok"},"requests":[{"status":{"responseStatus":"ok"},"records":[{"id":1}]},{"status":{"responseStatus":"ok"},"records":[{"id":2}]}]}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	cli := newTestClient(srv.URL, common.RetryPolicy{})
+
+	res, err := CallBulk[callTestResponseBulk](
+		context.Background(),
+		cli,
+		"getProducts",
+		[]map[string]interface{}{{"pageNo": 1}, {"pageNo": 2}},
+		map[string]string{"someKey": "someValue"},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, res.BulkItems, 2)
+	assert.Equal(t, 2, res.BulkItems[1].Records[0].ID)
+}
+
+func TestCallBulkWithFailedItem(t *testing.T) {
+	srv := newCallTestServer(
+		t,
+		`{"status":{"responseStatus":"ok"},"requests":[{"status":{"responseStatus":"ok"}},{"status":{"request":"getProducts","responseStatus":"error","errorCode":1016}}]}`,
+	)
+	defer srv.Close()
+
+	cli := newTestClient(srv.URL, common.RetryPolicy{})
+
+	res, err := CallBulk[callTestResponseBulk](context.Background(), cli, "getProducts", []map[string]interface{}{{}, {}}, nil)
+	assert.Error(t, err)
+	bulkErr, ok := err.(*common.BulkError)
+	assert.True(t, ok)
+	if ok {
+		assert.Equal(t, 2, bulkErr.TotalCount)
+		assert.Equal(t, []int{1}, bulkErr.FailedIndexes())
+		assert.Equal(t, common.InvalidValue, bulkErr.ErrorAt(1).Code)
+		assert.Equal(t, "getProducts", bulkErr.Items[0].RequestName)
+		assert.Nil(t, bulkErr.ErrorAt(0))
+	}
+
+	var erplyErr *common.ErplyError
+	assert.True(t, errors.As(err, &erplyErr))
+	assert.Len(t, res.BulkItems, 2)
+}
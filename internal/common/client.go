@@ -1,8 +1,10 @@
 package common
 
 import (
+	"github.com/erply/api-go-wrapper/pkg/api/common"
 	"net/http"
 	"net/url"
+	"sync"
 )
 
 type AuthFunc func(string) url.Values
@@ -39,6 +41,13 @@ type ClientConstructor struct {
 	httpCli                    *http.Client
 	headersForEveryRequestFunc AuthFunc
 	sessionProvider            SessionProvider
+	retryPolicy                common.RetryPolicy
+	middlewares                []common.Middleware
+	throttler                  common.Throttler
+	quotaAccountant            *common.QuotaAccountant
+	metricsCollector           common.MetricsCollector
+	tracer                     common.Tracer
+	bulkConcurrency            int
 }
 
 func (cc *ClientConstructor) Build() *Client {
@@ -54,11 +63,18 @@ func (cc *ClientConstructor) Build() *Client {
 	}
 
 	cli := &Client{
-		httpClient:      cc.httpCli,
-		sessionProvider: sessionProvider,
-		clientCode:      cc.clientCode,
-		partnerKey:      cc.partnerKey,
-		headersFunc:     cc.headersForEveryRequestFunc,
+		httpClient:       cc.httpCli,
+		sessionProvider:  sessionProvider,
+		clientCode:       cc.clientCode,
+		partnerKey:       cc.partnerKey,
+		headersFunc:      cc.headersForEveryRequestFunc,
+		retryPolicy:      cc.retryPolicy,
+		middlewares:      cc.middlewares,
+		throttler:        cc.throttler,
+		quotaAccountant:  cc.quotaAccountant,
+		metricsCollector: cc.metricsCollector,
+		tracer:           cc.tracer,
+		bulkConcurrency:  cc.bulkConcurrency,
 	}
 
 	if cli.headersFunc == nil {
@@ -104,6 +120,36 @@ func (cc *ClientConstructor) WithSessionProvider(sessProv SessionProvider) {
 	cc.sessionProvider = sessProv
 }
 
+//WithRetryPolicy enables repeating of failed requests, by default no retries are made
+func (cc *ClientConstructor) WithRetryPolicy(retryPolicy common.RetryPolicy) {
+	cc.retryPolicy = retryPolicy
+}
+
+//WithMiddlewares adds middlewares which wrap every request of the client, the first middleware is the outermost one
+func (cc *ClientConstructor) WithMiddlewares(middlewares ...common.Middleware) {
+	cc.middlewares = append(cc.middlewares, middlewares...)
+}
+
+//WithThrottler limits the rate of all requests of the client including the retries, by default requests are not limited
+func (cc *ClientConstructor) WithThrottler(throttler common.Throttler) {
+	cc.throttler = throttler
+}
+
+//WithQuotaAccountant enables counting of requests against the hourly quota of the account
+func (cc *ClientConstructor) WithQuotaAccountant(quotaAccountant *common.QuotaAccountant) {
+	cc.quotaAccountant = quotaAccountant
+}
+
+//WithMetricsCollector gives the metrics of every API call to the collector
+func (cc *ClientConstructor) WithMetricsCollector(metricsCollector common.MetricsCollector) {
+	cc.metricsCollector = metricsCollector
+}
+
+//WithTracer sets the tracer which starts a span for every API call
+func (cc *ClientConstructor) WithTracer(tracer common.Tracer) {
+	cc.tracer = tracer
+}
+
 type SessionProvider interface {
 	GetSession() (sessionKey string, err error)
 	Invalidate()
@@ -122,14 +168,28 @@ func (dsp *DefaultSessionProvider) Invalidate() {
 }
 
 type Client struct {
-	Url             string
-	httpClient      *http.Client
-	clientCode      string
-	partnerKey      string
-	headersFunc     AuthFunc
-	sessionProvider SessionProvider
+	Url              string
+	httpClient       *http.Client
+	clientCode       string
+	partnerKey       string
+	headersFunc      AuthFunc
+	sessionProvider  SessionProvider
+	retryPolicy      common.RetryPolicy
+	sessionLock      sync.Mutex
+	middlewares      []common.Middleware
+	throttler        common.Throttler
+	quotaAccountant  *common.QuotaAccountant
+	metricsCollector common.MetricsCollector
+	tracer           common.Tracer
+	bulkConcurrency  int
 }
 
 func (cli *Client) Close() {
 	cli.httpClient.CloseIdleConnections()
 }
+
+//WithBulkConcurrency limits how many chunks of an oversized bulk request are sent at the same time,
+//common.DefaultBulkConcurrency is used by default
+func (cc *ClientConstructor) WithBulkConcurrency(bulkConcurrency int) {
+	cc.bulkConcurrency = bulkConcurrency
+}
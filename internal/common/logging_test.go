@@ -0,0 +1,99 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/erply/api-go-wrapper/pkg/api/log"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type structuredLoggerMock struct {
+	messages []string
+	fields   map[string][]log.Field
+	lock     sync.Mutex
+}
+
+func (slm *structuredLoggerMock) Log(t log.Type, message string, arguments ...interface{}) {
+	slm.LogFields(t, message)
+}
+
+func (slm *structuredLoggerMock) LogFields(t log.Type, message string, fields ...log.Field) {
+	slm.lock.Lock()
+	defer slm.lock.Unlock()
+
+	slm.messages = append(slm.messages, message)
+	slm.fields[message] = fields
+}
+
+func (slm *structuredLoggerMock) Enabled(t log.Type) bool {
+	return true
+}
+
+func (slm *structuredLoggerMock) getField(message, key string) interface{} {
+	for _, field := range slm.fields[message] {
+		if field.Key == key {
+			return field.Value
+		}
+	}
+
+	return nil
+}
+
+func setTestLogger(t *testing.T) *structuredLoggerMock {
+	logger := &structuredLoggerMock{fields: map[string][]log.Field{}}
+	oldLogger := log.Log
+	log.Log = logger
+	t.Cleanup(func() {
+		log.Log = oldLogger
+	})
+
+	return logger
+}
+
+func TestRequestLogsAreStructuredAndRedacted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(t, w, common.Status{ResponseStatus: "error", ErrorCode: common.InvalidValue})
+	}))
+	defer srv.Close()
+
+	logger := setTestLogger(t)
+	cli := newTestClient(srv.URL, common.RetryPolicy{})
+
+	_, err := cli.SendRequest(context.Background(), "getProducts", map[string]string{"password": "somePass", "pageNo": "1"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "getProducts", logger.getField("will call API method", log.FieldMethod))
+	assert.Equal(t, "someclient", logger.getField("will call API method", log.FieldClientCode))
+	assert.Equal(
+		t,
+		map[string]string{"password": log.RedactedValue, "pageNo": "1"},
+		logger.getField("will call API method", log.FieldFilters),
+	)
+
+	assert.Equal(t, http.StatusOK, logger.getField("got API response", log.FieldHTTPStatus))
+	assert.Equal(t, common.InvalidValue, logger.getField("got API response", log.FieldErrorCode))
+	assert.NotNil(t, logger.getField("got API response", log.FieldDuration))
+
+	for message, fields := range logger.fields {
+		for _, field := range fields {
+			fieldStr := strings.ToLower(fmt.Sprintf("%s %s %v", message, field.Key, field.Value))
+			assert.NotContains(t, fieldStr, "somesess")
+			assert.NotContains(t, fieldStr, "somepass")
+		}
+	}
+}
+
+func TestFailedRequestErrorIsRedacted(t *testing.T) {
+	setTestLogger(t)
+	cli := newTestClient("http://localhost:1", common.RetryPolicy{})
+
+	_, err := cli.SendRequest(context.Background(), "getProducts", map[string]string{})
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "somesess")
+}
@@ -0,0 +1,117 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/erply/api-go-wrapper/pkg/api/log"
+	"time"
+)
+
+//reportResponse logs the outcome of an API call and gives its metrics to the metrics collector of the client
+//and to the one of the context
+func (cli *Client) reportResponse(ctx context.Context, request *common.Request, resp *common.Response, err error, duration time.Duration) {
+	ctxCollector := common.MetricsCollectorFromContext(ctx)
+
+	m := common.RequestMetrics{
+		Method:     getMetricsMethodName(request),
+		ClientCode: cli.getClientCode(),
+		Duration:   duration,
+		IsBulk:     request.IsBulk(),
+		Failed:     err != nil,
+	}
+	if m.IsBulk {
+		m.BulkSubRequestsCount = len(request.BulkInputs)
+	}
+
+	if err != nil {
+		if erplyErr, ok := err.(*common.ErplyError); ok {
+			m.ErrorCode = erplyErr.Code
+		}
+	}
+
+	if resp != nil {
+		if resp.HTTPResponse != nil {
+			m.HTTPStatus = resp.HTTPResponse.StatusCode
+		}
+		status := resp.Status
+		if status == nil || !IsJSONResponseOK(status) {
+			m.Failed = true
+		}
+		if status != nil {
+			m.ErrorCode = status.ErrorCode
+			m.GenerationTime = status.GenerationTime
+			m.RecordsInResponse = status.RecordsInResponse
+		}
+		if m.IsBulk && (cli.metricsCollector != nil || ctxCollector != nil) {
+			m.RecordsInResponse = countBulkRecordsInResponse(resp.Body)
+		}
+	}
+
+	logResponse(m, err)
+
+	if cli.metricsCollector != nil {
+		cli.metricsCollector.Collect(m)
+	}
+	if ctxCollector != nil {
+		ctxCollector.Collect(m)
+	}
+}
+
+func logResponse(m common.RequestMetrics, err error) {
+	fields := []log.Field{
+		log.F(log.FieldMethod, m.Method),
+		log.F(log.FieldClientCode, m.ClientCode),
+		log.F(log.FieldDuration, m.Duration),
+	}
+	if m.IsBulk {
+		fields = append(fields, log.F(log.FieldRequestsCount, m.BulkSubRequestsCount))
+	}
+	if m.ErrorCode != 0 {
+		fields = append(fields, log.F(log.FieldErrorCode, m.ErrorCode))
+	}
+
+	if err != nil {
+		log.LogFields(log.Debug, "API call failed", append(fields, log.F(log.FieldError, err))...)
+		return
+	}
+
+	fields = append(fields, log.F(log.FieldHTTPStatus, m.HTTPStatus))
+	log.LogFields(log.Debug, "got API response", fields...)
+}
+
+//getMetricsMethodName gives the API method of the request, for a bulk request it's the method of all sub-requests
+//or common.BulkMethodName if they call different methods
+func getMetricsMethodName(request *common.Request) string {
+	if !request.IsBulk() {
+		return request.Method
+	}
+
+	methodName := ""
+	for _, input := range request.BulkInputs {
+		if methodName != "" && input.MethodName != methodName {
+			return common.BulkMethodName
+		}
+		methodName = input.MethodName
+	}
+
+	if methodName == "" {
+		return common.BulkMethodName
+	}
+
+	return methodName
+}
+
+func countBulkRecordsInResponse(body []byte) int {
+	statuses := bulkResponseStatuses{}
+	if err := json.Unmarshal(body, &statuses); err != nil {
+		return 0
+	}
+
+	count := 0
+	for _, bulkItem := range statuses.BulkItems {
+		count += bulkItem.Status.RecordsInResponse
+	}
+
+	return count
+}
@@ -0,0 +1,143 @@
+package common
+
+import (
+	"context"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type metricsCollectorMock struct {
+	metrics []common.RequestMetrics
+	lock    sync.Mutex
+}
+
+func (mcm *metricsCollectorMock) Collect(m common.RequestMetrics) {
+	mcm.lock.Lock()
+	defer mcm.lock.Unlock()
+
+	mcm.metrics = append(mcm.metrics, m)
+}
+
+func newMetricsTestClient(url string, collector common.MetricsCollector) *Client {
+	constr := &ClientConstructor{}
+	constr.WithSessionKey("somesess")
+	constr.WithClientCode("someclient")
+	constr.WithURL(url)
+	constr.WithMetricsCollector(collector)
+
+	return constr.Build()
+}
+
+func TestMetricsOfSingleRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(t, w, common.Status{ResponseStatus: "ok", GenerationTime: 0.5, RecordsInResponse: 20})
+	}))
+	defer srv.Close()
+
+	collector := &metricsCollectorMock{}
+	cli := newMetricsTestClient(srv.URL, collector)
+
+	_, err := cli.SendRequest(context.Background(), "getProducts", map[string]string{})
+	assert.NoError(t, err)
+
+	assert.Len(t, collector.metrics, 1)
+	m := collector.metrics[0]
+	assert.Equal(t, "getProducts", m.Method)
+	assert.Equal(t, "someclient", m.ClientCode)
+	assert.Equal(t, http.StatusOK, m.HTTPStatus)
+	assert.False(t, m.Failed)
+	assert.False(t, m.IsBulk)
+	assert.Equal(t, 0.5, m.GenerationTime)
+	assert.Equal(t, 20, m.RecordsInResponse)
+	assert.True(t, m.Duration > 0)
+}
+
+func TestMetricsOfFailedRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(t, w, common.Status{ResponseStatus: "error", ErrorCode: common.HourlyRequestQuota})
+	}))
+	defer srv.Close()
+
+	collector := &metricsCollectorMock{}
+	cli := newMetricsTestClient(srv.URL, collector)
+
+	_, err := Call[statusResponse](context.Background(), cli, "getProducts", map[string]string{})
+	assert.Error(t, err)
+
+	assert.Len(t, collector.metrics, 1)
+	assert.True(t, collector.metrics[0].Failed)
+	assert.Equal(t, common.HourlyRequestQuota, collector.metrics[0].ErrorCode)
+}
+
+func TestMetricsOfBulkRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"status":{"responseStatus":"ok","generationTime":0.3},"requests":[` +
+			`{"status":{"responseStatus":"ok","recordsInResponse":100}},` +
+			`{"status":{"responseStatus":"ok","recordsInResponse":50}}]}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	collector := &metricsCollectorMock{}
+	cli := newMetricsTestClient(srv.URL, collector)
+
+	_, err := CallBulk[statusResponse](
+		context.Background(),
+		cli,
+		"getProducts",
+		[]map[string]interface{}{{"pageNo": 1}, {"pageNo": 2}},
+		map[string]string{},
+	)
+	assert.NoError(t, err)
+
+	_, err = CallBulkInputs[statusResponse](
+		context.Background(),
+		cli,
+		[]BulkInput{{MethodName: "getProducts"}, {MethodName: "getCustomers"}},
+		map[string]string{},
+	)
+	assert.NoError(t, err)
+
+	assert.Len(t, collector.metrics, 2)
+	m := collector.metrics[0]
+	assert.Equal(t, "getProducts", m.Method)
+	assert.True(t, m.IsBulk)
+	assert.Equal(t, 2, m.BulkSubRequestsCount)
+	assert.Equal(t, 0.3, m.GenerationTime)
+	assert.Equal(t, 150, m.RecordsInResponse)
+
+	assert.Equal(t, common.BulkMethodName, collector.metrics[1].Method)
+}
+
+func TestMetricsOfContextCollector(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"status":{"responseStatus":"ok","generationTime":0.7},"requests":[` +
+			`{"status":{"responseStatus":"ok","recordsInResponse":100}}]}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	ctxCollector := &metricsCollectorMock{}
+	cli := newMetricsTestClient(srv.URL, nil)
+
+	_, err := CallBulk[statusResponse](
+		common.WithMetricsCollector(context.Background(), ctxCollector),
+		cli,
+		"getProducts",
+		[]map[string]interface{}{{"pageNo": 1}},
+		map[string]string{},
+	)
+	assert.NoError(t, err)
+
+	_, err = cli.SendRequest(context.Background(), "getProducts", map[string]string{})
+	assert.NoError(t, err)
+
+	//only the call with the context is reported
+	assert.Len(t, ctxCollector.metrics, 1)
+	assert.Equal(t, 0.7, ctxCollector.metrics[0].GenerationTime)
+	assert.Equal(t, 100, ctxCollector.metrics[0].RecordsInResponse)
+}
@@ -0,0 +1,46 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"io/ioutil"
+	"net/http"
+)
+
+//handleRequest passes the request through the middlewares chain to the handler, the whole call is traced in one span
+func (cli *Client) handleRequest(ctx context.Context, req *common.Request, handler common.RequestHandler) (resp *common.Response, err error) {
+	if req.ClientCode == "" {
+		req.ClientCode = cli.getClientCode()
+	}
+	ctx, span := cli.startSpan(ctx, req)
+	defer func() {
+		endSpan(span, req, resp, err)
+	}()
+
+	resp, err = common.ChainMiddlewares(handler, cli.middlewares...)(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp == nil {
+		return nil, common.NewErplyError("Error", "no response was given by the middlewares chain", 0)
+	}
+
+	return resp, nil
+}
+
+//toHTTPResponse converts the result of the middlewares chain to a http response with a readable body
+func toHTTPResponse(resp *common.Response) *http.Response {
+	httpResp := resp.HTTPResponse
+	if httpResp == nil {
+		httpResp = &http.Response{
+			Status:     http.StatusText(http.StatusOK),
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+		}
+	}
+	httpResp.Body = ioutil.NopCloser(bytes.NewReader(resp.Body))
+
+	return httpResp
+}
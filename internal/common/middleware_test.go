@@ -0,0 +1,143 @@
+package common
+
+import (
+	"context"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func newMiddlewareTestClient(url string, middlewares ...common.Middleware) *Client {
+	constr := &ClientConstructor{}
+	constr.WithSessionKey("somesess")
+	constr.WithClientCode("someclient")
+	constr.WithURL(url)
+	constr.WithMiddlewares(middlewares...)
+
+	return constr.Build()
+}
+
+func TestMiddlewaresChain(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		AssertFormValues(t, r, map[string]interface{}{
+			"request":  "getProducts",
+			"someKey":  "someValue",
+			"addedKey": "addedValue",
+		})
+		writeStatus(t, w, common.Status{ResponseStatus: "ok", RecordsTotal: 10})
+	}))
+	defer srv.Close()
+
+	calls := make([]string, 0)
+	var actualStatus *common.Status
+	outerMiddleware := common.MiddlewareFunc(func(next common.RequestHandler) common.RequestHandler {
+		return func(ctx context.Context, req *common.Request) (*common.Response, error) {
+			calls = append(calls, "outer before "+req.Method)
+			resp, err := next(ctx, req)
+			calls = append(calls, "outer after")
+			actualStatus = resp.Status
+			return resp, err
+		}
+	})
+	innerMiddleware := common.MiddlewareFunc(func(next common.RequestHandler) common.RequestHandler {
+		return func(ctx context.Context, req *common.Request) (*common.Response, error) {
+			calls = append(calls, "inner before")
+			assert.False(t, req.IsBulk())
+			assert.Equal(t, "someclient", req.ClientCode)
+			assert.Equal(t, "someValue", req.Filters["someKey"])
+			req.Filters["addedKey"] = "addedValue"
+			resp, err := next(ctx, req)
+			calls = append(calls, "inner after")
+			return resp, err
+		}
+	})
+
+	cli := newMiddlewareTestClient(srv.URL, outerMiddleware, innerMiddleware)
+
+	dest := &statusResponse{}
+	err := cli.Scan(context.Background(), "getProducts", map[string]string{"someKey": "someValue"}, dest)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, dest.Status.RecordsTotal)
+
+	assert.Equal(t, []string{"outer before getProducts", "inner before", "inner after", "outer after"}, calls)
+	assert.NotNil(t, actualStatus)
+	if actualStatus != nil {
+		assert.Equal(t, 10, actualStatus.RecordsTotal)
+	}
+}
+
+func TestMiddlewareSeesBulkInputs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(t, w, common.Status{ResponseStatus: "ok"})
+	}))
+	defer srv.Close()
+
+	var actualRequest *common.Request
+	var actualResponse *common.Response
+	cli := newMiddlewareTestClient(srv.URL, common.MiddlewareFunc(func(next common.RequestHandler) common.RequestHandler {
+		return func(ctx context.Context, req *common.Request) (*common.Response, error) {
+			actualRequest = req
+			resp, err := next(ctx, req)
+			actualResponse = resp
+			return resp, err
+		}
+	}))
+
+	_, err := cli.SendRequestBulk(
+		context.Background(),
+		[]BulkInput{
+			{
+				MethodName: "getProducts",
+				Filters:    map[string]interface{}{"pageNo": 1},
+			},
+		},
+		map[string]string{"someKey": "someValue"},
+	)
+	assert.NoError(t, err)
+
+	assert.True(t, actualRequest.IsBulk())
+	assert.Equal(t, "", actualRequest.Method)
+	assert.Equal(t, "getProducts", actualRequest.BulkInputs[0].MethodName)
+	assert.Equal(t, map[string]string{"someKey": "someValue"}, actualRequest.Filters)
+	assert.Equal(t, http.StatusOK, actualResponse.HTTPResponse.StatusCode)
+	assert.Contains(t, string(actualResponse.Body), `"responseStatus":"ok"`)
+}
+
+func TestMiddlewareCanReplaceTransport(t *testing.T) {
+	cli := newMiddlewareTestClient("http://localhost:1", common.MiddlewareFunc(func(next common.RequestHandler) common.RequestHandler {
+		return func(ctx context.Context, req *common.Request) (*common.Response, error) {
+			return &common.Response{
+				Body: []byte(`{"status":{"responseStatus":"error","errorCode":1016}}`),
+			}, nil
+		}
+	}))
+
+	dest := &statusResponse{}
+	err := cli.Scan(context.Background(), "getProducts", map[string]string{}, dest)
+	assert.Error(t, err)
+	assert.Equal(t, common.InvalidValue, dest.Status.ErrorCode)
+}
+
+func TestMiddlewareSeesClientCodeOfHeaders(t *testing.T) {
+	var actualClientCode string
+	constr := &ClientConstructor{}
+	constr.WithURL("http://localhost:1")
+	constr.WithHeaderFunc(func(requestName string) url.Values {
+		return url.Values{"clientCode": {"headerclient"}, "sessionKey": {"somesess"}}
+	})
+	constr.WithMiddlewares(common.MiddlewareFunc(func(next common.RequestHandler) common.RequestHandler {
+		return func(ctx context.Context, req *common.Request) (*common.Response, error) {
+			actualClientCode = req.ClientCode
+			return &common.Response{Body: []byte(`{"status":{"responseStatus":"ok"}}`)}, nil
+		}
+	}))
+	cli := constr.Build()
+
+	dest := &statusResponse{}
+	err := cli.Scan(context.Background(), "getProducts", map[string]string{}, dest)
+	assert.NoError(t, err)
+	assert.Equal(t, "headerclient", actualClientCode)
+}
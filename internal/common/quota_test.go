@@ -0,0 +1,66 @@
+package common
+
+import (
+	"context"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientRequestsAreCountedByQuotaAccountant(t *testing.T) {
+	calledTimes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calledTimes++
+		writeStatus(t, w, common.Status{ResponseStatus: "ok"})
+	}))
+	defer srv.Close()
+
+	qa := common.NewQuotaAccountant(5, common.QuotaModeFailFast)
+	qa.CountBulkSubRequests = true
+
+	constr := &ClientConstructor{}
+	constr.WithSessionKey("somesess")
+	constr.WithClientCode("someclient")
+	constr.WithURL(srv.URL)
+	constr.WithQuotaAccountant(qa)
+	cli := constr.Build()
+
+	_, err := cli.SendRequest(context.Background(), "getProducts", map[string]string{})
+	assert.NoError(t, err)
+
+	_, err = cli.SendRequestBulk(
+		context.Background(),
+		[]BulkInput{
+			{MethodName: "getProducts"},
+			{MethodName: "getProducts"},
+			{MethodName: "getProducts"},
+		},
+		map[string]string{},
+	)
+	assert.NoError(t, err)
+
+	remaining, isTracked := cli.GetRemainingRequestsQuota()
+	assert.True(t, isTracked)
+	assert.Equal(t, 1, remaining)
+	assert.Equal(t, 4, qa.Used("someclient"))
+
+	_, err = cli.SendRequestBulk(
+		context.Background(),
+		[]BulkInput{
+			{MethodName: "getProducts"},
+			{MethodName: "getProducts"},
+		},
+		map[string]string{},
+	)
+	assert.Error(t, err)
+	assert.Equal(t, 2, calledTimes)
+}
+
+func TestClientWithoutQuotaAccountant(t *testing.T) {
+	cli := NewClient("somesess", "someclient", "", nil, nil)
+
+	_, isTracked := cli.GetRemainingRequestsQuota()
+	assert.False(t, isTracked)
+}
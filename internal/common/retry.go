@@ -0,0 +1,198 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/erply/api-go-wrapper/pkg/api/log"
+	"io/ioutil"
+	"net/http"
+)
+
+//requestBuilder creates a new request for each attempt, it gives the session key which was used for the request
+type requestBuilder func() (req *http.Request, sessionKey string, err error)
+
+//responseWithStatus is the part of every API response which is needed to understand if the request should be repeated
+type responseWithStatus struct {
+	Status common.Status `json:"status"`
+}
+
+//sendWithRetry executes requests created by buildRequest until it gets a non retryable response or the retry policy
+//gives up. The request is built for each attempt again, so the payload is replayed safely.
+//The returned response body is fully buffered, so it can be read after the connection is released.
+//Each attempt is counted by the quota accountant as bulkSubRequestsCount requests, which is 0 for non bulk calls.
+func (cli *Client) sendWithRetry(ctx context.Context, failureMsg string, bulkSubRequestsCount int, buildRequest requestBuilder) (*common.Response, error) {
+	attempt := 0
+	sessionWasRenewed := false
+	for {
+		attempt++
+
+		if cli.throttler != nil {
+			if err := cli.throttler.Throttle(ctx); err != nil {
+				return nil, common.NewFromError(failureMsg+", failed to wait for the requests throttler", err, 0)
+			}
+		}
+
+		if cli.quotaAccountant != nil {
+			requestsCount := cli.quotaAccountant.RequestsCount(bulkSubRequestsCount)
+			if err := cli.quotaAccountant.Acquire(ctx, cli.getClientCode(), requestsCount); err != nil {
+				return nil, err
+			}
+		}
+
+		req, usedSessionKey, err := buildRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		var resp *common.Response
+		httpResp, err := doRequest(req, cli)
+		if err != nil {
+			err = common.NewFromError(failureMsg, log.RedactError(err), 0)
+			if !cli.shouldRetry(ctx, attempt) {
+				return nil, err
+			}
+			log.LogFields(
+				log.Warn,
+				"request failed, will retry",
+				log.F(log.FieldClientCode, cli.getClientCode()),
+				log.F(log.FieldError, err),
+				log.F(log.FieldAttempt, attempt),
+				log.F(log.FieldMaxAttempts, cli.retryPolicy.MaxAttempts),
+			)
+			common.SpanFromContext(ctx).AddEvent(
+				common.EventRetry,
+				common.Attr(common.AttrAttempt, attempt),
+				common.Attr(common.AttrError, err.Error()),
+			)
+		} else {
+			var bufferErr error
+			resp, bufferErr = bufferResponse(httpResp)
+			if bufferErr != nil {
+				return nil, common.NewFromError(failureMsg, bufferErr, 0)
+			}
+			status := resp.Status
+
+			if !sessionWasRenewed && isSessionError(status) && cli.canRenewSession() {
+				log.LogFields(
+					log.Info,
+					"session is not valid anymore, will renew it and replay the request",
+					log.F(log.FieldClientCode, cli.getClientCode()),
+					log.F(log.FieldErrorCode, status.ErrorCode),
+				)
+				common.SpanFromContext(ctx).AddEvent(
+					common.EventSessionRenewal,
+					common.Attr(common.AttrErrorCode, int(status.ErrorCode)),
+				)
+				if err := cli.renewSession(usedSessionKey); err != nil {
+					return nil, common.NewFromError("failed to renew the expired session", err, status.ErrorCode)
+				}
+				sessionWasRenewed = true
+				attempt--
+				continue
+			}
+
+			if !cli.isRetryableResponse(resp, status) || !cli.shouldRetry(ctx, attempt) {
+				return resp, nil
+			}
+
+			var errCode common.ApiError
+			if status != nil {
+				errCode = status.ErrorCode
+			}
+			log.LogFields(
+				log.Warn,
+				failureMsg+", will retry",
+				log.F(log.FieldClientCode, cli.getClientCode()),
+				log.F(log.FieldHTTPStatus, httpResp.StatusCode),
+				log.F(log.FieldErrorCode, errCode),
+				log.F(log.FieldAttempt, attempt),
+				log.F(log.FieldMaxAttempts, cli.retryPolicy.MaxAttempts),
+			)
+			common.SpanFromContext(ctx).AddEvent(
+				common.EventRetry,
+				common.Attr(common.AttrAttempt, attempt),
+				common.Attr(common.AttrHTTPStatusCode, httpResp.StatusCode),
+				common.Attr(common.AttrErrorCode, int(errCode)),
+			)
+		}
+
+		if waitErr := cli.retryPolicy.Wait(ctx, attempt); waitErr != nil {
+			log.LogFields(log.Warn, "stopped retrying", log.F(log.FieldError, waitErr))
+			return resp, err
+		}
+	}
+}
+
+func (cli *Client) shouldRetry(ctx context.Context, attempt int) bool {
+	if !cli.retryPolicy.IsEnabled() || attempt >= cli.retryPolicy.MaxAttempts {
+		return false
+	}
+
+	return ctx.Err() == nil
+}
+
+func (cli *Client) isRetryableResponse(resp *common.Response, status *common.Status) bool {
+	if cli.retryPolicy.IsRetryableHTTPStatus(resp.HTTPResponse.StatusCode) {
+		return true
+	}
+
+	if status == nil || IsJSONResponseOK(status) {
+		return false
+	}
+
+	return cli.retryPolicy.IsRetryableCode(status.ErrorCode)
+}
+
+//bufferResponse reads and closes the response body replacing it with an in-memory copy, the status of the
+//returned response is nil if the body has no valid JSON status
+func bufferResponse(httpResp *http.Response) (*common.Response, error) {
+	resp := &common.Response{HTTPResponse: httpResp}
+	if httpResp.Body == nil {
+		httpResp.Body = ioutil.NopCloser(bytes.NewReader(nil))
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(httpResp.Body)
+	closeErr := httpResp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if closeErr != nil {
+		log.LogFields(log.Warn, "failed to close response body", log.F(log.FieldError, closeErr))
+	}
+
+	httpResp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	resp.Body = body
+	resp.Status = extractStatus(body)
+
+	return resp, nil
+}
+
+//responseWithStringTotal is the status of the API methods which give recordsTotal as a string, e.g. getUserOperationsLog
+type responseWithStringTotal struct {
+	Status struct {
+		common.Status
+		RecordsTotal json.Number `json:"recordsTotal"`
+	} `json:"status"`
+}
+
+func extractStatus(body []byte) *common.Status {
+	respWithStatus := responseWithStatus{}
+	if err := json.Unmarshal(body, &respWithStatus); err == nil {
+		return &respWithStatus.Status
+	}
+
+	respWithStringTotal := responseWithStringTotal{}
+	if err := json.Unmarshal(body, &respWithStringTotal); err != nil {
+		return nil
+	}
+
+	status := respWithStringTotal.Status.Status
+	if total, err := respWithStringTotal.Status.RecordsTotal.Int64(); err == nil {
+		status.RecordsTotal = int(total)
+	}
+
+	return &status
+}
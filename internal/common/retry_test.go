@@ -0,0 +1,215 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func getTestRetryPolicy() common.RetryPolicy {
+	return common.RetryPolicy{
+		MaxAttempts:     3,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond * 5,
+		Multiplier:      2,
+		RetryableCodes:  common.DefaultRetryableCodes,
+	}
+}
+
+func writeStatus(t *testing.T, w http.ResponseWriter, status common.Status) {
+	jsonRaw, err := json.Marshal(map[string]interface{}{
+		"status": status,
+	})
+	assert.NoError(t, err)
+
+	_, err = w.Write(jsonRaw)
+	assert.NoError(t, err)
+}
+
+type statusResponse struct {
+	Status common.Status `json:"status"`
+}
+
+func (sr *statusResponse) GetStatus() *common.Status {
+	return &sr.Status
+}
+
+func newTestClient(url string, retryPolicy common.RetryPolicy) *Client {
+	constr := &ClientConstructor{}
+	constr.WithSessionKey("somesess")
+	constr.WithClientCode("someclient")
+	constr.WithURL(url)
+	constr.WithRetryPolicy(retryPolicy)
+
+	return constr.Build()
+}
+
+func TestSendRequestRetriesRetryableCodes(t *testing.T) {
+	calledTimes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calledTimes++
+		if calledTimes < 3 {
+			writeStatus(t, w, common.Status{ResponseStatus: "error", ErrorCode: common.ServerMaintenance})
+			return
+		}
+		writeStatus(t, w, common.Status{ResponseStatus: "ok"})
+	}))
+	defer srv.Close()
+
+	cli := newTestClient(srv.URL, getTestRetryPolicy())
+
+	resp, err := cli.SendRequest(context.Background(), "getProducts", map[string]string{})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, 3, calledTimes)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `"responseStatus":"ok"`)
+}
+
+func TestSendRequestGivesUpAfterMaxAttempts(t *testing.T) {
+	calledTimes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calledTimes++
+		writeStatus(t, w, common.Status{ResponseStatus: "error", ErrorCode: common.HourlyRequestQuota})
+	}))
+	defer srv.Close()
+
+	cli := newTestClient(srv.URL, getTestRetryPolicy())
+
+	dest := &statusResponse{}
+	err := cli.Scan(context.Background(), "getProducts", map[string]string{}, dest)
+	assert.Error(t, err)
+	assert.Equal(t, 3, calledTimes)
+	assert.Equal(t, common.HourlyRequestQuota, dest.Status.ErrorCode)
+}
+
+func TestSendRequestDoesNotRetryOtherCodes(t *testing.T) {
+	calledTimes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calledTimes++
+		writeStatus(t, w, common.Status{ResponseStatus: "error", ErrorCode: common.MalformedRequest})
+	}))
+	defer srv.Close()
+
+	cli := newTestClient(srv.URL, getTestRetryPolicy())
+
+	_, err := cli.SendRequest(context.Background(), "getProducts", map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calledTimes)
+}
+
+func TestSendRequestWithoutRetryPolicy(t *testing.T) {
+	calledTimes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calledTimes++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	cli := newTestClient(srv.URL, common.RetryPolicy{})
+
+	resp, err := cli.SendRequest(context.Background(), "getProducts", map[string]string{})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, 1, calledTimes)
+}
+
+func TestSendRequestBulkReplaysPayload(t *testing.T) {
+	calledTimes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calledTimes++
+
+		AssertFormValues(t, r, map[string]interface{}{
+			"clientCode": "someclient",
+			"sessionKey": "somesess",
+			"someKey":    "someValue",
+		})
+
+		AssertRequestBulk(t, r, []map[string]interface{}{
+			{
+				"requestName": "getSuppliers",
+				"pageNo":      "1",
+			},
+		})
+
+		if calledTimes == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeStatus(t, w, common.Status{ResponseStatus: "ok"})
+	}))
+	defer srv.Close()
+
+	cli := newTestClient(srv.URL, getTestRetryPolicy())
+
+	bulkFilters := map[string]interface{}{"pageNo": "1"}
+	baseFilters := map[string]string{"someKey": "someValue"}
+	resp, err := cli.SendRequestBulk(
+		context.Background(),
+		[]BulkInput{
+			{
+				MethodName: "getSuppliers",
+				Filters:    bulkFilters,
+			},
+		},
+		baseFilters,
+	)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, calledTimes)
+	assert.Equal(t, map[string]interface{}{"pageNo": "1"}, bulkFilters)
+	assert.Equal(t, map[string]string{"someKey": "someValue"}, baseFilters)
+}
+
+func TestSendRequestStopsRetryingOnContextDeadline(t *testing.T) {
+	calledTimes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calledTimes++
+		writeStatus(t, w, common.Status{ResponseStatus: "error", ErrorCode: common.AccountDbConnError})
+	}))
+	defer srv.Close()
+
+	retryPolicy := getTestRetryPolicy()
+	retryPolicy.InitialInterval = time.Minute
+	retryPolicy.MaxInterval = time.Minute
+	cli := newTestClient(srv.URL, retryPolicy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	resp, err := cli.SendRequest(ctx, "getProducts", map[string]string{})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, 1, calledTimes)
+
+	status := extractStatusFromResponse(t, resp)
+	assert.Equal(t, common.AccountDbConnError, status.ErrorCode)
+}
+
+func extractStatusFromResponse(t *testing.T, resp *http.Response) common.Status {
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	res := responseWithStatus{}
+	assert.NoError(t, json.Unmarshal(body, &res))
+
+	return res.Status
+}
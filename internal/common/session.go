@@ -0,0 +1,56 @@
+package common
+
+import (
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/erply/api-go-wrapper/pkg/api/log"
+)
+
+//sessionErrorCodes are API errors which mean that a new session key should be requested
+var sessionErrorCodes = []common.ApiError{
+	common.APISessionExpired,
+	common.InvalidSession,
+	common.SessionTooOld,
+}
+
+func isSessionError(status *common.Status) bool {
+	if status == nil || IsJSONResponseOK(status) {
+		return false
+	}
+
+	for _, code := range sessionErrorCodes {
+		if status.ErrorCode == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+//canRenewSession tells if the session provider is able to give a new session key after invalidation,
+//the default provider holds a static key so it cannot renew it
+func (cli *Client) canRenewSession() bool {
+	_, isStatic := cli.sessionProvider.(*DefaultSessionProvider)
+	return !isStatic
+}
+
+//renewSession invalidates the session key which was used for a failed request and fetches a new one,
+//if a concurrent request has already renewed the session, the new key is kept, so only one re-login happens
+func (cli *Client) renewSession(usedSessionKey string) error {
+	cli.sessionLock.Lock()
+	defer cli.sessionLock.Unlock()
+
+	currentSessionKey, err := cli.sessionProvider.GetSession()
+	if err != nil {
+		return err
+	}
+
+	if currentSessionKey != usedSessionKey {
+		log.LogFields(log.Debug, "session was already renewed by a concurrent request", log.F(log.FieldClientCode, cli.getClientCode()))
+		return nil
+	}
+
+	cli.sessionProvider.Invalidate()
+
+	_, err = cli.sessionProvider.GetSession()
+	return err
+}
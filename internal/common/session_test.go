@@ -0,0 +1,173 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type sessionProviderMock struct {
+	sessionKey  string
+	loginsCount int
+	lock        sync.Mutex
+}
+
+func (spm *sessionProviderMock) GetSession() (sessionKey string, err error) {
+	spm.lock.Lock()
+	defer spm.lock.Unlock()
+
+	if spm.sessionKey == "" {
+		spm.loginsCount++
+		spm.sessionKey = fmt.Sprintf("sess%d", spm.loginsCount)
+	}
+
+	return spm.sessionKey, nil
+}
+
+func (spm *sessionProviderMock) Invalidate() {
+	spm.lock.Lock()
+	defer spm.lock.Unlock()
+
+	spm.sessionKey = ""
+}
+
+func newSessionTestServer(t *testing.T, validSessionKey string, errCode common.ApiError, calledTimes *int, lock *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		*calledTimes++
+		lock.Unlock()
+
+		if r.FormValue("sessionKey") != validSessionKey {
+			writeStatus(t, w, common.Status{ResponseStatus: "error", ErrorCode: errCode})
+			return
+		}
+		writeStatus(t, w, common.Status{ResponseStatus: "ok"})
+	}))
+}
+
+func newSessionTestClient(url string, sessionProvider SessionProvider) *Client {
+	constr := &ClientConstructor{}
+	constr.WithClientCode("someclient")
+	constr.WithURL(url)
+	constr.WithSessionProvider(sessionProvider)
+
+	return constr.Build()
+}
+
+func TestSessionIsRenewedOnExpiration(t *testing.T) {
+	testCases := []common.ApiError{
+		common.APISessionExpired,
+		common.InvalidSession,
+		common.SessionTooOld,
+	}
+
+	for _, errCode := range testCases {
+		t.Run(errCode.String(), func(t *testing.T) {
+			calledTimes := 0
+			srv := newSessionTestServer(t, "sess2", errCode, &calledTimes, &sync.Mutex{})
+			defer srv.Close()
+
+			sessionProvider := &sessionProviderMock{}
+			cli := newSessionTestClient(srv.URL, sessionProvider)
+
+			dest := &statusResponse{}
+			err := cli.Scan(context.Background(), "getProducts", map[string]string{}, dest)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, calledTimes)
+			assert.Equal(t, 2, sessionProvider.loginsCount)
+		})
+	}
+}
+
+func TestSessionIsRenewedOnlyOnce(t *testing.T) {
+	calledTimes := 0
+	srv := newSessionTestServer(t, "someInvalidSession", common.InvalidSession, &calledTimes, &sync.Mutex{})
+	defer srv.Close()
+
+	sessionProvider := &sessionProviderMock{}
+	cli := newSessionTestClient(srv.URL, sessionProvider)
+
+	dest := &statusResponse{}
+	err := cli.Scan(context.Background(), "getProducts", map[string]string{}, dest)
+	assert.Error(t, err)
+	assert.Equal(t, common.InvalidSession, dest.Status.ErrorCode)
+	assert.Equal(t, 2, calledTimes)
+	assert.Equal(t, 2, sessionProvider.loginsCount)
+}
+
+func TestSessionIsRenewedForBulkRequest(t *testing.T) {
+	calledTimes := 0
+	srv := newSessionTestServer(t, "sess2", common.APISessionExpired, &calledTimes, &sync.Mutex{})
+	defer srv.Close()
+
+	sessionProvider := &sessionProviderMock{}
+	cli := newSessionTestClient(srv.URL, sessionProvider)
+
+	resp, err := cli.SendRequestBulk(
+		context.Background(),
+		[]BulkInput{
+			{
+				MethodName: "getProducts",
+				Filters:    map[string]interface{}{"pageNo": 1},
+			},
+		},
+		map[string]string{},
+	)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+
+	assert.Equal(t, 2, calledTimes)
+	status := extractStatusFromResponse(t, resp)
+	assert.Equal(t, "ok", status.ResponseStatus)
+}
+
+func TestConcurrentSessionRenewalLogsInOnce(t *testing.T) {
+	calledTimes := 0
+	srv := newSessionTestServer(t, "sess2", common.APISessionExpired, &calledTimes, &sync.Mutex{})
+	defer srv.Close()
+
+	sessionProvider := &sessionProviderMock{}
+	_, err := sessionProvider.GetSession()
+	assert.NoError(t, err)
+
+	cli := newSessionTestClient(srv.URL, sessionProvider)
+
+	const requestsCount = 10
+	wg := sync.WaitGroup{}
+	wg.Add(requestsCount)
+	for i := 0; i < requestsCount; i++ {
+		go func() {
+			defer wg.Done()
+			dest := &statusResponse{}
+			err := cli.Scan(context.Background(), "getProducts", map[string]string{}, dest)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, sessionProvider.loginsCount)
+}
+
+func TestStaticSessionIsNotRenewed(t *testing.T) {
+	calledTimes := 0
+	srv := newSessionTestServer(t, "sess2", common.APISessionExpired, &calledTimes, &sync.Mutex{})
+	defer srv.Close()
+
+	cli := NewClientWithURL("sess1", "someclient", "", srv.URL, nil, nil)
+
+	dest := &statusResponse{}
+	err := cli.Scan(context.Background(), "getProducts", map[string]string{}, dest)
+	assert.Error(t, err)
+	assert.Equal(t, 1, calledTimes)
+
+	sessionKey, err := cli.GetSession()
+	assert.NoError(t, err)
+	assert.Equal(t, "sess1", sessionKey)
+}
@@ -0,0 +1,84 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newThrottlingTestClient(url string, throttler common.Throttler, retryPolicy common.RetryPolicy) *Client {
+	constr := &ClientConstructor{}
+	constr.WithSessionKey("somesess")
+	constr.WithClientCode("someclient")
+	constr.WithURL(url)
+	constr.WithThrottler(throttler)
+	constr.WithRetryPolicy(retryPolicy)
+
+	return constr.Build()
+}
+
+func TestClientRequestsAreThrottled(t *testing.T) {
+	calledTimes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calledTimes++
+		writeStatus(t, w, common.Status{ResponseStatus: "ok"})
+	}))
+	defer srv.Close()
+
+	throttledCount := 0
+	sleeper := func(sleepTime time.Duration) {
+		throttledCount++
+	}
+	cli := newThrottlingTestClient(srv.URL, common.NewTokenBucketThrottler(1, 2).WithSleeper(sleeper), common.RetryPolicy{})
+
+	for i := 0; i < 3; i++ {
+		_, err := cli.SendRequest(context.Background(), "getProducts", map[string]string{})
+		assert.NoError(t, err)
+	}
+	_, err := cli.SendRequestBulk(context.Background(), []BulkInput{{MethodName: "getProducts"}}, map[string]string{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 4, calledTimes)
+	assert.Equal(t, 2, throttledCount)
+}
+
+func TestClientRetriesAreThrottled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	throttledCount := 0
+	sleeper := func(sleepTime time.Duration) {
+		throttledCount++
+	}
+	cli := newThrottlingTestClient(srv.URL, common.NewTokenBucketThrottler(1, 1).WithSleeper(sleeper), getTestRetryPolicy())
+
+	_, err := cli.SendRequest(context.Background(), "getProducts", map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, throttledCount)
+}
+
+func TestClientThrottlingError(t *testing.T) {
+	calledTimes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calledTimes++
+	}))
+	defer srv.Close()
+
+	throttler := &common.ThrottlerMock{ErrToGive: errors.New("some throttling error")}
+	cli := newThrottlingTestClient(srv.URL, throttler, common.RetryPolicy{})
+
+	_, err := cli.SendRequest(context.Background(), "getProducts", map[string]string{})
+	assert.Error(t, err)
+	if err != nil {
+		assert.Contains(t, err.Error(), "some throttling error")
+	}
+	assert.True(t, throttler.WasTriggered)
+	assert.Equal(t, 0, calledTimes)
+}
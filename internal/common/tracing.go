@@ -0,0 +1,110 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+)
+
+//startSpan starts the span of an API call, the returned context carries the span, so the middlewares, retries and
+//http transport can attach their data to it
+func (cli *Client) startSpan(ctx context.Context, req *common.Request) (context.Context, common.Span) {
+	if cli.tracer == nil {
+		return ctx, common.NoopSpan{}
+	}
+
+	spanName := common.SpanNameRequest
+	attrs := []common.Attribute{
+		common.Attr(common.AttrMethod, getMetricsMethodName(req)),
+		common.Attr(common.AttrClientCode, cli.getClientCode()),
+	}
+	if req.IsBulk() {
+		spanName = common.SpanNameBulkRequest
+		attrs = append(attrs, common.Attr(common.AttrBulkSubRequests, len(req.BulkInputs)))
+	}
+
+	ctx, span := cli.tracer.Start(ctx, spanName, attrs...)
+
+	return common.ContextWithSpan(ctx, span), span
+}
+
+//endSpan records the outcome of an API call in its span and ends it
+func endSpan(span common.Span, req *common.Request, resp *common.Response, err error) {
+	defer span.End()
+
+	if err != nil {
+		if erplyErr, ok := err.(*common.ErplyError); ok && erplyErr.Code != 0 {
+			span.SetAttributes(common.Attr(common.AttrErrorCode, int(erplyErr.Code)))
+		}
+		span.RecordError(err)
+		span.SetStatus(common.SpanStatusError, err.Error())
+		return
+	}
+
+	if resp == nil {
+		return
+	}
+
+	if resp.HTTPResponse != nil {
+		span.SetAttributes(common.Attr(common.AttrHTTPStatusCode, resp.HTTPResponse.StatusCode))
+	}
+
+	status := resp.Status
+	if status != nil {
+		span.SetAttributes(
+			common.Attr(common.AttrResponseStatus, status.ResponseStatus),
+			common.Attr(common.AttrGenerationTime, status.GenerationTime),
+			common.Attr(common.AttrRecordsTotal, status.RecordsTotal),
+			common.Attr(common.AttrRecordsInResp, status.RecordsInResponse),
+		)
+	}
+
+	if req.IsBulk() {
+		addBulkSubRequestEvents(span, req, resp.Body)
+	}
+
+	if status == nil || !IsJSONResponseOK(status) {
+		description := "no status in API response"
+		if status != nil {
+			span.SetAttributes(common.Attr(common.AttrErrorCode, int(status.ErrorCode)))
+			description = common.NewFromResponseStatus(status).Error()
+		}
+		span.SetStatus(common.SpanStatusError, description)
+		return
+	}
+
+	span.SetStatus(common.SpanStatusOK, "")
+}
+
+//addBulkSubRequestEvents adds an event with the status of each bulk sub-request to the span
+func addBulkSubRequestEvents(span common.Span, req *common.Request, body []byte) {
+	statuses := bulkResponseStatuses{}
+	if err := json.Unmarshal(body, &statuses); err != nil {
+		return
+	}
+
+	for i, bulkItem := range statuses.BulkItems {
+		requestName := bulkItem.Status.RequestName
+		if requestName == "" && i < len(req.BulkInputs) {
+			requestName = req.BulkInputs[i].MethodName
+		}
+
+		attrs := []common.Attribute{
+			common.Attr(common.AttrSubRequestIndex, i),
+			common.Attr(common.AttrRequestName, requestName),
+			common.Attr(common.AttrRequestID, bulkItem.Status.RequestID),
+			common.Attr(common.AttrResponseStatus, bulkItem.Status.ResponseStatus),
+			common.Attr(common.AttrGenerationTime, bulkItem.Status.GenerationTime),
+			common.Attr(common.AttrRecordsInResp, bulkItem.Status.RecordsInResponse),
+		}
+		if bulkItem.Status.ErrorCode != 0 {
+			attrs = append(
+				attrs,
+				common.Attr(common.AttrErrorCode, int(bulkItem.Status.ErrorCode)),
+				common.Attr(common.AttrErrorField, bulkItem.Status.ErrorField),
+			)
+		}
+
+		span.AddEvent(common.EventBulkSubRequest, attrs...)
+	}
+}
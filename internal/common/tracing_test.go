@@ -0,0 +1,241 @@
+package common
+
+import (
+	"context"
+	"github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type parentSpanKey struct{}
+
+type spanEventMock struct {
+	name  string
+	attrs map[string]interface{}
+}
+
+type spanMock struct {
+	name        string
+	parent      string
+	attrs       map[string]interface{}
+	events      []spanEventMock
+	errors      []error
+	status      common.SpanStatusCode
+	description string
+	ended       bool
+	lock        sync.Mutex
+}
+
+func toAttrsMap(attrs []common.Attribute) map[string]interface{} {
+	res := make(map[string]interface{}, len(attrs))
+	for _, attr := range attrs {
+		res[attr.Key] = attr.Value
+	}
+	return res
+}
+
+func (sm *spanMock) SetAttributes(attrs ...common.Attribute) {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+	for k, v := range toAttrsMap(attrs) {
+		sm.attrs[k] = v
+	}
+}
+
+func (sm *spanMock) AddEvent(name string, attrs ...common.Attribute) {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+	sm.events = append(sm.events, spanEventMock{name: name, attrs: toAttrsMap(attrs)})
+}
+
+func (sm *spanMock) RecordError(err error) {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+	sm.errors = append(sm.errors, err)
+}
+
+func (sm *spanMock) SetStatus(code common.SpanStatusCode, description string) {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+	sm.status = code
+	sm.description = description
+}
+
+func (sm *spanMock) End() {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+	sm.ended = true
+}
+
+type tracerMock struct {
+	spans []*spanMock
+	lock  sync.Mutex
+}
+
+func (tm *tracerMock) Start(ctx context.Context, spanName string, attrs ...common.Attribute) (context.Context, common.Span) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	parent, _ := ctx.Value(parentSpanKey{}).(string)
+	span := &spanMock{name: spanName, parent: parent, attrs: toAttrsMap(attrs)}
+	tm.spans = append(tm.spans, span)
+
+	return context.WithValue(ctx, parentSpanKey{}, spanName), span
+}
+
+func newTracingTestClient(url string, tracer common.Tracer, middlewares ...common.Middleware) *Client {
+	constr := &ClientConstructor{}
+	constr.WithSessionKey("somesess")
+	constr.WithClientCode("someclient")
+	constr.WithURL(url)
+	constr.WithRetryPolicy(getTestRetryPolicy())
+	constr.WithTracer(tracer)
+	constr.WithMiddlewares(middlewares...)
+
+	return constr.Build()
+}
+
+func TestSpanOfSingleRequest(t *testing.T) {
+	var requestParentSpan string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(t, w, common.Status{ResponseStatus: "ok", GenerationTime: 0.5, RecordsTotal: 100, RecordsInResponse: 20})
+	}))
+	defer srv.Close()
+
+	tracer := &tracerMock{}
+	var middlewareSpan common.Span
+	cli := newTracingTestClient(srv.URL, tracer, common.MiddlewareFunc(func(next common.RequestHandler) common.RequestHandler {
+		return func(ctx context.Context, req *common.Request) (*common.Response, error) {
+			middlewareSpan = common.SpanFromContext(ctx)
+			resp, err := next(ctx, req)
+			if resp != nil && resp.HTTPResponse != nil {
+				requestParentSpan, _ = resp.HTTPResponse.Request.Context().Value(parentSpanKey{}).(string)
+			}
+			return resp, err
+		}
+	}))
+
+	ctx := context.WithValue(context.Background(), parentSpanKey{}, "managerSpan")
+	_, err := Call[statusResponse](ctx, cli, "getProducts", map[string]string{})
+	assert.NoError(t, err)
+
+	assert.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	assert.Equal(t, common.SpanNameRequest, span.name)
+	assert.Equal(t, "managerSpan", span.parent)
+	assert.Equal(t, common.SpanNameRequest, requestParentSpan)
+	assert.Equal(t, span, middlewareSpan)
+	assert.True(t, span.ended)
+	assert.Equal(t, common.SpanStatusOK, span.status)
+	assert.Equal(t, "getProducts", span.attrs[common.AttrMethod])
+	assert.Equal(t, "someclient", span.attrs[common.AttrClientCode])
+	assert.Equal(t, http.StatusOK, span.attrs[common.AttrHTTPStatusCode])
+	assert.Equal(t, 0.5, span.attrs[common.AttrGenerationTime])
+	assert.Equal(t, 100, span.attrs[common.AttrRecordsTotal])
+	assert.Equal(t, 20, span.attrs[common.AttrRecordsInResp])
+}
+
+func TestSpanOfFailedRequestWithRetries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(t, w, common.Status{ResponseStatus: "error", ErrorCode: common.ServerMaintenance})
+	}))
+	defer srv.Close()
+
+	tracer := &tracerMock{}
+	cli := newTracingTestClient(srv.URL, tracer)
+
+	_, err := Call[statusResponse](context.Background(), cli, "getProducts", map[string]string{})
+	assert.Error(t, err)
+
+	assert.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	assert.True(t, span.ended)
+	assert.Equal(t, common.SpanStatusError, span.status)
+	assert.Equal(t, int(common.ServerMaintenance), span.attrs[common.AttrErrorCode])
+
+	assert.Len(t, span.events, 2)
+	for i, event := range span.events {
+		assert.Equal(t, common.EventRetry, event.name)
+		assert.Equal(t, i+1, event.attrs[common.AttrAttempt])
+		assert.Equal(t, int(common.ServerMaintenance), event.attrs[common.AttrErrorCode])
+	}
+}
+
+func TestSpanOfBulkRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"status":{"responseStatus":"ok","generationTime":0.3},"requests":[` +
+			`{"status":{"requestName":"getProducts","requestID":"1","responseStatus":"ok","recordsInResponse":100}},` +
+			`{"status":{"requestID":"2","responseStatus":"error","errorCode":1016,"errorField":"pageNo"}}]}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	tracer := &tracerMock{}
+	cli := newTracingTestClient(srv.URL, tracer)
+
+	_, err := cli.SendRequestBulk(
+		context.Background(),
+		[]BulkInput{
+			{MethodName: "getProducts", Filters: map[string]interface{}{"requestID": "1"}},
+			{MethodName: "getCustomers", Filters: map[string]interface{}{"requestID": "2"}},
+		},
+		map[string]string{},
+	)
+	assert.NoError(t, err)
+
+	assert.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	assert.Equal(t, common.SpanNameBulkRequest, span.name)
+	assert.Equal(t, common.BulkMethodName, span.attrs[common.AttrMethod])
+	assert.Equal(t, 2, span.attrs[common.AttrBulkSubRequests])
+	assert.Equal(t, common.SpanStatusOK, span.status)
+
+	assert.Equal(t, []spanEventMock{
+		{
+			name: common.EventBulkSubRequest,
+			attrs: map[string]interface{}{
+				common.AttrSubRequestIndex: 0,
+				common.AttrRequestName:     "getProducts",
+				common.AttrRequestID:       "1",
+				common.AttrResponseStatus:  "ok",
+				common.AttrGenerationTime:  0.0,
+				common.AttrRecordsInResp:   100,
+			},
+		},
+		{
+			name: common.EventBulkSubRequest,
+			attrs: map[string]interface{}{
+				common.AttrSubRequestIndex: 1,
+				common.AttrRequestName:     "getCustomers",
+				common.AttrRequestID:       "2",
+				common.AttrResponseStatus:  "error",
+				common.AttrGenerationTime:  0.0,
+				common.AttrRecordsInResp:   0,
+				common.AttrErrorCode:       int(common.InvalidValue),
+				common.AttrErrorField:      "pageNo",
+			},
+		},
+	}, span.events)
+}
+
+func TestSpanOfTransportError(t *testing.T) {
+	tracer := &tracerMock{}
+	constr := &ClientConstructor{}
+	constr.WithSessionKey("somesess")
+	constr.WithClientCode("someclient")
+	constr.WithURL("http://127.0.0.1:1")
+	constr.WithTracer(tracer)
+	cli := constr.Build()
+
+	_, err := cli.SendRequest(context.Background(), "getProducts", map[string]string{})
+	assert.Error(t, err)
+
+	assert.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	assert.True(t, span.ended)
+	assert.Equal(t, common.SpanStatusError, span.status)
+	assert.Len(t, span.errors, 1)
+}
@@ -11,12 +11,10 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
-type BulkInput struct {
-	MethodName string
-	Filters    map[string]interface{}
-}
+type BulkInput = common.BulkInput
 
 func IsJSONResponseOK(responseStatus *common.Status) bool {
 	return strings.EqualFold(responseStatus.ResponseStatus, "ok")
@@ -55,29 +53,45 @@ func setParams(params url.Values, filters map[string]string) {
 }
 
 func (cli *Client) SendRequest(ctx context.Context, apiMethod string, filters map[string]string) (*http.Response, error) {
-	log.Log.Log(log.Debug, "will call %s with filters %+v", apiMethod, filters)
-	req, err := getHTTPRequest(cli, nil)
-	if err != nil {
-		return nil, common.NewFromError("failed to build http request", err, 0)
-	}
-	req = req.WithContext(ctx)
-	params := cli.headersFunc(apiMethod)
-	log.Log.Log(log.Debug, "extracted headers %+v", params)
-
-	params, err = cli.addSessionParams(params)
+	resp, err := cli.handleRequest(ctx, &common.Request{Method: apiMethod, Filters: filters}, cli.sendSingleRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	setParams(params, filters)
+	return toHTTPResponse(resp), nil
+}
 
-	req.URL.RawQuery = params.Encode()
-	resp, err := doRequest(req, cli)
-	if err != nil {
-		return nil, common.NewFromError(fmt.Sprintf("%v request failed", apiMethod), err, 0)
-	}
-	log.Log.Log(log.Debug, "got response with code: %d", resp.StatusCode)
-	return resp, nil
+func (cli *Client) sendSingleRequest(ctx context.Context, request *common.Request) (*common.Response, error) {
+	apiMethod, filters := request.Method, request.Filters
+	log.LogFields(
+		log.Debug,
+		"will call API method",
+		log.F(log.FieldMethod, apiMethod),
+		log.F(log.FieldClientCode, cli.getClientCode()),
+		log.F(log.FieldFilters, filters),
+	)
+	startTime := time.Now()
+	resp, err := cli.sendWithRetry(ctx, fmt.Sprintf("%v request failed", apiMethod), 0, func() (*http.Request, string, error) {
+		req, err := getHTTPRequest(cli, nil)
+		if err != nil {
+			return nil, "", common.NewFromError("failed to build http request", err, 0)
+		}
+		req = req.WithContext(ctx)
+		params := cli.headersFunc(apiMethod)
+		log.LogFields(log.Debug, "extracted headers", log.F("headers", params))
+
+		params, err = cli.addSessionParams(params)
+		if err != nil {
+			return nil, "", err
+		}
+
+		setParams(params, filters)
+
+		req.URL.RawQuery = params.Encode()
+		return req, params.Get(sessionKey), nil
+	})
+	cli.reportResponse(ctx, request, resp, err, time.Since(startTime))
+	return resp, err
 }
 
 func (cli *Client) addSessionParams(params url.Values) (url.Values, error) {
@@ -87,6 +101,25 @@ func (cli *Client) addSessionParams(params url.Values) (url.Values, error) {
 	return params, err
 }
 
+//getClientCode gives the client code which is sent with requests
+func (cli *Client) getClientCode() string {
+	if cli.clientCode != "" {
+		return cli.clientCode
+	}
+
+	return cli.headersFunc("").Get(clientCode)
+}
+
+//GetRemainingRequestsQuota gives the amount of requests which the client can make within the budget of the quota accountant,
+//isTracked is false if the client has no quota accountant
+func (cli *Client) GetRemainingRequestsQuota() (remaining int, isTracked bool) {
+	if cli.quotaAccountant == nil {
+		return 0, false
+	}
+
+	return cli.quotaAccountant.Remaining(cli.getClientCode()), true
+}
+
 func (cli *Client) InvalidateSession() {
 	cli.sessionProvider.Invalidate()
 }
@@ -131,10 +164,34 @@ func (cli *Client) Scan(ctx context.Context, apiMethod string, filters map[strin
 }
 
 func (cli *Client) SendRequestBulk(ctx context.Context, inputs []BulkInput, filters map[string]string) (*http.Response, error) {
-	log.Log.Log(log.Debug, "will call Bulk request with inputs %+v and filters %+v", inputs, filters)
+	if inputs == nil {
+		inputs = []BulkInput{}
+	}
+	resp, err := cli.handleRequest(ctx, &common.Request{BulkInputs: inputs, Filters: filters}, cli.sendBulkRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return toHTTPResponse(resp), nil
+}
+
+func (cli *Client) sendBulkRequest(ctx context.Context, request *common.Request) (*common.Response, error) {
+	inputs, filters := request.BulkInputs, request.Filters
+	log.LogFields(
+		log.Debug,
+		"will call Bulk API",
+		log.F(log.FieldClientCode, cli.getClientCode()),
+		log.F(log.FieldRequestsCount, len(inputs)),
+		log.F("inputs", inputs),
+		log.F(log.FieldFilters, filters),
+	)
+	startTime := time.Now()
 	bulkRequest := make([]map[string]interface{}, 0, len(inputs))
 	for _, input := range inputs {
-		bulkItemFilters := input.Filters
+		bulkItemFilters := make(map[string]interface{}, len(input.Filters)+1)
+		for k, v := range input.Filters {
+			bulkItemFilters[k] = v
+		}
 		bulkItemFilters["requestName"] = input.MethodName
 
 		bulkRequest = append(bulkRequest, bulkItemFilters)
@@ -145,37 +202,38 @@ func (cli *Client) SendRequestBulk(ctx context.Context, inputs []BulkInput, filt
 		return nil, common.NewFromError("failed to build requests payload", err, 0)
 	}
 
-	filters["requests"] = string(jsonRequests)
-
-	var params url.Values
-	if cli.headersFunc != nil {
-		params = cli.headersFunc("")
-		params.Del("request")
-		params, err = cli.addSessionParams(params)
-		if err != nil {
-			return nil, err
-		}
-
-	} else {
-		params = make(url.Values)
+	//the payload is copied, so it stays the same if the request is repeated
+	bulkFilters := make(map[string]string, len(filters)+1)
+	for k, v := range filters {
+		bulkFilters[k] = v
 	}
+	bulkFilters["requests"] = string(jsonRequests)
+
+	resp, err := cli.sendWithRetry(ctx, "Bulk request failed", len(inputs), func() (*http.Request, string, error) {
+		var params url.Values
+		if cli.headersFunc != nil {
+			params = cli.headersFunc("")
+			params.Del("request")
+			params, err = cli.addSessionParams(params)
+			if err != nil {
+				return nil, "", err
+			}
+		} else {
+			params = make(url.Values)
+		}
 
-	setParams(params, filters)
-
-	req, err := getHTTPRequest(cli, strings.NewReader(params.Encode()))
-	if err != nil {
-		return nil, common.NewFromError("failed to build http request", err, 0)
-	}
+		setParams(params, bulkFilters)
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req = req.WithContext(ctx)
+		req, err := getHTTPRequest(cli, strings.NewReader(params.Encode()))
+		if err != nil {
+			return nil, "", common.NewFromError("failed to build http request", err, 0)
+		}
 
-	resp, err := doRequest(req, cli)
-	if err != nil {
-		return nil, common.NewFromError("Bulk request failed", err, 0)
-	}
-	log.Log.Log(log.Debug, "got response from Bulk API with status %d", resp.StatusCode)
-	return resp, nil
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req.WithContext(ctx), params.Get(sessionKey), nil
+	})
+	cli.reportResponse(ctx, request, resp, err, time.Since(startTime))
+	return resp, err
 }
 
 func doRequest(req *http.Request, cli *Client) (*http.Response, error) {
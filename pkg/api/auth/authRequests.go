@@ -34,6 +34,7 @@ func VerifyUser(username, password, clientCode string, client *http.Client) (str
 	if err != nil {
 		return "", sharedCommon.NewFromError("failed to build VerifyUser request", err, 0)
 	}
+	defer resp.Body.Close()
 
 	res := &VerifyUserResponse{}
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
@@ -64,6 +65,7 @@ func VerifyUserV2(ctx context.Context, filters map[string]string, clientCode str
 	if err != nil {
 		return "", sharedCommon.NewFromError("failed to build VerifyUser request", err, 0)
 	}
+	defer resp.Body.Close()
 	res := &VerifyUserResponse{}
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
 		return "", sharedCommon.NewFromError("failed to decode VerifyUserResponse", err, 0)
@@ -93,6 +95,7 @@ func VerifyUserV3(ctx context.Context, filters map[string]string, clientCode str
 	if err != nil {
 		return nil, sharedCommon.NewFromError("failed to build VerifyUser request", err, 0)
 	}
+	defer resp.Body.Close()
 	res := &VerifyUserResponse{}
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
 		return nil, sharedCommon.NewFromError("failed to decode VerifyUserResponse", err, 0)
@@ -128,6 +131,7 @@ func VerifyUserFull(ctx context.Context, username, password, clientCode string,
 	if err != nil {
 		return nil, sharedCommon.NewFromError("failed to build VerifyUser request", err, 0)
 	}
+	defer resp.Body.Close()
 	res := &VerifyUserResponse{}
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
 		return nil, sharedCommon.NewFromError("failed to decode VerifyUserResponse", err, 0)
@@ -166,6 +170,7 @@ func SwitchUser(ctx context.Context, sessionKey, pin, clientCode string, inputPa
 	if err != nil {
 		return nil, sharedCommon.NewFromError("failed to build SwitchUser request", err, 0)
 	}
+	defer resp.Body.Close()
 	res := &SwitchUserResponse{}
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
 		return nil, sharedCommon.NewFromError("failed to decode SwitchUserResponse", err, 0)
@@ -2,10 +2,7 @@ package auth
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"github.com/erply/api-go-wrapper/internal/common"
-	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
 )
 
 //VerifyIdentityToken ...
@@ -17,37 +14,19 @@ func (cli *Client) VerifyIdentityToken(ctx context.Context, jwt string) (*Sessio
 		//params.Add("setContentType", "1")
 		"jwt": jwt,
 	}
-	resp, err := cli.SendRequest(ctx, method, params)
+	res, err := common.Call[verifyIdentityTokenResponse](ctx, cli.Client, method, params)
 	if err != nil {
 		return nil, err
 	}
-	res := &verifyIdentityTokenResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError(fmt.Sprintf("unmarshaling %s response failed", method), err, 0)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 
 	return &res.Result, nil
 }
 
 //GetIdentityToken ...
 func (cli *Client) GetIdentityToken(ctx context.Context) (*IdentityToken, error) {
-	method := "getIdentityToken"
-
-	resp, err := cli.SendRequest(ctx, method, map[string]string{})
+	res, err := common.Call[getIdentityTokenResponse](ctx, cli.Client, "getIdentityToken", map[string]string{})
 	if err != nil {
-		return nil, sharedCommon.NewFromError(fmt.Sprintf("%s request failed", method), err, 0)
-	}
-	res := &getIdentityTokenResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError(fmt.Sprintf("unmarshaling %s response failed", method), err, 0)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
+		return nil, err
 	}
 
 	return &res.Result, nil
@@ -55,40 +34,19 @@ func (cli *Client) GetIdentityToken(ctx context.Context) (*IdentityToken, error)
 
 //GetJWTToken executes the getJWTToken query (https://learn-api.erply.com/requests/getjwttoken).
 func (cli *Client) GetJWTToken(ctx context.Context) (*JwtToken, error) {
-
-	resp, err := cli.SendRequest(ctx, "getJwtToken", map[string]string{})
-	if err != nil {
-		return nil, err
-	}
-	var res JwtTokenResponse
-
-	err = json.NewDecoder(resp.Body).Decode(&res)
-	if err != nil {
-		return nil, sharedCommon.NewFromError("error decoding GetJWTToken response", err, 0)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
-
-	return &res.Records, nil
+	return getJWTToken(ctx, cli.Client)
 }
 
 //only for partnerClient
 func (cli *PartnerClient) GetJWTToken(ctx context.Context) (*JwtToken, error) {
+	return getJWTToken(ctx, cli.Client)
+}
 
-	resp, err := cli.SendRequest(ctx, "getJwtToken", map[string]string{})
+func getJWTToken(ctx context.Context, cli *common.Client) (*JwtToken, error) {
+	res, err := common.Call[JwtTokenResponse](ctx, cli, "getJwtToken", map[string]string{})
 	if err != nil {
 		return nil, err
 	}
-	var res JwtTokenResponse
-
-	err = json.NewDecoder(resp.Body).Decode(&res)
-	if err != nil {
-		return nil, sharedCommon.NewFromError("error decoding GetJWTToken response", err, 0)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 
 	return &res.Records, nil
 }
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/erply/api-go-wrapper/internal/common"
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+)
+
+//ErrBulkNotSent is the error of a BulkResult before BulkBuilder.Send is called
+var ErrBulkNotSent = errors.New("bulk request is not sent yet")
+
+//BulkBuilder queues calls of different API methods which are sent in one bulk request, e.g. saveSalesDocument
+//and savePayment which refers to the created document with sharedCommon.CurrentInvoiceID.
+//Use AddToBulk or AddInputToBulk to queue the calls and Send to execute them, a builder can be sent only once.
+type BulkBuilder struct {
+	cli     *common.Client
+	inputs  []common.BulkInput
+	results []bulkResultSetter
+	sent    bool
+}
+
+//bulkResultSetter is implemented by BulkResult of every type
+type bulkResultSetter interface {
+	set(status sharedCommon.StatusBulk, records json.RawMessage, err error)
+}
+
+//NewBulkBuilder creates an empty BulkBuilder which sends its requests with the client
+func (cl *Client) NewBulkBuilder() *BulkBuilder {
+	return &BulkBuilder{cli: cl.commonClient}
+}
+
+//Len gives the count of queued calls
+func (bb *BulkBuilder) Len() int {
+	return len(bb.inputs)
+}
+
+//BulkResult is the typed result of one call queued in BulkBuilder, it's filled after BulkBuilder.Send,
+//T is the type of the response records, e.g. sales.SaleDocImportReport for saveSalesDocument
+type BulkResult[T any] struct {
+	index   int
+	status  sharedCommon.StatusBulk
+	records []T
+	err     error
+}
+
+//Index gives the position of the call in the bulk request
+func (br *BulkResult[T]) Index() int {
+	return br.index
+}
+
+//Status gives the status of the sub-request response
+func (br *BulkResult[T]) Status() sharedCommon.StatusBulk {
+	return br.status
+}
+
+//Records gives the decoded records of the sub-request response or the error of the sub-request.
+//The error is *sharedCommon.ErplyError or *sharedCommon.BulkDependencyError if the call refers to a document
+//of a preceding saveSalesDocument call which failed, it's ErrBulkNotSent if the builder wasn't sent yet.
+func (br *BulkResult[T]) Records() ([]T, error) {
+	return br.records, br.err
+}
+
+//First gives the first record of the response, e.g. the import report of a saved document
+func (br *BulkResult[T]) First() (T, error) {
+	var res T
+	if br.err != nil {
+		return res, br.err
+	}
+	if len(br.records) == 0 {
+		return res, sharedCommon.NewFromError(fmt.Sprintf("%s: no records in response", br.status.RequestName), nil, 0)
+	}
+
+	return br.records[0], nil
+}
+
+//Err gives the error of the sub-request, see Records
+func (br *BulkResult[T]) Err() error {
+	return br.err
+}
+
+func (br *BulkResult[T]) set(status sharedCommon.StatusBulk, records json.RawMessage, err error) {
+	br.status = status
+	br.err = err
+	if err != nil || len(records) == 0 {
+		return
+	}
+
+	if decodeErr := json.Unmarshal(records, &br.records); decodeErr != nil {
+		br.err = fmt.Errorf("ERPLY API: failed to unmarshal records of %s from '%s': %w", status.RequestName, string(records), decodeErr)
+	}
+}
+
+//AddToBulk queues the API call in the builder, the returned result is filled after the builder is sent
+func AddToBulk[T any](bb *BulkBuilder, method string, filters map[string]interface{}) *BulkResult[T] {
+	res := &BulkResult[T]{index: len(bb.inputs), err: ErrBulkNotSent}
+	if filters == nil {
+		filters = map[string]interface{}{}
+	}
+
+	bb.inputs = append(bb.inputs, common.BulkInput{MethodName: method, Filters: filters})
+	bb.results = append(bb.results, res)
+
+	return res
+}
+
+//AddInputToBulk queues the API call with a typed filter or save input, e.g. sales.SaleDocumentInput
+func AddInputToBulk[T any](bb *BulkBuilder, method string, input sharedCommon.Filter) (*BulkResult[T], error) {
+	filters, err := input.ToFilters()
+	if err != nil {
+		return nil, err
+	}
+
+	bulkFilters := make(map[string]interface{}, len(filters))
+	for k, v := range filters {
+		bulkFilters[k] = v
+	}
+
+	return AddToBulk[T](bb, method, bulkFilters), nil
+}
+
+type mixedBulkResponse struct {
+	Status    sharedCommon.Status `json:"status"`
+	BulkItems []struct {
+		Status  sharedCommon.StatusBulk `json:"status"`
+		Records json.RawMessage         `json:"records"`
+	} `json:"requests"`
+}
+
+//Send executes all queued calls in one bulk request and fills their results.
+//It fails if the whole request fails, otherwise it gives nil or *sharedCommon.BulkError with the failed calls,
+//the results of the successful calls are available in both cases.
+func (bb *BulkBuilder) Send(ctx context.Context, baseFilters map[string]string) error {
+	if bb.sent {
+		return errors.New("bulk request is sent already")
+	}
+	if err := bb.validate(); err != nil {
+		return err
+	}
+	bb.sent = true
+
+	resp, err := common.CallBulkInputs[mixedBulkResponse](ctx, bb.cli, bb.inputs, baseFilters)
+	bulkErr, isBulkErr := sharedCommon.AsBulkError(err)
+	if err != nil && !isBulkErr {
+		for _, res := range bb.results {
+			res.set(sharedCommon.StatusBulk{}, nil, err)
+		}
+		return err
+	}
+
+	for i, res := range bb.results {
+		if i >= len(resp.BulkItems) {
+			res.set(sharedCommon.StatusBulk{}, nil, fmt.Errorf("ERPLY API: no response for the sub-request %d", i))
+			continue
+		}
+
+		bulkItem := resp.BulkItems[i]
+		var itemErr error
+		if isBulkErr {
+			if erplyErr := bulkErr.ErrorAt(i); erplyErr != nil {
+				itemErr = bb.newItemError(i, erplyErr, bulkErr)
+			}
+		}
+		res.set(bulkItem.Status, bulkItem.Records, itemErr)
+	}
+
+	return err
+}
+
+//validate checks the limits of one bulk request and that CURRENT_INVOICE_ID is used after a saveSalesDocument call
+func (bb *BulkBuilder) validate() error {
+	if len(bb.inputs) == 0 {
+		return errors.New("bulk request has no calls")
+	}
+	if len(bb.inputs) > sharedCommon.MaxBulkRequestsCount {
+		return fmt.Errorf("cannot send more than %d calls in one bulk request, got %d", sharedCommon.MaxBulkRequestsCount, len(bb.inputs))
+	}
+
+	for i, input := range bb.inputs {
+		if usesCurrentInvoiceID(input) && bb.findDocumentIndex(i) < 0 {
+			return fmt.Errorf(
+				"call %d of %s refers to %s, but there is no %s call before it",
+				i,
+				input.MethodName,
+				sharedCommon.CurrentInvoiceID,
+				sharedCommon.SaveSalesDocumentMethod,
+			)
+		}
+	}
+
+	return nil
+}
+
+//findDocumentIndex gives the position of the last saveSalesDocument call before the given one or -1
+func (bb *BulkBuilder) findDocumentIndex(index int) int {
+	for i := index - 1; i >= 0; i-- {
+		if bb.inputs[i].MethodName == sharedCommon.SaveSalesDocumentMethod {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (bb *BulkBuilder) newItemError(index int, erplyErr *sharedCommon.ErplyError, bulkErr *sharedCommon.BulkError) error {
+	if !sharedCommon.IsBulkDependencyErrorCode(erplyErr.Code) {
+		return erplyErr
+	}
+
+	dependencyErr := &sharedCommon.BulkDependencyError{
+		Err:           erplyErr,
+		DocumentIndex: bb.findDocumentIndex(index),
+	}
+	if dependencyErr.DocumentIndex >= 0 {
+		dependencyErr.DocumentErr = bulkErr.ErrorAt(dependencyErr.DocumentIndex)
+	}
+
+	return dependencyErr
+}
+
+func usesCurrentInvoiceID(input common.BulkInput) bool {
+	for _, v := range input.Filters {
+		if s, ok := v.(string); ok && s == sharedCommon.CurrentInvoiceID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"errors"
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/erply/api-go-wrapper/pkg/api/erplytest"
+	"github.com/erply/api-go-wrapper/pkg/api/products"
+	"github.com/erply/api-go-wrapper/pkg/api/sales"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newBulkTestClient(t *testing.T) (*erplytest.Server, *Client) {
+	srv := erplytest.NewServer()
+	srv.SessionKey = "somesess"
+
+	paymentsCount := 0
+	srv.HandleMethod("savePayment", func(filters map[string]string) ([]interface{}, int, error) {
+		paymentsCount++
+		return []interface{}{erplytest.Record{"paymentID": paymentsCount, "documentID": filters["documentID"]}}, 1, nil
+	})
+
+	cli, err := NewClientWithURL("somesess", "someclient", "", srv.URL, nil, nil)
+	assert.NoError(t, err)
+
+	return srv, cli
+}
+
+func TestBulkBuilder(t *testing.T) {
+	srv, cli := newBulkTestClient(t)
+	defer srv.Close()
+	srv.AddRecords(erplytest.EntityProducts, erplytest.Record{"productID": 10, "code": "001"})
+
+	price := 2.0
+	builder := cli.NewBulkBuilder()
+	docRes, err := AddInputToBulk[sales.SaleDocImportReport](builder, "saveSalesDocument", sales.SaleDocumentInput{
+		WarehouseID: 1,
+		Rows:        []sales.SaleDocumentRowInput{{ProductID: 10, Amount: 3, Price: &price}},
+	})
+	assert.NoError(t, err)
+	paymentRes := AddToBulk[sales.SavePaymentID](builder, "savePayment", map[string]interface{}{
+		"documentID": sharedCommon.CurrentInvoiceID,
+		"sum":        6,
+	})
+	productsRes := AddToBulk[products.Product](builder, "getProducts", nil)
+	assert.Equal(t, 3, builder.Len())
+
+	_, err = paymentRes.Records()
+	assert.Equal(t, ErrBulkNotSent, err)
+
+	assert.NoError(t, builder.Send(context.Background(), nil))
+
+	report, err := docRes.First()
+	assert.NoError(t, err)
+	assert.Equal(t, 6.0, report.Total)
+
+	payment, err := paymentRes.First()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, payment.PaymentID)
+	assert.Equal(t, 1, paymentRes.Index())
+
+	prods, err := productsRes.Records()
+	assert.NoError(t, err)
+	assert.Len(t, prods, 1)
+	assert.Equal(t, "getProducts", productsRes.Status().RequestName)
+
+	reqs := srv.Requests()
+	assert.Equal(t, report.InvoiceID.String(), reqs[len(reqs)-2].Filters["documentID"])
+
+	assert.EqualError(t, builder.Send(context.Background(), nil), "bulk request is sent already")
+}
+
+func TestBulkBuilderDependencyErrors(t *testing.T) {
+	srv, cli := newBulkTestClient(t)
+	defer srv.Close()
+
+	builder := cli.NewBulkBuilder()
+	docRes := AddToBulk[sales.SaleDocImportReport](builder, "saveSalesDocument", map[string]interface{}{"id": 100})
+	paymentRes := AddToBulk[sales.SavePaymentID](builder, "savePayment", map[string]interface{}{"documentID": sharedCommon.CurrentInvoiceID})
+	otherPaymentRes := AddToBulk[sales.SavePaymentID](builder, "savePayment", map[string]interface{}{"documentID": 5})
+
+	err := builder.Send(context.Background(), nil)
+	bulkErr, ok := sharedCommon.AsBulkError(err)
+	assert.True(t, ok)
+	if ok {
+		assert.Equal(t, []int{0, 1}, bulkErr.FailedIndexes())
+	}
+
+	var erplyErr *sharedCommon.ErplyError
+	assert.True(t, errors.As(docRes.Err(), &erplyErr))
+	assert.Equal(t, sharedCommon.InvalidClassifierID, erplyErr.Code)
+
+	var dependencyErr *sharedCommon.BulkDependencyError
+	assert.True(t, errors.As(paymentRes.Err(), &dependencyErr))
+	if dependencyErr != nil {
+		assert.Equal(t, 0, dependencyErr.DocumentIndex)
+		assert.Equal(t, sharedCommon.BulkSubRequestDocumentError, dependencyErr.Err.Code)
+		assert.Equal(t, sharedCommon.InvalidClassifierID, dependencyErr.DocumentErr.Code)
+		assert.False(t, dependencyErr.IsDuplicate())
+	}
+	assert.True(t, errors.As(paymentRes.Err(), &erplyErr))
+	assert.Equal(t, sharedCommon.BulkSubRequestDocumentError, erplyErr.Code)
+
+	assert.NoError(t, otherPaymentRes.Err())
+}
+
+func TestBulkBuilderValidation(t *testing.T) {
+	srv, cli := newBulkTestClient(t)
+	defer srv.Close()
+
+	builder := cli.NewBulkBuilder()
+	assert.EqualError(t, builder.Send(context.Background(), nil), "bulk request has no calls")
+
+	AddToBulk[sales.SavePaymentID](builder, "savePayment", map[string]interface{}{"documentID": sharedCommon.CurrentInvoiceID})
+	assert.EqualError(
+		t,
+		builder.Send(context.Background(), nil),
+		"call 0 of savePayment refers to CURRENT_INVOICE_ID, but there is no saveSalesDocument call before it",
+	)
+
+	builder = cli.NewBulkBuilder()
+	for i := 0; i <= sharedCommon.MaxBulkRequestsCount; i++ {
+		AddToBulk[products.Product](builder, "getProducts", nil)
+	}
+	assert.EqualError(t, builder.Send(context.Background(), nil), "cannot send more than 100 calls in one bulk request, got 101")
+	assert.Len(t, srv.Requests(), 0)
+}
@@ -59,6 +59,12 @@ func (cl *Client) GetSession() (sessionKey string, err error) {
 	return cl.commonClient.GetSession()
 }
 
+//GetRemainingRequestsQuota gives the amount of requests which can be made within the budget of the quota accountant,
+//isTracked is false if no QuotaAccountant was configured
+func (cl *Client) GetRemainingRequestsQuota() (remaining int, isTracked bool) {
+	return cl.commonClient.GetRemainingRequestsQuota()
+}
+
 //NewUnvalidatedClient returns a new Client without validating any of the incoming parameters giving the
 //developer more flexibility
 func NewUnvalidatedClient(sk, cc, partnerKey string, httpCli *http.Client) *Client {
@@ -117,16 +123,24 @@ func newErplyClient(c *common.Client) *Client {
 }
 
 type ClientBuilder struct {
-	UserName                   string                 //if set this will be used to fetch session key every time when session gets outdated
-	Password                   string                 //if set this will be used to fetch session key every time when session gets outdated
-	ClientCode                 string                 //required value for all requests
-	SessionKey                 string                 //if you don't set SessionProvider this key will be used to auth all requests
-	DefaultSessionLenSeconds   int                    //set the length of dynamically created sessions
-	URL                        string                 //change the base API url
-	PartnerKey                 string                 //set the partner key
-	HttpCli                    *http.Client           //you can adjust the http client transport options here
-	HeadersForEveryRequestFunc common.AuthFunc        //this will set headers for all outgoing requests except for the session key
-	SessionProvider            common.SessionProvider //custom session establishing logic, if not set DynamicSessionProvider is used which requires UserName and Password
+	UserName                   string                        //if set this will be used to fetch session key every time when session gets outdated
+	Password                   string                        //if set this will be used to fetch session key every time when session gets outdated
+	ClientCode                 string                        //required value for all requests
+	SessionKey                 string                        //if you don't set SessionProvider this key will be used to auth all requests
+	DefaultSessionLenSeconds   int                           //set the length of dynamically created sessions
+	URL                        string                        //change the base API url
+	PartnerKey                 string                        //set the partner key
+	HttpCli                    *http.Client                  //you can adjust the http client transport options here
+	HeadersForEveryRequestFunc common.AuthFunc               //this will set headers for all outgoing requests except for the session key
+	SessionProvider            common.SessionProvider        //custom session establishing logic, if not set DynamicSessionProvider is used which requires UserName and Password
+	RetryPolicy                sharedCommon.RetryPolicy      //repeating of failed requests, e.g. sharedCommon.NewDefaultRetryPolicy(), requests are not repeated if not set
+	Middlewares                []sharedCommon.Middleware     //wrappers of every request e.g. for logging or metrics, the first middleware is the outermost one
+	Throttler                  sharedCommon.Throttler        //limits the rate of all requests, e.g. sharedCommon.NewTokenBucketThrottler or a throttler from sharedCommon.ThrottlersPool shared per client code
+	QuotaAccountant            *sharedCommon.QuotaAccountant //counts requests against the hourly quota of the account and optionally enforces a budget, can be shared between clients
+	MetricsCollector           sharedCommon.MetricsCollector //receives latency, status and error code of every API call, e.g. sharedCommon.NewInMemoryMetricsCollector which can be exposed to Prometheus
+	Tracer                     sharedCommon.Tracer           //starts a span for every API call, e.g. an adapter of an OpenTelemetry tracer, nothing is traced if not set
+	Cache                      *sharedCommon.RequestCache    //caches the responses of reference data methods like getVatRates, it's the outermost middleware, nothing is cached if not set
+	BulkConcurrency            int                           //count of chunks sent at the same time when a bulk request has more than sharedCommon.MaxBulkRequestsCount items, sharedCommon.DefaultBulkConcurrency if not set
 }
 
 type DynamicSessionProvider struct {
@@ -151,17 +165,17 @@ func (dsp *DynamicSessionProvider) GetSession() (sessionKey string, err error) {
 	defer dsp.Lock.Unlock()
 
 	if dsp.isSessionValid() {
-		log.Log.Log(log.Debug, "will use the cached key which is valid till %v", dsp.SessionValidTill)
+		log.LogFields(log.Debug, "will use the cached session key", log.F(log.FieldClientCode, dsp.ClientCode), log.F("validTill", dsp.SessionValidTill))
 		return dsp.SessionKey, nil
 	}
 
-	log.Log.Log(log.Debug, "will request new session key since the old one is not valid %v", dsp.SessionValidTill)
+	log.LogFields(log.Debug, "will request new session key since the old one is not valid", log.F(log.FieldClientCode, dsp.ClientCode), log.F("validTill", dsp.SessionValidTill))
 	sessionKey, validTill, err := dsp.getAuthUserFromAPI()
 	if err != nil {
 		return "", err
 	}
 
-	log.Log.Log(log.Debug, "got new session key with validity till %v", validTill)
+	log.LogFields(log.Debug, "got new session key", log.F(log.FieldClientCode, dsp.ClientCode), log.F("validTill", validTill))
 
 	dsp.SessionKey = sessionKey
 	dsp.SessionValidTill = validTill
@@ -189,11 +203,12 @@ func (dsp *DynamicSessionProvider) getAuthUserFromAPI() (sessionKey string, vali
 	params.Add("sessionLength", strconv.Itoa(dsp.DefaultSessionLenSeconds))
 	params.Add("request", "verifyUser")
 
-	log.Log.Log(log.Debug,
-		"will call verifyUser with client code %s, user name %s and session length %d seconds",
-		dsp.ClientCode,
-		dsp.UserName,
-		dsp.DefaultSessionLenSeconds,
+	log.LogFields(
+		log.Debug,
+		"will call verifyUser",
+		log.F(log.FieldClientCode, dsp.ClientCode),
+		log.F("userName", dsp.UserName),
+		log.F("sessionLengthSeconds", dsp.DefaultSessionLenSeconds),
 	)
 
 	req, err := http.NewRequest("POST", requestUrl, nil)
@@ -211,8 +226,10 @@ func (dsp *DynamicSessionProvider) getAuthUserFromAPI() (sessionKey string, vali
 	resp, err := client.Do(req)
 
 	if err != nil {
-		return "", nil, err
+		//the request URL contains the password
+		return "", nil, log.RedactError(err)
 	}
+	defer resp.Body.Close()
 
 	res := &auth.VerifyUserResponse{}
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
@@ -258,6 +275,16 @@ func (cb ClientBuilder) Build() *Client {
 	constr.WithHeaderFunc(cb.HeadersForEveryRequestFunc)
 	constr.WithHttpClient(cb.HttpCli)
 	constr.WithSessionKey(cb.SessionKey)
+	constr.WithRetryPolicy(cb.RetryPolicy)
+	if cb.Cache != nil {
+		constr.WithMiddlewares(cb.Cache)
+	}
+	constr.WithMiddlewares(cb.Middlewares...)
+	constr.WithThrottler(cb.Throttler)
+	constr.WithQuotaAccountant(cb.QuotaAccountant)
+	constr.WithMetricsCollector(cb.MetricsCollector)
+	constr.WithTracer(cb.Tracer)
+	constr.WithBulkConcurrency(cb.BulkConcurrency)
 
 	baseClient := constr.Build()
 
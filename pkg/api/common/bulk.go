@@ -3,4 +3,18 @@ package common
 const (
 	MaxBulkRequestsCount       = 100
 	MaxCountPerBulkRequestItem = 100
+	//DefaultBulkConcurrency is the count of chunks of a bulk request with more than MaxBulkRequestsCount
+	//sub-requests which are sent at the same time
+	DefaultBulkConcurrency = 4
+
+	//CurrentInvoiceID is a special filter value of a bulk sub-request which refers to the document
+	//created by the preceding saveSalesDocument sub-request, e.g. documentID of savePayment
+	CurrentInvoiceID        = "CURRENT_INVOICE_ID"
+	SaveSalesDocumentMethod = "saveSalesDocument"
 )
+
+//BulkInput describes one sub-request of a bulk API call
+type BulkInput struct {
+	MethodName string
+	Filters    map[string]interface{}
+}
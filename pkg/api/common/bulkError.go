@@ -0,0 +1,226 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+//BulkItemError is the error of one failed sub-request of a bulk API call
+type BulkItemError struct {
+	Index       int    //position of the sub-request in the input slice
+	RequestID   string //requestID of the sub-request if it was given
+	RequestName string //API method of the sub-request
+	Err         *ErplyError
+}
+
+//BulkError is returned by bulk methods when some sub-requests failed, the response with the results
+//of the successful sub-requests is returned together with it
+type BulkError struct {
+	Items      []BulkItemError //failed sub-requests ordered by Index
+	TotalCount int             //count of all sub-requests in the bulk call
+}
+
+func (be *BulkError) Error() string {
+	if len(be.Items) == 0 {
+		return fmt.Sprintf("ERPLY API: 0 of %d bulk sub-requests failed", be.TotalCount)
+	}
+
+	first := be.Items[0]
+	return fmt.Sprintf(
+		"ERPLY API: %d of %d bulk sub-requests failed, first failed sub-request %d (%s): %v",
+		len(be.Items),
+		be.TotalCount,
+		first.Index,
+		first.RequestName,
+		first.Err,
+	)
+}
+
+//Unwrap gives the errors of all failed sub-requests, so errors.As finds an ErplyError in BulkError
+func (be *BulkError) Unwrap() []error {
+	errs := make([]error, 0, len(be.Items))
+	for _, item := range be.Items {
+		errs = append(errs, item.Err)
+	}
+
+	return errs
+}
+
+//FailedIndexes gives the positions of the failed sub-requests in the input slice
+func (be *BulkError) FailedIndexes() []int {
+	indexes := make([]int, 0, len(be.Items))
+	for _, item := range be.Items {
+		indexes = append(indexes, item.Index)
+	}
+
+	return indexes
+}
+
+//ErrorAt gives the error of the sub-request with the given position or nil if it succeeded
+func (be *BulkError) ErrorAt(index int) *ErplyError {
+	for _, item := range be.Items {
+		if item.Index == index {
+			return item.Err
+		}
+	}
+
+	return nil
+}
+
+//ErrorsByRequestID maps the requestID of each failed sub-request to its error, sub-requests without requestID are skipped
+func (be *BulkError) ErrorsByRequestID() map[string]*ErplyError {
+	res := make(map[string]*ErplyError, len(be.Items))
+	for _, item := range be.Items {
+		if item.RequestID != "" {
+			res[item.RequestID] = item.Err
+		}
+	}
+
+	return res
+}
+
+//IsRetryable tells if all failed sub-requests have an error which the retry policy considers temporary
+func (be *BulkError) IsRetryable(retryPolicy RetryPolicy) bool {
+	for _, item := range be.Items {
+		if !retryPolicy.IsRetryableCode(item.Err.Code) {
+			return false
+		}
+	}
+
+	return len(be.Items) > 0
+}
+
+//IsBulkDependencyErrorCode tells if a sub-request failed because it refers to CurrentInvoiceID,
+//but the preceding saveSalesDocument sub-request failed or was flagged as a duplicate, so no document was created
+func IsBulkDependencyErrorCode(code ApiError) bool {
+	return code == BulkSubRequestDocumentError || code == BulkSubRequestDuplicateError
+}
+
+//BulkDependencyError is the error of a sub-request which refers to CurrentInvoiceID when the document
+//of the preceding saveSalesDocument sub-request wasn't created
+type BulkDependencyError struct {
+	Err           *ErplyError //BulkSubRequestDocumentError or BulkSubRequestDuplicateError
+	DocumentIndex int         //position of the saveSalesDocument sub-request, -1 if it's unknown
+	DocumentErr   *ErplyError //error of the saveSalesDocument sub-request, nil if it was flagged as a duplicate
+}
+
+func (bde *BulkDependencyError) Error() string {
+	if bde.DocumentErr != nil {
+		return fmt.Sprintf("%v, the document of sub-request %d failed: %v", bde.Err, bde.DocumentIndex, bde.DocumentErr)
+	}
+
+	return fmt.Sprintf("%v, the document of sub-request %d was not created", bde.Err, bde.DocumentIndex)
+}
+
+//Unwrap gives the error of the sub-request
+func (bde *BulkDependencyError) Unwrap() error {
+	return bde.Err
+}
+
+//IsDuplicate tells if the document wasn't created because it's a duplicate of an existing one
+func (bde *BulkDependencyError) IsDuplicate() bool {
+	return bde.Err.Code == BulkSubRequestDuplicateError
+}
+
+//AsBulkError gives BulkError if err is or wraps it
+func AsBulkError(err error) (*BulkError, bool) {
+	var bulkErr *BulkError
+	if errors.As(err, &bulkErr) {
+		return bulkErr, true
+	}
+
+	return nil, false
+}
+
+//FailedBulkItems selects the inputs of the failed sub-requests and their positions in the input slice, so they can be sent again,
+//nothing is selected if err is not a BulkError
+func FailedBulkItems[T any](items []T, err error) (failedItems []T, indexes []int) {
+	bulkErr, ok := AsBulkError(err)
+	if !ok {
+		return nil, nil
+	}
+
+	for _, index := range bulkErr.FailedIndexes() {
+		if index >= 0 && index < len(items) {
+			failedItems = append(failedItems, items[index])
+			indexes = append(indexes, index)
+		}
+	}
+
+	return failedItems, indexes
+}
+
+//BulkItemsCall sends the items as one bulk request, indexes are the positions of the items in the original input slice,
+//so the caller can put the results to the right place
+type BulkItemsCall[T any] func(ctx context.Context, items []T, indexes []int) error
+
+//RetryFailedBulkItems repeats the failed sub-requests of a bulk call according to the retry policy.
+//err is the error of the first call with all items, only the items with retryable error codes are sent again.
+//It gives nil if all items succeeded finally, or BulkError with the positions in the original input slice
+//for the items which still fail. Errors which are not BulkError stop the retries and are returned as is.
+//A nil sleeper means retryPolicy.Wait, which returns early when ctx is done.
+func RetryFailedBulkItems[T any](ctx context.Context, items []T, err error, retryPolicy RetryPolicy, sleeper Sleeper, call BulkItemsCall[T]) error {
+	for attempt := 1; attempt < retryPolicy.MaxAttempts; attempt++ {
+		bulkErr, ok := AsBulkError(err)
+		if !ok {
+			return err
+		}
+
+		var failedItems []T
+		var indexes []int
+		remainingErrors := make([]BulkItemError, 0)
+		for _, item := range bulkErr.Items {
+			if item.Index < 0 || item.Index >= len(items) {
+				continue
+			}
+			if !retryPolicy.IsRetryableCode(item.Err.Code) {
+				remainingErrors = append(remainingErrors, item)
+				continue
+			}
+			failedItems = append(failedItems, items[item.Index])
+			indexes = append(indexes, item.Index)
+		}
+		if len(failedItems) == 0 {
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if sleeper != nil {
+			sleeper(retryPolicy.Backoff(attempt))
+		} else if waitErr := retryPolicy.Wait(ctx, attempt); waitErr != nil {
+			return waitErr
+		}
+
+		retryErr := call(ctx, failedItems, indexes)
+		if retryErr == nil {
+			if len(remainingErrors) == 0 {
+				return nil
+			}
+			return &BulkError{Items: remainingErrors, TotalCount: len(items)}
+		}
+
+		retryBulkErr, ok := AsBulkError(retryErr)
+		if !ok {
+			return retryErr
+		}
+
+		//the positions in the retried slice are mapped back to the original input slice
+		for _, item := range retryBulkErr.Items {
+			if item.Index < 0 || item.Index >= len(indexes) {
+				continue
+			}
+			item.Index = indexes[item.Index]
+			remainingErrors = append(remainingErrors, item)
+		}
+		sort.Slice(remainingErrors, func(i, j int) bool {
+			return remainingErrors[i].Index < remainingErrors[j].Index
+		})
+		err = &BulkError{Items: remainingErrors, TotalCount: len(items)}
+	}
+
+	return err
+}
@@ -0,0 +1,97 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func getTestBulkError() *BulkError {
+	return &BulkError{
+		Items: []BulkItemError{
+			{Index: 1, RequestID: "req1", RequestName: "saveProduct", Err: NewErplyError("error", "quota", HourlyRequestQuota)},
+			{Index: 3, RequestName: "saveProduct", Err: NewErplyError("error", "invalid", InvalidValue)},
+		},
+		TotalCount: 4,
+	}
+}
+
+func TestBulkError(t *testing.T) {
+	bulkErr := getTestBulkError()
+
+	assert.EqualError(
+		t,
+		bulkErr,
+		"ERPLY API: 2 of 4 bulk sub-requests failed, first failed sub-request 1 (saveProduct): ERPLY API: quota, status: error, code: 1002",
+	)
+	assert.Equal(t, []int{1, 3}, bulkErr.FailedIndexes())
+	assert.Equal(t, InvalidValue, bulkErr.ErrorAt(3).Code)
+	assert.Nil(t, bulkErr.ErrorAt(2))
+	assert.Equal(t, map[string]*ErplyError{"req1": bulkErr.Items[0].Err}, bulkErr.ErrorsByRequestID())
+	assert.False(t, bulkErr.IsRetryable(NewDefaultRetryPolicy()))
+
+	var erplyErr *ErplyError
+	assert.True(t, errors.As(bulkErr, &erplyErr))
+	assert.Equal(t, HourlyRequestQuota, erplyErr.Code)
+
+	wrappedErr, ok := AsBulkError(errors.Join(errors.New("import failed"), bulkErr))
+	assert.True(t, ok)
+	assert.Equal(t, bulkErr, wrappedErr)
+
+	failedItems, indexes := FailedBulkItems([]string{"a", "b", "c", "d"}, bulkErr)
+	assert.Equal(t, []string{"b", "d"}, failedItems)
+	assert.Equal(t, []int{1, 3}, indexes)
+
+	failedItems, indexes = FailedBulkItems([]string{"a"}, errors.New("some"))
+	assert.Nil(t, failedItems)
+	assert.Nil(t, indexes)
+}
+
+func TestRetryFailedBulkItemsStopsOnOtherErrors(t *testing.T) {
+	retryPolicy := RetryPolicy{MaxAttempts: 3, RetryableCodes: DefaultRetryableCodes}
+	callErr := errors.New("connection refused")
+	calledTimes := 0
+
+	err := RetryFailedBulkItems(
+		context.Background(),
+		[]string{"a", "b", "c", "d"},
+		getTestBulkError(),
+		retryPolicy,
+		func(time.Duration) {},
+		func(ctx context.Context, items []string, indexes []int) error {
+			calledTimes++
+			assert.Equal(t, []string{"b"}, items)
+			assert.Equal(t, []int{1}, indexes)
+			return callErr
+		},
+	)
+	assert.Equal(t, callErr, err)
+	assert.Equal(t, 1, calledTimes)
+
+	otherErr := errors.New("some")
+	assert.Equal(t, otherErr, RetryFailedBulkItems(context.Background(), []string{"a"}, otherErr, retryPolicy, nil, nil))
+	assert.Nil(t, RetryFailedBulkItems(context.Background(), []string{"a"}, nil, retryPolicy, nil, nil))
+}
+
+func TestRetryFailedBulkItemsWaitsWithContext(t *testing.T) {
+	retryPolicy := RetryPolicy{MaxAttempts: 3, InitialInterval: time.Hour, MaxInterval: time.Hour, RetryableCodes: DefaultRetryableCodes}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	startTime := time.Now()
+	err := RetryFailedBulkItems(
+		ctx,
+		[]string{"a", "b", "c", "d"},
+		getTestBulkError(),
+		retryPolicy,
+		nil,
+		func(ctx context.Context, items []string, indexes []int) error {
+			assert.Fail(t, "the items should not be sent after cancellation")
+			return nil
+		},
+	)
+	assert.Equal(t, context.Canceled, err)
+	assert.Less(t, time.Since(startTime), time.Minute)
+}
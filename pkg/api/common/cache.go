@@ -0,0 +1,306 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/erply/api-go-wrapper/pkg/api/log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+//DefaultCacheTTL is the lifetime of cached responses of the reference data methods
+const DefaultCacheTTL = 10 * time.Minute
+
+//DefaultCachedMethods are the API methods which return rarely changed reference data and their cache TTLs
+var DefaultCachedMethods = map[string]time.Duration{
+	"getVatRates":       DefaultCacheTTL,
+	"getWarehouses":     DefaultCacheTTL,
+	"getCurrencies":     DefaultCacheTTL,
+	"getCountries":      DefaultCacheTTL,
+	"getConfParameters": DefaultCacheTTL,
+	"getProductUnits":   DefaultCacheTTL,
+	"getPointsOfSale":   DefaultCacheTTL,
+}
+
+//DefaultCacheInvalidations maps the API methods which change the reference data to the cached methods
+//which responses are removed from the cache after them
+var DefaultCacheInvalidations = map[string][]string{
+	"saveVatRate":          {"getVatRates"},
+	"saveVatRateComponent": {"getVatRates"},
+	"saveWarehouse":        {"getWarehouses"},
+	"saveCurrency":         {"getCurrencies"},
+	"saveConfParameter":    {"getConfParameters"},
+	"saveProductUnit":      {"getProductUnits"},
+	"savePointOfSale":      {"getPointsOfSale"},
+}
+
+//CacheStorage keeps the cached response bodies, implement it to share the cache between processes e.g. in Redis
+type CacheStorage interface {
+	//Get gives the value and false if there is no value for the key or it's expired
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	//DeleteByPrefix removes all values which keys start with the prefix
+	DeleteByPrefix(ctx context.Context, prefix string) error
+}
+
+type inMemoryCacheItem struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+//InMemoryCacheStorage is the default CacheStorage which keeps the values in a map of the process
+type InMemoryCacheStorage struct {
+	items   map[string]inMemoryCacheItem
+	lock    sync.Mutex
+	timeNow func() time.Time
+}
+
+//NewInMemoryCacheStorage creates an empty InMemoryCacheStorage
+func NewInMemoryCacheStorage() *InMemoryCacheStorage {
+	return &InMemoryCacheStorage{
+		items:   map[string]inMemoryCacheItem{},
+		timeNow: time.Now,
+	}
+}
+
+//Get CacheStorage interface implementation, expired values are removed on reading
+func (ims *InMemoryCacheStorage) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	ims.lock.Lock()
+	defer ims.lock.Unlock()
+
+	item, ok := ims.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !ims.timeNow().Before(item.expiresAt) {
+		delete(ims.items, key)
+		return nil, false, nil
+	}
+
+	return item.value, true, nil
+}
+
+//Set CacheStorage interface implementation
+func (ims *InMemoryCacheStorage) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ims.lock.Lock()
+	defer ims.lock.Unlock()
+
+	now := ims.timeNow()
+	for k, item := range ims.items {
+		if !now.Before(item.expiresAt) {
+			delete(ims.items, k)
+		}
+	}
+	ims.items[key] = inMemoryCacheItem{value: value, expiresAt: now.Add(ttl)}
+
+	return nil
+}
+
+//DeleteByPrefix CacheStorage interface implementation
+func (ims *InMemoryCacheStorage) DeleteByPrefix(ctx context.Context, prefix string) error {
+	ims.lock.Lock()
+	defer ims.lock.Unlock()
+
+	for k := range ims.items {
+		if strings.HasPrefix(k, prefix) {
+			delete(ims.items, k)
+		}
+	}
+
+	return nil
+}
+
+//CacheSettings configures RequestCache
+type CacheSettings struct {
+	TTLs          map[string]time.Duration //cached API methods and their TTLs, DefaultCachedMethods if not set
+	Invalidations map[string][]string      //API methods which remove the responses of cached methods, DefaultCacheInvalidations if not set
+	Storage       CacheStorage             //NewInMemoryCacheStorage if not set
+	Namespace     string                   //prefix of the cache keys, e.g. the app name if the storage is shared between apps
+}
+
+type cacheCall struct {
+	done chan struct{}
+	resp *Response
+	err  error
+}
+
+type skipCacheKey struct{}
+
+//WithoutCache gives a context which makes RequestCache skip reading the cache, the fresh response is cached as usual
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipCacheKey{}, true)
+}
+
+//RequestCache is a Middleware which caches the successful responses of the configured API methods for their TTLs.
+//The responses depend on the client code, the method and the filters, the cached methods of the account are invalidated
+//after the calls of the methods from Invalidations including bulk sub-requests. Concurrent requests of a missing
+//response make only one API call. Bulk requests are never cached.
+type RequestCache struct {
+	settings CacheSettings
+	calls    map[string]*cacheCall
+	lock     sync.Mutex
+}
+
+//NewRequestCache creates RequestCache, add it to ClientBuilder.Cache or as the first middleware of the client
+func NewRequestCache(settings CacheSettings) *RequestCache {
+	if settings.TTLs == nil {
+		settings.TTLs = DefaultCachedMethods
+	}
+	if settings.Invalidations == nil {
+		settings.Invalidations = DefaultCacheInvalidations
+	}
+	if settings.Storage == nil {
+		settings.Storage = NewInMemoryCacheStorage()
+	}
+
+	return &RequestCache{
+		settings: settings,
+		calls:    map[string]*cacheCall{},
+	}
+}
+
+//Wrap Middleware interface implementation
+func (rc *RequestCache) Wrap(next RequestHandler) RequestHandler {
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		if req.IsBulk() {
+			return rc.callAndInvalidate(ctx, req, next)
+		}
+
+		ttl, isCached := rc.settings.TTLs[req.Method]
+		if !isCached || ttl <= 0 {
+			return rc.callAndInvalidate(ctx, req, next)
+		}
+
+		key := rc.getKey(req)
+		if skip, _ := ctx.Value(skipCacheKey{}).(bool); !skip {
+			body, found, err := rc.settings.Storage.Get(ctx, key)
+			if err != nil {
+				log.LogFields(log.Warn, "failed to read cache", log.F(log.FieldMethod, req.Method), log.F(log.FieldError, err))
+			} else if found {
+				return &Response{Body: body, Status: decodeCachedStatus(body)}, nil
+			}
+		}
+
+		return rc.callOnce(ctx, key, ttl, req, next)
+	}
+}
+
+//Invalidate removes the cached responses of the API methods of all accounts
+func (rc *RequestCache) Invalidate(ctx context.Context, methods ...string) error {
+	for _, method := range methods {
+		if err := rc.settings.Storage.DeleteByPrefix(ctx, rc.getMethodPrefix(method)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//callOnce makes one API call for concurrent requests with the same key, the other requests wait for its response.
+//If the call fails because its own context was cancelled, the waiting requests make the call again
+func (rc *RequestCache) callOnce(ctx context.Context, key string, ttl time.Duration, req *Request, next RequestHandler) (*Response, error) {
+	rc.lock.Lock()
+	for {
+		call, ok := rc.calls[key]
+		if !ok {
+			break
+		}
+		rc.lock.Unlock()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if call.err == nil {
+			return &Response{Body: call.resp.Body, Status: call.resp.Status}, nil
+		}
+		if !errors.Is(call.err, context.Canceled) && !errors.Is(call.err, context.DeadlineExceeded) {
+			return nil, call.err
+		}
+		rc.lock.Lock()
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	rc.calls[key] = call
+	rc.lock.Unlock()
+
+	defer func() {
+		rc.lock.Lock()
+		delete(rc.calls, key)
+		rc.lock.Unlock()
+		close(call.done)
+	}()
+
+	call.resp, call.err = next(ctx, req)
+	if call.err != nil {
+		return call.resp, call.err
+	}
+
+	if call.resp.Status != nil && strings.EqualFold(call.resp.Status.ResponseStatus, "ok") {
+		if err := rc.settings.Storage.Set(ctx, key, call.resp.Body, ttl); err != nil {
+			log.LogFields(log.Warn, "failed to write cache", log.F(log.FieldMethod, req.Method), log.F(log.FieldError, err))
+		}
+	}
+
+	return call.resp, nil
+}
+
+//callAndInvalidate calls the API and removes the responses of the methods which data could be changed by the call,
+//it's done even if the call fails as the data could be changed anyway
+func (rc *RequestCache) callAndInvalidate(ctx context.Context, req *Request, next RequestHandler) (*Response, error) {
+	resp, err := next(ctx, req)
+
+	methods := []string{req.Method}
+	for _, bulkInput := range req.BulkInputs {
+		methods = append(methods, bulkInput.MethodName)
+	}
+
+	invalidated := map[string]bool{}
+	for _, method := range methods {
+		for _, cachedMethod := range rc.settings.Invalidations[method] {
+			if invalidated[cachedMethod] {
+				continue
+			}
+			invalidated[cachedMethod] = true
+			invalidateErr := rc.settings.Storage.DeleteByPrefix(ctx, rc.getAccountPrefix(cachedMethod, req.ClientCode))
+			if invalidateErr != nil {
+				log.LogFields(log.Warn, "failed to invalidate cache", log.F(log.FieldMethod, cachedMethod), log.F(log.FieldError, invalidateErr))
+			}
+		}
+	}
+
+	return resp, err
+}
+
+func (rc *RequestCache) getMethodPrefix(method string) string {
+	return rc.settings.Namespace + ":" + method + ":"
+}
+
+//getAccountPrefix separates the responses of the accounts which share the cache
+func (rc *RequestCache) getAccountPrefix(method, clientCode string) string {
+	return rc.getMethodPrefix(method) + clientCode + ":"
+}
+
+func (rc *RequestCache) getKey(req *Request) string {
+	params := url.Values{}
+	for k, v := range req.Filters {
+		params.Set(k, v)
+	}
+
+	//Encode sorts the params by keys, so the same filters give the same key
+	return rc.getAccountPrefix(req.Method, req.ClientCode) + params.Encode()
+}
+
+func decodeCachedStatus(body []byte) *Status {
+	resp := struct {
+		Status *Status `json:"status"`
+	}{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil
+	}
+
+	return resp.Status
+}
@@ -0,0 +1,214 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newCacheTestHandler(calls *int32, release <-chan struct{}) RequestHandler {
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		callNo := atomic.AddInt32(calls, 1)
+		if release != nil {
+			<-release
+		}
+		if req.Filters["fail"] == "transport" {
+			return nil, errors.New("connection refused")
+		}
+		if req.Filters["fail"] == "api" {
+			body := []byte(`{"status":{"responseStatus":"error","errorCode":1002}}`)
+			return &Response{Body: body, Status: &Status{ResponseStatus: "error", ErrorCode: HourlyRequestQuota}}, nil
+		}
+
+		responseStatus := "ok"
+		if req.Filters["responseStatus"] != "" {
+			responseStatus = req.Filters["responseStatus"]
+		}
+		body := []byte(fmt.Sprintf(`{"status":{"request":"%s","responseStatus":"%s"},"records":[{"call":%d}]}`, req.Method, responseStatus, callNo))
+		return &Response{Body: body, Status: &Status{Request: req.Method, ResponseStatus: responseStatus}}, nil
+	}
+}
+
+func TestRequestCache(t *testing.T) {
+	var calls int32
+	storage := NewInMemoryCacheStorage()
+	now := time.Unix(1600000000, 0)
+	storage.timeNow = func() time.Time {
+		return now
+	}
+	cache := NewRequestCache(CacheSettings{
+		TTLs:    map[string]time.Duration{"getVatRates": time.Minute, "getWarehouses": time.Hour},
+		Storage: storage,
+	})
+	handler := cache.Wrap(newCacheTestHandler(&calls, nil))
+	ctx := context.Background()
+
+	send := func(ctx context.Context, method string, filters map[string]string) string {
+		resp, err := handler(ctx, &Request{Method: method, Filters: filters})
+		assert.NoError(t, err)
+		if err != nil {
+			return ""
+		}
+		return string(resp.Body)
+	}
+
+	first := send(ctx, "getVatRates", map[string]string{"active": "1", "searchAttributeName": "x"})
+	assert.Contains(t, first, `"call":1`)
+	assert.Equal(t, first, send(ctx, "getVatRates", map[string]string{"searchAttributeName": "x", "active": "1"}))
+	assert.Contains(t, send(ctx, "getVatRates", map[string]string{"active": "0"}), `"call":2`)
+	assert.Contains(t, send(ctx, "getWarehouses", nil), `"call":3`)
+	assert.Contains(t, send(ctx, "getProducts", nil), `"call":4`)
+	assert.Contains(t, send(ctx, "getProducts", nil), `"call":5`)
+
+	resp, err := handler(ctx, &Request{Method: "getWarehouses"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp.Status.ResponseStatus)
+	assert.Equal(t, int32(5), calls)
+
+	//the ttl is per method
+	now = now.Add(2 * time.Minute)
+	assert.Contains(t, send(ctx, "getVatRates", map[string]string{"active": "0"}), `"call":6`)
+	assert.Contains(t, send(ctx, "getWarehouses", nil), `"call":3`)
+
+	//invalidation after a save request and a bulk sub-request
+	send(ctx, "saveVatRate", map[string]string{"name": "20%"})
+	assert.Contains(t, send(ctx, "getVatRates", map[string]string{"active": "0"}), `"call":8`)
+	assert.Contains(t, send(ctx, "getWarehouses", nil), `"call":3`)
+
+	_, err = handler(ctx, &Request{BulkInputs: []BulkInput{{MethodName: "saveWarehouse"}}, Filters: map[string]string{}})
+	assert.NoError(t, err)
+	assert.Contains(t, send(ctx, "getWarehouses", nil), `"call":10`)
+
+	assert.Contains(t, send(WithoutCache(ctx), "getWarehouses", nil), `"call":11`)
+	assert.Contains(t, send(ctx, "getWarehouses", nil), `"call":11`)
+
+	assert.NoError(t, cache.Invalidate(ctx, "getWarehouses"))
+	assert.Contains(t, send(ctx, "getWarehouses", nil), `"call":12`)
+}
+
+func TestRequestCacheSeparatesAccounts(t *testing.T) {
+	var calls int32
+	cache := NewRequestCache(CacheSettings{})
+	handler := cache.Wrap(newCacheTestHandler(&calls, nil))
+	ctx := context.Background()
+
+	send := func(clientCode, method string, filters map[string]string) string {
+		resp, err := handler(ctx, &Request{Method: method, Filters: filters, ClientCode: clientCode})
+		assert.NoError(t, err)
+		if err != nil {
+			return ""
+		}
+		return string(resp.Body)
+	}
+
+	assert.Contains(t, send("111", "getVatRates", nil), `"call":1`)
+	assert.Contains(t, send("222", "getVatRates", nil), `"call":2`)
+	assert.Contains(t, send("111", "getVatRates", nil), `"call":1`)
+	assert.Contains(t, send("222", "getVatRates", nil), `"call":2`)
+
+	//the save of an account invalidates only its responses
+	send("111", "saveVatRate", nil)
+	assert.Contains(t, send("111", "getVatRates", nil), `"call":4`)
+	assert.Contains(t, send("222", "getVatRates", nil), `"call":2`)
+
+	assert.NoError(t, cache.Invalidate(ctx, "getVatRates"))
+	assert.Contains(t, send("111", "getVatRates", nil), `"call":5`)
+	assert.Contains(t, send("222", "getVatRates", nil), `"call":6`)
+
+	//the status is compared case insensitive
+	assert.Contains(t, send("111", "getWarehouses", map[string]string{"responseStatus": "OK"}), `"call":7`)
+	assert.Contains(t, send("111", "getWarehouses", map[string]string{"responseStatus": "OK"}), `"call":7`)
+}
+
+func TestRequestCacheSkipsErrors(t *testing.T) {
+	var calls int32
+	handler := NewRequestCache(CacheSettings{}).Wrap(newCacheTestHandler(&calls, nil))
+
+	for i := 0; i < 2; i++ {
+		_, err := handler(context.Background(), &Request{Method: "getCountries", Filters: map[string]string{"fail": "transport"}})
+		assert.Error(t, err)
+
+		resp, err := handler(context.Background(), &Request{Method: "getCountries", Filters: map[string]string{"fail": "api"}})
+		assert.NoError(t, err)
+		assert.Equal(t, HourlyRequestQuota, resp.Status.ErrorCode)
+	}
+	assert.Equal(t, int32(4), calls)
+}
+
+func TestRequestCacheMakesOneCallForConcurrentMisses(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	handler := NewRequestCache(CacheSettings{}).Wrap(newCacheTestHandler(&calls, release))
+
+	wg := sync.WaitGroup{}
+	bodies := make([]string, 10)
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := handler(context.Background(), &Request{Method: "getCurrencies", Filters: map[string]string{}})
+			assert.NoError(t, err)
+			if err == nil {
+				bodies[i] = string(resp.Body)
+			}
+		}(i)
+	}
+
+	//the waiting requests give up on their context without affecting the call
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	time.Sleep(time.Millisecond * 10)
+	_, err := handler(ctx, &Request{Method: "getCurrencies", Filters: map[string]string{}})
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls)
+	for _, body := range bodies {
+		assert.Contains(t, body, `"call":1`)
+	}
+}
+
+func TestRequestCacheRepeatsCallCancelledByItsContext(t *testing.T) {
+	var calls int32
+	started := make(chan struct{})
+	handler := NewRequestCache(CacheSettings{}).Wrap(func(ctx context.Context, req *Request) (*Response, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		body := []byte(`{"status":{"responseStatus":"ok"},"records":[]}`)
+		return &Response{Body: body, Status: &Status{ResponseStatus: "ok"}}, nil
+	})
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderDone := make(chan error)
+	go func() {
+		_, err := handler(leaderCtx, &Request{Method: "getCurrencies", Filters: map[string]string{}})
+		leaderDone <- err
+	}()
+	<-started
+
+	waiterDone := make(chan error)
+	go func() {
+		resp, err := handler(context.Background(), &Request{Method: "getCurrencies", Filters: map[string]string{}})
+		if err == nil {
+			assert.Equal(t, "ok", resp.Status.ResponseStatus)
+		}
+		waiterDone <- err
+	}()
+	time.Sleep(time.Millisecond * 10)
+	cancel()
+
+	assert.Equal(t, context.Canceled, <-leaderDone)
+	//the waiter doesn't get the cancellation of the leader and makes the call itself
+	assert.NoError(t, <-waiterDone)
+	assert.Equal(t, int32(2), calls)
+}
@@ -0,0 +1,403 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/erply/api-go-wrapper/pkg/api/log"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//CassetteMode tells if CassetteRecorder sends requests to the API or serves the recorded responses
+type CassetteMode int
+
+const (
+	//CassetteModeReplay serves the responses from the cassette file and never calls the API
+	CassetteModeReplay CassetteMode = iota
+	//CassetteModeRecord calls the API and records all request/response pairs, the cassette is written by Save
+	CassetteModeRecord
+	//CassetteModeReplayOrRecord replays the cassette file if it exists, otherwise records it
+	CassetteModeReplayOrRecord
+)
+
+const (
+	cassetteRequestNameParam = "request"
+	cassetteBulkRequestParam = "requests"
+	cassetteBulkNameParam    = "requestName"
+)
+
+//DefaultCassetteScrubbedFields are hidden in the recorded requests and responses in addition to log.DefaultRedactedFields
+var DefaultCassetteScrubbedFields = []string{
+	"partnerKey",
+	"clientCode",
+}
+
+//Cassette is the content of a cassette file
+type Cassette struct {
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+//CassetteInteraction is a recorded request with its response
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+//CassetteRequest identifies a request by the API method name and the normalized filters,
+//for a bulk request the name is the comma separated list of sub-requests methods and the requests filter is
+//the normalized list of sub-requests
+type CassetteRequest struct {
+	Name    string            `json:"name"`
+	Filters map[string]string `json:"filters"`
+}
+
+//CassetteResponse is a recorded response
+type CassetteResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body"`
+}
+
+//CassetteRecorder is an http.RoundTripper which records the Erply API calls to a cassette file and replays them,
+//so the tests of the managers run with no network. The secrets are scrubbed from the cassette, so it can be committed.
+//Set it as the transport of the http client given to the ClientBuilder.
+type CassetteRecorder struct {
+	path         string
+	mode         CassetteMode
+	transport    http.RoundTripper
+	redactor     *log.Redactor
+	interactions []CassetteInteraction
+	replayed     map[string]int
+	lock         sync.Mutex
+}
+
+//NewCassetteRecorder creates CassetteRecorder for the cassette file, in replay mode the file should exist,
+//the transport is used for recording, http.DefaultTransport is used if it's nil
+func NewCassetteRecorder(path string, mode CassetteMode, transport http.RoundTripper) (*CassetteRecorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	cr := &CassetteRecorder{
+		path:      path,
+		mode:      mode,
+		transport: transport,
+		redactor:  log.NewRedactor(DefaultCassetteScrubbedFields...),
+		replayed:  map[string]int{},
+	}
+	cr.redactor.AddFields(log.DefaultRedactedFields...)
+
+	if mode == CassetteModeReplayOrRecord {
+		if _, err := os.Stat(path); err == nil {
+			cr.mode = CassetteModeReplay
+		} else {
+			cr.mode = CassetteModeRecord
+		}
+	}
+
+	if cr.mode == CassetteModeReplay {
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		cr.interactions = cassette.Interactions
+	}
+
+	return cr, nil
+}
+
+//LoadCassette reads the cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette %s: %v", path, err)
+	}
+
+	cassette := &Cassette{}
+	if err := json.Unmarshal(content, cassette); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cassette %s: %v", path, err)
+	}
+
+	return cassette, nil
+}
+
+//ScrubFields adds more fields which should be hidden in the cassette and ignored while matching requests
+func (cr *CassetteRecorder) ScrubFields(fields ...string) {
+	cr.redactor.AddFields(fields...)
+}
+
+//IsRecording tells if the requests are sent to the API
+func (cr *CassetteRecorder) IsRecording() bool {
+	return cr.mode == CassetteModeRecord
+}
+
+//Interactions gives the recorded or loaded interactions
+func (cr *CassetteRecorder) Interactions() []CassetteInteraction {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	return append([]CassetteInteraction{}, cr.interactions...)
+}
+
+//RoundTrip http.RoundTripper interface implementation
+func (cr *CassetteRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	cassetteReq, body, err := cr.toCassetteRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cr.mode == CassetteModeReplay {
+		return cr.replay(req, cassetteReq)
+	}
+
+	return cr.record(req, cassetteReq, body)
+}
+
+func (cr *CassetteRecorder) replay(req *http.Request, cassetteReq CassetteRequest) (*http.Response, error) {
+	key := cassetteReq.key()
+
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	//the same request can be recorded several times e.g. while listing changed data, they are served in the recorded order,
+	//the last one is repeated if the request is sent more times than recorded
+	matches := make([]CassetteInteraction, 0, 1)
+	for _, interaction := range cr.interactions {
+		if interaction.Request.key() == key {
+			matches = append(matches, interaction)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no interaction is recorded in cassette %s for request %s", cr.path, key)
+	}
+
+	index := cr.replayed[key]
+	if index >= len(matches) {
+		index = len(matches) - 1
+	}
+	cr.replayed[key]++
+
+	return matches[index].Response.toHTTPResponse(req), nil
+}
+
+func (cr *CassetteRecorder) record(req *http.Request, cassetteReq CassetteRequest, body []byte) (*http.Response, error) {
+	outReq := req.Clone(req.Context())
+	if body != nil {
+		outReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := cr.transport.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := resp.Header.Clone()
+	headers.Del("Set-Cookie")
+
+	cr.lock.Lock()
+	cr.interactions = append(cr.interactions, CassetteInteraction{
+		Request: cassetteReq,
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    headers,
+			Body:       cr.scrubBody(respBody),
+		},
+	})
+	cr.lock.Unlock()
+
+	//the caller gets the original response, only the cassette is scrubbed
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	return resp, nil
+}
+
+//Save writes the recorded interactions to the cassette file, it does nothing in replay mode
+func (cr *CassetteRecorder) Save() error {
+	if cr.mode != CassetteModeRecord {
+		return nil
+	}
+
+	cr.lock.Lock()
+	content, err := json.MarshalIndent(Cassette{Interactions: cr.interactions}, "", "  ")
+	cr.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cr.path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(cr.path, content, 0644)
+}
+
+//toCassetteRequest extracts the API method name and filters from the query and the form body of the request,
+//it gives the read body, so it can be sent again
+func (cr *CassetteRecorder) toCassetteRequest(req *http.Request) (CassetteRequest, []byte, error) {
+	params := url.Values{}
+	for k, v := range req.URL.Query() {
+		params[k] = v
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return CassetteRequest{}, nil, err
+		}
+		_ = req.Body.Close()
+
+		if strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+			formParams, err := url.ParseQuery(string(body))
+			if err != nil {
+				return CassetteRequest{}, nil, fmt.Errorf("failed to parse form body of the request: %v", err)
+			}
+			for k, v := range formParams {
+				params[k] = append(params[k], v...)
+			}
+		}
+	}
+
+	cassetteReq := CassetteRequest{
+		Name:    params.Get(cassetteRequestNameParam),
+		Filters: map[string]string{},
+	}
+	for k := range params {
+		if k == cassetteRequestNameParam || k == cassetteBulkRequestParam {
+			continue
+		}
+		cassetteReq.Filters[k] = fmt.Sprint(cr.redactor.Redact(k, params.Get(k)))
+	}
+
+	if bulkRequests := params.Get(cassetteBulkRequestParam); bulkRequests != "" {
+		names, normalizedRequests, err := cr.normalizeBulkRequests(bulkRequests)
+		if err != nil {
+			return CassetteRequest{}, nil, err
+		}
+		cassetteReq.Name = strings.Join(names, ",")
+		cassetteReq.Filters[cassetteBulkRequestParam] = normalizedRequests
+	}
+
+	return cassetteReq, body, nil
+}
+
+//normalizeBulkRequests gives the methods of the sub-requests and their filters where the keys are sorted
+//and all values are strings, so e.g. 1 and "1" match
+func (cr *CassetteRecorder) normalizeBulkRequests(bulkRequests string) ([]string, string, error) {
+	decoder := json.NewDecoder(strings.NewReader(bulkRequests))
+	decoder.UseNumber()
+
+	var subRequests []map[string]interface{}
+	if err := decoder.Decode(&subRequests); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal bulk requests '%s': %v", bulkRequests, err)
+	}
+
+	names := make([]string, 0, len(subRequests))
+	normalizedRequests := make([]map[string]string, 0, len(subRequests))
+	for _, subRequest := range subRequests {
+		names = append(names, fmt.Sprint(subRequest[cassetteBulkNameParam]))
+
+		normalizedRequest := make(map[string]string, len(subRequest))
+		for k, v := range subRequest {
+			normalizedRequest[k] = fmt.Sprint(cr.redactor.Redact(k, fmt.Sprint(v)))
+		}
+		normalizedRequests = append(normalizedRequests, normalizedRequest)
+	}
+
+	res, err := json.Marshal(normalizedRequests)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return names, string(res), nil
+}
+
+//scrubBody hides the secret fields in a JSON body keeping it valid JSON, other bodies are scrubbed as text
+func (cr *CassetteRecorder) scrubBody(body []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var content interface{}
+	if err := decoder.Decode(&content); err != nil {
+		return cr.redactor.RedactString(string(body))
+	}
+
+	scrubbed, err := json.Marshal(cr.scrubJSONValue(content))
+	if err != nil {
+		return cr.redactor.RedactString(string(body))
+	}
+
+	return string(scrubbed)
+}
+
+func (cr *CassetteRecorder) scrubJSONValue(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for k, v := range typedValue {
+			if cr.redactor.IsRedactedField(k) {
+				typedValue[k] = log.RedactedValue
+				continue
+			}
+			typedValue[k] = cr.scrubJSONValue(v)
+		}
+		return typedValue
+	case []interface{}:
+		for i, v := range typedValue {
+			typedValue[i] = cr.scrubJSONValue(v)
+		}
+		return typedValue
+	case string:
+		return cr.redactor.RedactString(typedValue)
+	default:
+		return value
+	}
+}
+
+//key identifies the request regardless of the order of filters
+func (cr CassetteRequest) key() string {
+	keys := make([]string, 0, len(cr.Filters))
+	for k := range cr.Filters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+cr.Filters[k])
+	}
+
+	return cr.Name + "?" + strings.Join(pairs, "&")
+}
+
+func (cr CassetteResponse) toHTTPResponse(req *http.Request) *http.Response {
+	headers := cr.Headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", cr.StatusCode, http.StatusText(cr.StatusCode)),
+		StatusCode:    cr.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          ioutil.NopCloser(strings.NewReader(cr.Body)),
+		ContentLength: int64(len(cr.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,183 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sendCassetteTestRequest(t *testing.T, cli *http.Client, baseURL string, query url.Values, form url.Values) string {
+	var body *strings.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	} else {
+		body = strings.NewReader("")
+	}
+
+	req, err := http.NewRequest("POST", baseURL+"?"+query.Encode(), body)
+	assert.NoError(t, err)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := cli.Do(req)
+	assert.NoError(t, err)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	return string(respBody)
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	calledTimes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calledTimes++
+		assert.NoError(t, r.ParseForm())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.FormValue("request") {
+		case "verifyUser":
+			_, _ = w.Write([]byte(`{"status":{"responseStatus":"ok"},"records":[{"sessionKey":"realsess","token":"eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.abc","userID":"1"}]}`))
+		case "getProducts":
+			_, _ = w.Write([]byte(`{"status":{"responseStatus":"ok"},"records":[{"productID":` + r.FormValue("pageNo") + `}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":{"responseStatus":"ok"},"requests":[{"status":{"responseStatus":"ok"}}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	cassettePath := filepath.Join(t.TempDir(), "cassettes", "products.json")
+	recorder, err := NewCassetteRecorder(cassettePath, CassetteModeReplayOrRecord, nil)
+	assert.NoError(t, err)
+	assert.True(t, recorder.IsRecording())
+
+	cli := &http.Client{Transport: recorder}
+	verifyUserResp := sendCassetteTestRequest(
+		t,
+		cli,
+		srv.URL,
+		url.Values{"request": {"verifyUser"}, "clientCode": {"123"}},
+		url.Values{"username": {"user"}, "password": {"secretpass"}},
+	)
+	assert.Contains(t, verifyUserResp, "realsess")
+
+	productsResp := sendCassetteTestRequest(
+		t,
+		cli,
+		srv.URL,
+		url.Values{"request": {"getProducts"}, "sessionKey": {"realsess"}, "clientCode": {"123"}, "pageNo": {"2"}},
+		nil,
+	)
+	assert.Contains(t, productsResp, `"productID":2`)
+
+	bulkResp := sendCassetteTestRequest(
+		t,
+		cli,
+		srv.URL,
+		url.Values{"clientCode": {"123"}},
+		url.Values{"sessionKey": {"realsess"}, "requests": {`[{"requestName":"getProducts","pageNo":1}]`}},
+	)
+	assert.Contains(t, bulkResp, `"requests"`)
+
+	assert.NoError(t, recorder.Save())
+	assert.Equal(t, 3, calledTimes)
+
+	cassetteContent, err := ioutil.ReadFile(cassettePath)
+	assert.NoError(t, err)
+	for _, secret := range []string{"realsess", "secretpass", "eyJhbGciOiJIUzI1NiJ9"} {
+		assert.NotContains(t, string(cassetteContent), secret)
+	}
+
+	interactions := recorder.Interactions()
+	assert.Len(t, interactions, 3)
+	assert.Equal(t, "verifyUser", interactions[0].Request.Name)
+	assert.Equal(t, "getProducts", interactions[1].Request.Name)
+	assert.Equal(t, "getProducts", interactions[2].Request.Name)
+	assert.Equal(t, `[{"pageNo":"1","requestName":"getProducts"}]`, interactions[2].Request.Filters["requests"])
+
+	srv.Close()
+
+	replayer, err := NewCassetteRecorder(cassettePath, CassetteModeReplayOrRecord, nil)
+	assert.NoError(t, err)
+	assert.False(t, replayer.IsRecording())
+
+	cli = &http.Client{Transport: replayer}
+
+	//the secrets and the client code are ignored while matching and the filters are normalized
+	productsResp = sendCassetteTestRequest(
+		t,
+		cli,
+		srv.URL,
+		url.Values{"pageNo": {"2"}, "request": {"getProducts"}, "sessionKey": {"othersess"}, "clientCode": {"456"}},
+		nil,
+	)
+	assert.Contains(t, productsResp, `"productID":2`)
+
+	bulkResp = sendCassetteTestRequest(
+		t,
+		cli,
+		srv.URL,
+		url.Values{"clientCode": {"456"}},
+		url.Values{"sessionKey": {"othersess"}, "requests": {`[{"pageNo":"1","requestName":"getProducts"}]`}},
+	)
+	assert.Contains(t, bulkResp, `"requests"`)
+
+	verifyUserResp = sendCassetteTestRequest(
+		t,
+		cli,
+		srv.URL,
+		url.Values{"request": {"verifyUser"}, "clientCode": {"456"}},
+		url.Values{"username": {"user"}, "password": {"otherpass"}},
+	)
+	assert.Contains(t, verifyUserResp, `"sessionKey":"[REDACTED]"`)
+
+	req, err := http.NewRequest("POST", srv.URL+"?request=getProducts&pageNo=3", nil)
+	assert.NoError(t, err)
+	_, err = cli.Do(req)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no interaction is recorded")
+}
+
+func TestCassetteReplaysRepeatedRequestsInOrder(t *testing.T) {
+	calledTimes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calledTimes++
+		if calledTimes == 1 {
+			_, _ = w.Write([]byte(`{"status":{"responseStatus":"error","errorCode":1002}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":{"responseStatus":"ok"}}`))
+	}))
+	defer srv.Close()
+
+	cassettePath := filepath.Join(t.TempDir(), "retries.json")
+	recorder, err := NewCassetteRecorder(cassettePath, CassetteModeRecord, nil)
+	assert.NoError(t, err)
+
+	query := url.Values{"request": {"getProducts"}}
+	sendCassetteTestRequest(t, &http.Client{Transport: recorder}, srv.URL, query, nil)
+	sendCassetteTestRequest(t, &http.Client{Transport: recorder}, srv.URL, query, nil)
+	assert.NoError(t, recorder.Save())
+
+	replayer, err := NewCassetteRecorder(cassettePath, CassetteModeReplay, nil)
+	assert.NoError(t, err)
+	cli := &http.Client{Transport: replayer}
+
+	assert.Contains(t, sendCassetteTestRequest(t, cli, srv.URL, query, nil), "1002")
+	assert.Contains(t, sendCassetteTestRequest(t, cli, srv.URL, query, nil), `"ok"`)
+	assert.Contains(t, sendCassetteTestRequest(t, cli, srv.URL, query, nil), `"ok"`)
+}
+
+func TestCassetteReplayFailsWithoutFile(t *testing.T) {
+	_, err := NewCassetteRecorder(filepath.Join(t.TempDir(), "missing.json"), CassetteModeReplay, nil)
+	assert.Error(t, err)
+}
@@ -39,18 +39,23 @@ func (c Connector) Run() error {
 		switch e := err.(type) {
 		case *ErplyError:
 			if e.Code == APISessionExpired {
-				log.Log.Log(log.Error, "failed to connect because auth session is expired: %v", err)
-				log.Log.Log(log.Debug, "will invalidate session")
+				log.LogFields(log.Error, "failed to connect because auth session is expired", log.F(log.FieldError, err))
+				log.LogFields(log.Debug, "will invalidate session")
 				err := c.SessionCleaner()
 				if err != nil {
 					return err
 				}
 			}
 		default:
-			log.Log.Log(log.Error, "failed to connect: %v", err)
+			log.LogFields(log.Error, "failed to connect", log.F(log.FieldError, err))
 		}
 
-		log.Log.Log(log.Debug, "will retry the connection attempt after %v sleep interval, connections attempts left: %d", c.WaitingInterval, c.AttemptsCount-i)
+		log.LogFields(
+			log.Debug,
+			"will retry the connection attempt",
+			log.F("sleepInterval", c.WaitingInterval),
+			log.F("attemptsLeft", c.AttemptsCount-i),
+		)
 		c.Waiter.Wait(c.WaitingInterval * time.Duration(c.WaitingIncrementCoeff*i+1))
 	}
 
@@ -0,0 +1,432 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	//DefaultSyncBatchSize is the count of events which are given to SyncSink at once
+	DefaultSyncBatchSize = 100
+	//DefaultSyncSafetyWindow is subtracted from the start time of a sync run to get the highest possible checkpoint,
+	//it covers the records which are changed while the run reads them and the clock difference with the API
+	DefaultSyncSafetyWindow = time.Minute
+	//ChangedSinceFilter is the filter of get requests which selects the records changed after a unix timestamp
+	ChangedSinceFilter = "changedSince"
+	//lastModifiedLayout is the format of lastModified values which are given as date time strings
+	lastModifiedLayout = "2006-01-02 15:04:05"
+)
+
+//Checkpoint is the state of the delta sync of one entity
+type Checkpoint struct {
+	Entity       string `json:"entity"`
+	ChangedSince int64  `json:"changedSince"` //the next run reads the records changed since this unix timestamp
+	SyncedAt     int64  `json:"syncedAt"`     //unix timestamp of the run which saved the checkpoint
+}
+
+//Advance gives the checkpoint after a successful run which started at startTime and got the records changed
+//up to maxSeen, it never goes back and never goes beyond startTime minus safetyWindow
+func (c Checkpoint) Advance(maxSeen int64, startTime time.Time, safetyWindow time.Duration) Checkpoint {
+	next := Checkpoint{
+		Entity:       c.Entity,
+		ChangedSince: c.ChangedSince,
+		SyncedAt:     startTime.Unix(),
+	}
+	if maxSeen > next.ChangedSince {
+		next.ChangedSince = maxSeen
+	}
+	if safeLimit := startTime.Add(-safetyWindow).Unix(); next.ChangedSince > safeLimit {
+		next.ChangedSince = safeLimit
+	}
+	if next.ChangedSince < c.ChangedSince {
+		next.ChangedSince = c.ChangedSince
+	}
+
+	return next
+}
+
+//CheckpointStore persists the checkpoints of DeltaSync, store them in the same database as the synced data
+//to keep them consistent
+type CheckpointStore interface {
+	//LoadCheckpoint gives false if the entity was never synced
+	LoadCheckpoint(ctx context.Context, entity string) (Checkpoint, bool, error)
+	SaveCheckpoint(ctx context.Context, checkpoint Checkpoint) error
+}
+
+//InMemoryCheckpointStore keeps the checkpoints in memory of the process, mostly for tests
+type InMemoryCheckpointStore struct {
+	checkpoints map[string]Checkpoint
+	lock        sync.Mutex
+}
+
+//NewInMemoryCheckpointStore creates an empty InMemoryCheckpointStore
+func NewInMemoryCheckpointStore() *InMemoryCheckpointStore {
+	return &InMemoryCheckpointStore{checkpoints: map[string]Checkpoint{}}
+}
+
+//LoadCheckpoint CheckpointStore interface implementation
+func (imcs *InMemoryCheckpointStore) LoadCheckpoint(ctx context.Context, entity string) (Checkpoint, bool, error) {
+	imcs.lock.Lock()
+	defer imcs.lock.Unlock()
+
+	checkpoint, ok := imcs.checkpoints[entity]
+	return checkpoint, ok, nil
+}
+
+//SaveCheckpoint CheckpointStore interface implementation
+func (imcs *InMemoryCheckpointStore) SaveCheckpoint(ctx context.Context, checkpoint Checkpoint) error {
+	imcs.lock.Lock()
+	defer imcs.lock.Unlock()
+
+	imcs.checkpoints[checkpoint.Entity] = checkpoint
+	return nil
+}
+
+//FileCheckpointStore keeps the checkpoints of all entities in a JSON file, the file is replaced atomically on saving
+type FileCheckpointStore struct {
+	path string
+	lock sync.Mutex
+}
+
+//NewFileCheckpointStore creates FileCheckpointStore, the file is created with the first saved checkpoint
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+//LoadCheckpoint CheckpointStore interface implementation
+func (fcs *FileCheckpointStore) LoadCheckpoint(ctx context.Context, entity string) (Checkpoint, bool, error) {
+	fcs.lock.Lock()
+	defer fcs.lock.Unlock()
+
+	checkpoints, err := fcs.read()
+	if err != nil {
+		return Checkpoint{}, false, err
+	}
+
+	checkpoint, ok := checkpoints[entity]
+	return checkpoint, ok, nil
+}
+
+//SaveCheckpoint CheckpointStore interface implementation
+func (fcs *FileCheckpointStore) SaveCheckpoint(ctx context.Context, checkpoint Checkpoint) error {
+	fcs.lock.Lock()
+	defer fcs.lock.Unlock()
+
+	checkpoints, err := fcs.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[checkpoint.Entity] = checkpoint
+
+	content, err := json.MarshalIndent(checkpoints, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fcs.path), 0755); err != nil {
+		return err
+	}
+
+	tmpPath := fcs.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, content, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, fcs.path)
+}
+
+func (fcs *FileCheckpointStore) read() (map[string]Checkpoint, error) {
+	checkpoints := map[string]Checkpoint{}
+
+	content, err := ioutil.ReadFile(fcs.path)
+	if os.IsNotExist(err) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, &checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to read checkpoints from %s: %v", fcs.path, err)
+	}
+
+	return checkpoints, nil
+}
+
+//SyncEvent is an upsert of a created or changed record
+type SyncEvent struct {
+	Entity       string
+	Payload      interface{} //the record as the DataProvider gives it, e.g. products.Product
+	LastModified int64
+}
+
+//SyncSink receives the changed records, the upserts should be idempotent as the records changed close to
+//the checkpoint and the records of a failed run are given again in the next run
+type SyncSink interface {
+	Upsert(ctx context.Context, events []SyncEvent) error
+}
+
+//SyncSinkFunc allows using ordinary functions as SyncSink
+type SyncSinkFunc func(ctx context.Context, events []SyncEvent) error
+
+//Upsert SyncSink interface implementation
+func (ssf SyncSinkFunc) Upsert(ctx context.Context, events []SyncEvent) error {
+	return ssf(ctx, events)
+}
+
+//SyncEntity describes an entity which is synced by DeltaSync
+type SyncEntity struct {
+	Name         string                       //key of the checkpoint, e.g. "products"
+	DataProvider DataProvider                 //the listing provider of the entity, e.g. products.NewListingDataProvider
+	Filters      map[string]interface{}       //additional filters of the get requests
+	LastModified func(item interface{}) int64 //gives lastModified of a record, the LastModified field is required if not set
+}
+
+//SyncResult is the outcome of a sync run of one entity
+type SyncResult struct {
+	Entity             string
+	UpsertedCount      int
+	PreviousCheckpoint Checkpoint
+	Checkpoint         Checkpoint //equals PreviousCheckpoint if the run failed
+}
+
+//DeltaSyncSettings configures DeltaSync
+type DeltaSyncSettings struct {
+	ListingSettings ListingSettings
+	CheckpointStore CheckpointStore //NewInMemoryCheckpointStore if not set
+	BatchSize       int             //DefaultSyncBatchSize if not set
+	SafetyWindow    time.Duration   //DefaultSyncSafetyWindow if not set
+}
+
+//DeltaSync mirrors entities to SyncSink reading only the records changed since the checkpoint of the previous run.
+//The checkpoint is the highest lastModified of the synced records limited by the start time of the run minus
+//the safety window. It's saved exactly once per run after SyncSink accepted all records of the run,
+//a failed run doesn't move it, so no changes are lost and the next run repeats the failed one.
+type DeltaSync struct {
+	settings    DeltaSyncSettings
+	sink        SyncSink
+	entityLocks map[string]*sync.Mutex
+	lock        sync.Mutex
+	timeNow     func() time.Time
+}
+
+//NewDeltaSync creates DeltaSync which gives the changed records to the sink
+func NewDeltaSync(settings DeltaSyncSettings, sink SyncSink) *DeltaSync {
+	if settings.CheckpointStore == nil {
+		settings.CheckpointStore = NewInMemoryCheckpointStore()
+	}
+	if settings.BatchSize <= 0 {
+		settings.BatchSize = DefaultSyncBatchSize
+	}
+	if settings.SafetyWindow <= 0 {
+		settings.SafetyWindow = DefaultSyncSafetyWindow
+	}
+
+	return &DeltaSync{
+		settings:    settings,
+		sink:        sink,
+		entityLocks: map[string]*sync.Mutex{},
+		timeNow:     time.Now,
+	}
+}
+
+//RunAll syncs the entities one after another, a failed entity doesn't stop the others, the errors are joined
+func (ds *DeltaSync) RunAll(ctx context.Context, entities ...SyncEntity) ([]SyncResult, error) {
+	results := make([]SyncResult, 0, len(entities))
+	var errs []error
+	for _, entity := range entities {
+		result, err := ds.Run(ctx, entity)
+		results = append(results, result)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+//Run syncs the records of the entity changed since its checkpoint, runs of the same entity don't overlap
+func (ds *DeltaSync) Run(ctx context.Context, entity SyncEntity) (SyncResult, error) {
+	entityLock := ds.getEntityLock(entity.Name)
+	entityLock.Lock()
+	defer entityLock.Unlock()
+
+	result := SyncResult{Entity: entity.Name}
+
+	previousCheckpoint, found, err := ds.settings.CheckpointStore.LoadCheckpoint(ctx, entity.Name)
+	if err != nil {
+		return result, fmt.Errorf("failed to load the checkpoint of %s: %w", entity.Name, err)
+	}
+	if !found {
+		previousCheckpoint = Checkpoint{Entity: entity.Name}
+	}
+	result.PreviousCheckpoint = previousCheckpoint
+	result.Checkpoint = previousCheckpoint
+
+	startTime := ds.timeNow()
+	maxLastModified, upsertedCount, err := ds.sync(ctx, entity, previousCheckpoint)
+	result.UpsertedCount = upsertedCount
+	if err != nil {
+		return result, fmt.Errorf("failed to sync %s: %w", entity.Name, err)
+	}
+
+	checkpoint := previousCheckpoint.Advance(maxLastModified, startTime, ds.settings.SafetyWindow)
+	if err := ds.settings.CheckpointStore.SaveCheckpoint(ctx, checkpoint); err != nil {
+		return result, fmt.Errorf("failed to save the checkpoint of %s: %w", entity.Name, err)
+	}
+	result.Checkpoint = checkpoint
+
+	return result, nil
+}
+
+//sync lists the changed records and gives them to the sink in batches, it returns the highest lastModified
+func (ds *DeltaSync) sync(ctx context.Context, entity SyncEntity, checkpoint Checkpoint) (int64, int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	filters := make(map[string]interface{}, len(entity.Filters)+1)
+	for k, v := range entity.Filters {
+		filters[k] = v
+	}
+	if checkpoint.ChangedSince > 0 {
+		filters[ChangedSinceFilter] = checkpoint.ChangedSince
+	}
+
+	getLastModified := func(item interface{}) (int64, error) {
+		if entity.LastModified != nil {
+			return entity.LastModified(item), nil
+		}
+		//without lastModified the checkpoint would never advance, so the run fails instead
+		lastModified, ok := findLastModified(item)
+		if !ok {
+			return 0, fmt.Errorf("%T has no usable LastModified field, set SyncEntity.LastModified to read it", item)
+		}
+		return lastModified, nil
+	}
+
+	lister := NewLister(ds.settings.ListingSettings, entity.DataProvider, nil)
+	itemsStream := lister.Get(ctx, filters)
+	defer func() {
+		//the stream is drained, so the fetchers can stop
+		cancel()
+		for range itemsStream {
+		}
+	}()
+
+	var maxLastModified int64
+	upsertedCount := 0
+	batch := make([]SyncEvent, 0, ds.settings.BatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := ds.sink.Upsert(ctx, batch); err != nil {
+			return err
+		}
+		upsertedCount += len(batch)
+		batch = make([]SyncEvent, 0, ds.settings.BatchSize)
+		return nil
+	}
+
+	for item := range itemsStream {
+		if item.Err != nil {
+			return maxLastModified, upsertedCount, item.Err
+		}
+		if item.Payload == nil {
+			continue
+		}
+
+		lastModified, err := getLastModified(item.Payload)
+		if err != nil {
+			return maxLastModified, upsertedCount, err
+		}
+		if lastModified > maxLastModified {
+			maxLastModified = lastModified
+		}
+		batch = append(batch, SyncEvent{Entity: entity.Name, Payload: item.Payload, LastModified: lastModified})
+		if len(batch) >= ds.settings.BatchSize {
+			if err := flush(); err != nil {
+				return maxLastModified, upsertedCount, err
+			}
+		}
+	}
+
+	if ctx.Err() != nil {
+		return maxLastModified, upsertedCount, ctx.Err()
+	}
+
+	return maxLastModified, upsertedCount, flush()
+}
+
+func (ds *DeltaSync) getEntityLock(entity string) *sync.Mutex {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+
+	entityLock, ok := ds.entityLocks[entity]
+	if !ok {
+		entityLock = &sync.Mutex{}
+		ds.entityLocks[entity] = entityLock
+	}
+
+	return entityLock
+}
+
+//GetLastModified finds the LastModified field of a record including the embedded LastModified struct,
+//the field can be a number, a numeric string or a date time string, 0 is given if there is no such field
+func GetLastModified(item interface{}) int64 {
+	lastModified, _ := findLastModified(item)
+	return lastModified
+}
+
+//findLastModified is GetLastModified which tells if a usable LastModified field was found
+func findLastModified(item interface{}) (int64, bool) {
+	val := reflect.ValueOf(item)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return 0, false
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return 0, false
+	}
+
+	field := val.FieldByName("LastModified")
+	if !field.IsValid() {
+		return 0, false
+	}
+	if field.Kind() == reflect.Struct {
+		field = field.FieldByName("LastModified")
+		if !field.IsValid() {
+			return 0, false
+		}
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(field.Uint()), true
+	case reflect.String:
+		if timestamp, err := strconv.ParseInt(field.String(), 10, 64); err == nil {
+			return timestamp, true
+		}
+		if t, err := time.Parse(lastModifiedLayout, field.String()); err == nil {
+			return t.Unix(), true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,321 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+type syncRecordMock struct {
+	ID           int
+	LastModified uint64
+}
+
+type embeddedLastModifiedMock struct {
+	ID int
+	LastModified
+}
+
+//changedRecordsProviderMock pages the records which are changed since the changedSince filter
+type changedRecordsProviderMock struct {
+	lock           sync.Mutex
+	records        []syncRecordMock
+	readErr        error
+	changedSinceIn []interface{}
+}
+
+func (crpm *changedRecordsProviderMock) getChanged(filters map[string]interface{}) []syncRecordMock {
+	changedSince, _ := filters[ChangedSinceFilter].(int64)
+	res := make([]syncRecordMock, 0, len(crpm.records))
+	for _, rec := range crpm.records {
+		if int64(rec.LastModified) >= changedSince {
+			res = append(res, rec)
+		}
+	}
+
+	return res
+}
+
+func (crpm *changedRecordsProviderMock) Count(ctx context.Context, filters map[string]interface{}) (int, error) {
+	crpm.lock.Lock()
+	defer crpm.lock.Unlock()
+
+	crpm.changedSinceIn = append(crpm.changedSinceIn, filters[ChangedSinceFilter])
+
+	return len(crpm.getChanged(filters)), nil
+}
+
+func (crpm *changedRecordsProviderMock) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	crpm.lock.Lock()
+	defer crpm.lock.Unlock()
+
+	if crpm.readErr != nil {
+		return crpm.readErr
+	}
+
+	for _, filters := range bulkFilters {
+		changed := crpm.getChanged(filters)
+		pageNo := filters["pageNo"].(int)
+		recordsOnPage := filters["recordsOnPage"].(int)
+		for i := (pageNo - 1) * recordsOnPage; i < pageNo*recordsOnPage && i < len(changed); i++ {
+			callback(changed[i])
+		}
+	}
+
+	return nil
+}
+
+type syncSinkMock struct {
+	lock    sync.Mutex
+	events  []SyncEvent
+	batches int
+	err     error
+}
+
+func (ssm *syncSinkMock) Upsert(ctx context.Context, events []SyncEvent) error {
+	ssm.lock.Lock()
+	defer ssm.lock.Unlock()
+
+	if ssm.err != nil {
+		return ssm.err
+	}
+	ssm.batches++
+	ssm.events = append(ssm.events, events...)
+
+	return nil
+}
+
+func (ssm *syncSinkMock) ids() []int {
+	ssm.lock.Lock()
+	defer ssm.lock.Unlock()
+
+	ids := make([]int, 0, len(ssm.events))
+	for _, event := range ssm.events {
+		ids = append(ids, event.Payload.(syncRecordMock).ID)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+func newTestDeltaSync(store CheckpointStore, sink SyncSink, now time.Time) *DeltaSync {
+	ds := NewDeltaSync(
+		DeltaSyncSettings{
+			ListingSettings: ListingSettings{
+				MaxItemsPerRequest: 2,
+				MaxFetchersCount:   2,
+				RetryPolicy:        &RetryPolicy{MaxAttempts: 1},
+			},
+			CheckpointStore: store,
+			BatchSize:       2,
+			SafetyWindow:    time.Minute,
+		},
+		sink,
+	)
+	ds.timeNow = func() time.Time {
+		return now
+	}
+
+	return ds
+}
+
+func TestDeltaSyncRun(t *testing.T) {
+	now := time.Unix(10000, 0)
+	dp := &changedRecordsProviderMock{
+		records: []syncRecordMock{
+			{ID: 1, LastModified: 100},
+			{ID: 2, LastModified: 300},
+			{ID: 3, LastModified: 200},
+		},
+	}
+	store := NewInMemoryCheckpointStore()
+	sink := &syncSinkMock{}
+	ds := newTestDeltaSync(store, sink, now)
+	entity := SyncEntity{Name: "products", DataProvider: dp, Filters: map[string]interface{}{"active": 1}}
+
+	res, err := ds.Run(context.Background(), entity)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, res.UpsertedCount)
+	assert.Equal(t, Checkpoint{Entity: "products"}, res.PreviousCheckpoint)
+	assert.Equal(t, Checkpoint{Entity: "products", ChangedSince: 300, SyncedAt: 10000}, res.Checkpoint)
+	assert.Equal(t, []int{1, 2, 3}, sink.ids())
+	assert.Equal(t, 2, sink.batches)
+	assert.Equal(t, map[string]interface{}{"active": 1}, entity.Filters)
+
+	checkpoint, found, err := store.LoadCheckpoint(context.Background(), "products")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(300), checkpoint.ChangedSince)
+
+	//the second run gets only the records changed since the checkpoint
+	dp.records = append(dp.records, syncRecordMock{ID: 4, LastModified: 500})
+	sink.events = nil
+
+	res, err = ds.Run(context.Background(), entity)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.UpsertedCount)
+	assert.Equal(t, int64(500), res.Checkpoint.ChangedSince)
+	assert.Equal(t, []int{2, 4}, sink.ids())
+	assert.Equal(t, []interface{}{nil, int64(300)}, dp.changedSinceIn)
+}
+
+func TestDeltaSyncRunLimitsCheckpointBySafetyWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	dp := &changedRecordsProviderMock{
+		records: []syncRecordMock{
+			{ID: 1, LastModified: 990},
+		},
+	}
+	ds := newTestDeltaSync(nil, &syncSinkMock{}, now)
+
+	res, err := ds.Run(context.Background(), SyncEntity{Name: "customers", DataProvider: dp})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(940), res.Checkpoint.ChangedSince)
+}
+
+func TestDeltaSyncRunWithoutChangesKeepsCheckpoint(t *testing.T) {
+	store := NewInMemoryCheckpointStore()
+	err := store.SaveCheckpoint(context.Background(), Checkpoint{Entity: "suppliers", ChangedSince: 500})
+	assert.NoError(t, err)
+
+	sink := &syncSinkMock{}
+	ds := newTestDeltaSync(store, sink, time.Unix(1000, 0))
+
+	res, err := ds.Run(context.Background(), SyncEntity{Name: "suppliers", DataProvider: &changedRecordsProviderMock{}})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, res.UpsertedCount)
+	assert.Equal(t, int64(500), res.Checkpoint.ChangedSince)
+	assert.Equal(t, 0, sink.batches)
+}
+
+func TestDeltaSyncRunErrors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		dp          *changedRecordsProviderMock
+		sink        *syncSinkMock
+		expectedErr string
+	}{
+		{
+			name: "read error",
+			dp: &changedRecordsProviderMock{
+				records: []syncRecordMock{{ID: 1, LastModified: 100}},
+				readErr: errors.New("some read error"),
+			},
+			sink:        &syncSinkMock{},
+			expectedErr: "failed to sync addresses: failed to read listing pages 1 (recordsOnPage 2): some read error",
+		},
+		{
+			name: "sink error",
+			dp: &changedRecordsProviderMock{
+				records: []syncRecordMock{{ID: 1, LastModified: 100}},
+			},
+			sink:        &syncSinkMock{err: errors.New("some sink error")},
+			expectedErr: "failed to sync addresses: some sink error",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := NewInMemoryCheckpointStore()
+			err := store.SaveCheckpoint(context.Background(), Checkpoint{Entity: "addresses", ChangedSince: 50})
+			assert.NoError(t, err)
+
+			ds := newTestDeltaSync(store, testCase.sink, time.Unix(1000, 0))
+			res, err := ds.Run(context.Background(), SyncEntity{Name: "addresses", DataProvider: testCase.dp})
+			assert.EqualError(t, err, testCase.expectedErr)
+			assert.Equal(t, int64(50), res.Checkpoint.ChangedSince)
+
+			checkpoint, _, err := store.LoadCheckpoint(context.Background(), "addresses")
+			assert.NoError(t, err)
+			assert.Equal(t, int64(50), checkpoint.ChangedSince)
+		})
+	}
+}
+
+func TestDeltaSyncRunFailsWithoutLastModified(t *testing.T) {
+	store := NewInMemoryCheckpointStore()
+	err := store.SaveCheckpoint(context.Background(), Checkpoint{Entity: "warehouses", ChangedSince: 50})
+	assert.NoError(t, err)
+
+	sink := &syncSinkMock{}
+	ds := newTestDeltaSync(store, sink, time.Unix(1000, 0))
+
+	res, err := ds.Run(context.Background(), SyncEntity{Name: "warehouses", DataProvider: &pagedProviderMock{total: 3}})
+	assert.EqualError(t, err, "failed to sync warehouses: common.payloadMock has no usable LastModified field, set SyncEntity.LastModified to read it")
+	assert.Equal(t, int64(50), res.Checkpoint.ChangedSince)
+	assert.Equal(t, 0, sink.batches)
+
+	res, err = ds.Run(context.Background(), SyncEntity{
+		Name:         "warehouses",
+		DataProvider: &pagedProviderMock{total: 3},
+		LastModified: func(item interface{}) int64 {
+			return int64(item.(payloadMock).ID) * 100
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, res.UpsertedCount)
+	assert.Equal(t, int64(300), res.Checkpoint.ChangedSince)
+}
+
+func TestDeltaSyncRunAll(t *testing.T) {
+	sink := &syncSinkMock{}
+	ds := newTestDeltaSync(nil, sink, time.Unix(1000, 0))
+
+	results, err := ds.RunAll(
+		context.Background(),
+		SyncEntity{Name: "products", DataProvider: &changedRecordsProviderMock{records: []syncRecordMock{{ID: 1, LastModified: 100}}}},
+		SyncEntity{Name: "customers", DataProvider: &changedRecordsProviderMock{records: []syncRecordMock{{ID: 3}}, readErr: errors.New("some read error")}},
+		SyncEntity{Name: "suppliers", DataProvider: &changedRecordsProviderMock{records: []syncRecordMock{{ID: 2, LastModified: 200}}}},
+	)
+	assert.EqualError(t, err, "failed to sync customers: failed to read listing pages 1 (recordsOnPage 2): some read error")
+	assert.Len(t, results, 3)
+	assert.Equal(t, []int{1, 2}, sink.ids())
+	assert.Equal(t, int64(100), results[0].Checkpoint.ChangedSince)
+	assert.Equal(t, int64(200), results[2].Checkpoint.ChangedSince)
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync", "checkpoints.json")
+	store := NewFileCheckpointStore(path)
+
+	_, found, err := store.LoadCheckpoint(context.Background(), "products")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, store.SaveCheckpoint(context.Background(), Checkpoint{Entity: "products", ChangedSince: 100, SyncedAt: 200}))
+	assert.NoError(t, store.SaveCheckpoint(context.Background(), Checkpoint{Entity: "customers", ChangedSince: 300}))
+
+	checkpoint, found, err := NewFileCheckpointStore(path).LoadCheckpoint(context.Background(), "products")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Checkpoint{Entity: "products", ChangedSince: 100, SyncedAt: 200}, checkpoint)
+}
+
+func TestGetLastModified(t *testing.T) {
+	testCases := []struct {
+		name     string
+		item     interface{}
+		expected int64
+	}{
+		{name: "uint", item: syncRecordMock{LastModified: 100}, expected: 100},
+		{name: "pointer", item: &syncRecordMock{LastModified: 100}, expected: 100},
+		{name: "int", item: struct{ LastModified int }{LastModified: 200}, expected: 200},
+		{name: "numeric string", item: struct{ LastModified string }{LastModified: "300"}, expected: 300},
+		{name: "date string", item: struct{ LastModified string }{LastModified: "1970-01-01 00:06:40"}, expected: 400},
+		{name: "embedded struct", item: embeddedLastModifiedMock{LastModified: LastModified{LastModified: 500}}, expected: 500},
+		{name: "no field", item: payloadMock{ID: 1}, expected: 0},
+		{name: "not a struct", item: 1, expected: 0},
+		{name: "nil pointer", item: (*syncRecordMock)(nil), expected: 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, GetLastModified(testCase.item))
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	filterTag = "filter"
+	//DateFilterLayout is the format of the date filters like dateFrom, use the date option of the filter tag for them
+	DateFilterLayout = "2006-01-02"
+)
+
+//Filter is a typed set of parameters of an API request
+type Filter interface {
+	ToFilters() (map[string]string, error)
+}
+
+//Paging is the common part of the filters of get requests
+type Paging struct {
+	RecordsOnPage int    `filter:"recordsOnPage"`
+	PageNo        int    `filter:"pageNo"`
+	OrderBy       string `filter:"orderBy"`
+	OrderByDir    string `filter:"orderByDir"`
+}
+
+//EncodeFilters converts a struct to the API request parameters, the parameter names are given by the filter tag.
+//Zero values are skipped, use pointers to send them, e.g. *bool to send active=0.
+//Booleans are sent as 1 and 0, slices as comma separated lists and time.Time as a unix timestamp or as
+//a date with the date tag option, e.g. `filter:"dateFrom,date"`.
+//Embedded structs are flattened and a map[string]string field with the extra tag option is merged to the result,
+//so the parameters which have no field can be sent as well.
+//A slice of structs with the indexed tag option is encoded by Erply conventions for rows and attributes:
+//the parameter names of the n-th item get the n suffix starting from 1, e.g. productID1, amount1, productID2.
+func EncodeFilters(input interface{}) (map[string]string, error) {
+	filters := map[string]string{}
+
+	val := reflect.ValueOf(input)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return filters, nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot encode filters from %T, a struct is expected", input)
+	}
+
+	if err := encodeFilterStruct(val, filters); err != nil {
+		return nil, err
+	}
+
+	return filters, nil
+}
+
+//ToBulkFilters converts typed filters to the filters of bulk sub-requests
+func ToBulkFilters[F Filter](filters []F) ([]map[string]interface{}, error) {
+	bulkFilters := make([]map[string]interface{}, 0, len(filters))
+	for _, filter := range filters {
+		encodedFilter, err := filter.ToFilters()
+		if err != nil {
+			return nil, err
+		}
+
+		bulkFilter := make(map[string]interface{}, len(encodedFilter))
+		for k, v := range encodedFilter {
+			bulkFilter[k] = v
+		}
+		bulkFilters = append(bulkFilters, bulkFilter)
+	}
+
+	return bulkFilters, nil
+}
+
+func encodeFilterStruct(val reflect.Value, filters map[string]string) error {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldVal := val.Field(i)
+
+		tag, hasTag := field.Tag.Lookup(filterTag)
+		if tag == "-" {
+			continue
+		}
+		name, options := parseFilterTag(tag)
+
+		if field.Anonymous && !hasTag {
+			embedded := fieldVal
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := encodeFilterStruct(embedded, filters); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if !field.IsExported() || !hasTag {
+			continue
+		}
+
+		if options["extra"] {
+			extra, ok := fieldVal.Interface().(map[string]string)
+			if !ok {
+				return fmt.Errorf("filter field %s with the extra option should be map[string]string", field.Name)
+			}
+			for k, v := range extra {
+				filters[k] = v
+			}
+			continue
+		}
+
+		if options["indexed"] {
+			if err := encodeIndexedFilters(fieldVal, filters); err != nil {
+				return fmt.Errorf("failed to encode filter field %s: %v", field.Name, err)
+			}
+			continue
+		}
+
+		if name == "" {
+			return fmt.Errorf("filter field %s has no parameter name", field.Name)
+		}
+
+		value, isSet, err := encodeFilterValue(fieldVal, options)
+		if err != nil {
+			return fmt.Errorf("failed to encode filter %s: %v", name, err)
+		}
+		if isSet {
+			filters[name] = value
+		}
+	}
+
+	return nil
+}
+
+//encodeIndexedFilters adds the fields of each item of the slice with the item number as suffix
+func encodeIndexedFilters(val reflect.Value, filters map[string]string) error {
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return fmt.Errorf("indexed option requires a slice, got %s", val.Type())
+	}
+
+	for i := 0; i < val.Len(); i++ {
+		item := val.Index(i)
+		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				continue
+			}
+			item = item.Elem()
+		}
+		if item.Kind() != reflect.Struct {
+			return fmt.Errorf("indexed option requires a slice of structs, got %s", val.Type())
+		}
+
+		itemFilters := map[string]string{}
+		if err := encodeFilterStruct(item, itemFilters); err != nil {
+			return err
+		}
+		for k, v := range itemFilters {
+			filters[k+strconv.Itoa(i+1)] = v
+		}
+	}
+
+	return nil
+}
+
+//encodeFilterValue gives the parameter value and false if the value is zero and should be skipped
+func encodeFilterValue(val reflect.Value, options map[string]bool) (string, bool, error) {
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return "", false, nil
+		}
+		value, _, err := encodeFilterValue(val.Elem(), options)
+		return value, err == nil, err
+	}
+
+	if t, ok := val.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return "", false, nil
+		}
+		if options["date"] {
+			return t.Format(DateFilterLayout), true, nil
+		}
+		return strconv.FormatInt(t.Unix(), 10), true, nil
+	}
+
+	switch val.Kind() {
+	case reflect.String:
+		return val.String(), val.Len() > 0, nil
+	case reflect.Bool:
+		if val.Bool() {
+			return "1", true, nil
+		}
+		return "0", false, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10), val.Int() != 0, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10), val.Uint() != 0, nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(val.Float(), 'f', -1, 64), val.Float() != 0, nil
+	case reflect.Slice, reflect.Array:
+		if val.Len() == 0 {
+			return "", false, nil
+		}
+		items := make([]string, 0, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			item, _, err := encodeFilterValue(val.Index(i), options)
+			if err != nil {
+				return "", false, err
+			}
+			items = append(items, item)
+		}
+		return strings.Join(items, ","), true, nil
+	}
+
+	return "", false, fmt.Errorf("unsupported type %s", val.Type())
+}
+
+func parseFilterTag(tag string) (string, map[string]bool) {
+	parts := strings.Split(tag, ",")
+	options := make(map[string]bool, len(parts)-1)
+	for _, option := range parts[1:] {
+		options[strings.TrimSpace(option)] = true
+	}
+
+	return strings.TrimSpace(parts[0]), options
+}
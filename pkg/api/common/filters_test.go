@@ -0,0 +1,139 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type testEmbeddedFilter struct {
+	WarehouseID int `filter:"warehouseID"`
+}
+
+type testFilter struct {
+	ID           int       `filter:"id"`
+	IDs          []int     `filter:"ids"`
+	Codes        []string  `filter:"codes"`
+	Name         string    `filter:"name"`
+	Price        float64   `filter:"price"`
+	Active       *bool     `filter:"active"`
+	Deleted      *bool     `filter:"deleted"`
+	GetStockInfo bool      `filter:"getStockInfo"`
+	GetRecipes   bool      `filter:"getRecipes"`
+	ChangedSince time.Time `filter:"changedSince"`
+	DateFrom     time.Time `filter:"dateFrom,date"`
+	DateTo       time.Time `filter:"dateTo,date"`
+	Ignored      string    `filter:"-"`
+	NoTag        string
+	testEmbeddedFilter
+	Paging
+	Extra map[string]string `filter:",extra"`
+}
+
+func TestEncodeFilters(t *testing.T) {
+	active := false
+	filter := testFilter{
+		IDs:          []int{1, 2, 3},
+		Codes:        []string{"a", "b"},
+		Name:         "Coffee",
+		Price:        2.5,
+		Active:       &active,
+		GetStockInfo: true,
+		ChangedSince: time.Unix(1600000000, 0),
+		DateFrom:     time.Date(2020, 1, 2, 10, 0, 0, 0, time.UTC),
+		Ignored:      "ignored",
+		NoTag:        "ignored",
+		Paging:       Paging{RecordsOnPage: 100, PageNo: 2},
+		Extra:        map[string]string{"lang": "eng"},
+	}
+	filter.WarehouseID = 3
+
+	filters, err := EncodeFilters(filter)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"ids":           "1,2,3",
+		"codes":         "a,b",
+		"name":          "Coffee",
+		"price":         "2.5",
+		"active":        "0",
+		"getStockInfo":  "1",
+		"changedSince":  "1600000000",
+		"dateFrom":      "2020-01-02",
+		"warehouseID":   "3",
+		"recordsOnPage": "100",
+		"pageNo":        "2",
+		"lang":          "eng",
+	}, filters)
+
+	filters, err = EncodeFilters(&testFilter{ID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "1"}, filters)
+}
+
+func TestEncodeFiltersErrors(t *testing.T) {
+	_, err := EncodeFilters("some")
+	assert.EqualError(t, err, "cannot encode filters from string, a struct is expected")
+
+	_, err = EncodeFilters(struct {
+		Value map[string]int `filter:"value"`
+	}{Value: map[string]int{}})
+	assert.EqualError(t, err, "failed to encode filter value: unsupported type map[string]int")
+}
+
+type testTypedFilter struct {
+	ID int `filter:"id"`
+}
+
+func (tf testTypedFilter) ToFilters() (map[string]string, error) {
+	return EncodeFilters(tf)
+}
+
+func TestToBulkFilters(t *testing.T) {
+	bulkFilters, err := ToBulkFilters([]testTypedFilter{{ID: 1}, {ID: 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"id": "1"}, {"id": "2"}}, bulkFilters)
+}
+
+type testRow struct {
+	ProductID int               `filter:"productID"`
+	Amount    float64           `filter:"amount"`
+	Extra     map[string]string `filter:",extra"`
+}
+
+type testInput struct {
+	ID         int             `filter:"id"`
+	Rows       []testRow       `filter:",indexed"`
+	Attributes []ObjAttribute  `filter:",indexed"`
+	LongAttrs  []LongAttribute `filter:",indexed"`
+}
+
+func TestEncodeIndexedFilters(t *testing.T) {
+	filters, err := EncodeFilters(testInput{
+		ID: 3,
+		Rows: []testRow{
+			{ProductID: 10, Amount: 2},
+			{ProductID: 11, Amount: 0.5, Extra: map[string]string{"rowNotes": "some"}},
+		},
+		Attributes: []ObjAttribute{{AttributeName: "color", AttributeType: "text", AttributeValue: "red"}},
+		LongAttrs:  []LongAttribute{{AttributeName: "info", AttributeValue: "long text"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"id":                  "3",
+		"productID1":          "10",
+		"amount1":             "2",
+		"productID2":          "11",
+		"amount2":             "0.5",
+		"rowNotes2":           "some",
+		"attributeName1":      "color",
+		"attributeType1":      "text",
+		"attributeValue1":     "red",
+		"longAttributeName1":  "info",
+		"longAttributeValue1": "long text",
+	}, filters)
+
+	_, err = EncodeFilters(struct {
+		Rows []int `filter:",indexed"`
+	}{Rows: []int{1}})
+	assert.EqualError(t, err, "failed to encode filter field Rows: indexed option requires a slice of structs, got []int")
+}
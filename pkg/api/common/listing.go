@@ -11,9 +11,18 @@ const DefaultMaxRequestsCountPerSecond = 0
 
 type ListingSettings struct {
 	MaxRequestsCountPerSecond int
+	MaxRequestsBurst          int //amount of requests which can be sent without waiting, by default equals to MaxRequestsCountPerSecond
 	StreamBufferLength        int
 	MaxFetchersCount          int
 	MaxItemsPerRequest        int
+	//OrderedDelivery makes Lister emit the items in the order of pages, the items of a bulk request are kept
+	//in memory until the items of the preceding requests are emitted
+	OrderedDelivery bool
+	//RetryPolicy of the failed bulk requests, a chunk which keeps failing is split to isolate the failing pages,
+	//NewDefaultRetryPolicy is used if it's nil, set MaxAttempts to 1 to disable the retries
+	RetryPolicy *RetryPolicy
+	//Adaptive enables the adjusting of the fetchers count and the bulk size at runtime, see AdaptiveListingSettings
+	Adaptive *AdaptiveListingSettings
 }
 
 type Cursor struct {
@@ -29,6 +38,8 @@ type Item struct {
 	Err        error
 	TotalCount int
 	Payload    interface{}
+	//completedPages is set instead of Payload in resumable listings after all items of the pages were read
+	completedPages []int
 }
 
 func setListingSettingsDefaults(settingsFromInput ListingSettings) ListingSettings {
@@ -40,6 +51,10 @@ func setListingSettingsDefaults(settingsFromInput ListingSettings) ListingSettin
 		settingsFromInput.MaxItemsPerRequest = MaxCountPerBulkRequestItem * MaxCountPerBulkRequestItem
 	}
 
+	if settingsFromInput.MaxRequestsBurst == 0 {
+		settingsFromInput.MaxRequestsBurst = settingsFromInput.MaxRequestsCountPerSecond
+	}
+
 	if settingsFromInput.MaxFetchersCount == 0 {
 		settingsFromInput.MaxFetchersCount = DefaultMaxFetchersCount
 	}
@@ -56,17 +71,42 @@ type Lister struct {
 	listingSettings     ListingSettings
 	reqThrottler        Throttler
 	listingDataProvider DataProvider
+	sleeper             Sleeper             //waiting between the retries, nil means waiting respecting the context
+	adaptive            *adaptiveController //nil if the adaptive mode is disabled
 }
 
+//NewLister creates Lister with its own requests throttler, if sl is nil the throttler and the retries wait respecting the context,
+//set MaxRequestsCountPerSecond to 0 to rely only on the throttler of the API client
 func NewLister(settings ListingSettings, dataProvider DataProvider, sl Sleeper) *Lister {
 	settings = setListingSettingsDefaults(settings)
 
-	thrl := NewSleepThrottler(settings.MaxRequestsCountPerSecond, sl)
+	thrl := NewTokenBucketThrottler(float64(settings.MaxRequestsCountPerSecond), settings.MaxRequestsBurst)
+	if sl != nil {
+		thrl.WithSleeper(sl)
+	}
 
-	return &Lister{
+	lister := &Lister{
 		listingSettings:     settings,
 		reqThrottler:        thrl,
 		listingDataProvider: dataProvider,
+		sleeper:             sl,
+	}
+	if settings.Adaptive != nil {
+		lister.adaptive = newAdaptiveController(*settings.Adaptive, settings)
+	}
+
+	return lister
+}
+
+//AdaptiveState gives the current fetchers count and bulk size, they are taken from ListingSettings if the adaptive mode is disabled
+func (p *Lister) AdaptiveState() AdaptiveState {
+	if p.adaptive != nil {
+		return p.adaptive.getState()
+	}
+
+	return AdaptiveState{
+		FetchersCount:   p.listingSettings.MaxFetchersCount,
+		ItemsPerRequest: p.getMaxItemsPerRequest(),
 	}
 }
 
@@ -111,41 +151,160 @@ func (p *Lister) GetGrouped(ctx context.Context, filters map[string]interface{},
 }
 
 func (p *Lister) Get(ctx context.Context, filters map[string]interface{}) ItemsStream {
-	p.reqThrottler.Throttle()
-
 	filters["recordsOnPage"] = 1
 	filters["pageNo"] = 1
 
-	totalCount, err := p.listingDataProvider.Count(ctx, filters)
+	totalCount, err := p.count(ctx, filters)
 	if err != nil {
-		outputChan := make(ItemsStream, 1)
-		defer close(outputChan)
+		return newErrorStream(err, totalCount)
+	}
 
-		outputChan <- Item{
-			Err:        err,
-			TotalCount: totalCount,
-			Payload:    nil,
-		}
-		return outputChan
+	return p.fetch(ctx, filters, totalCount, p.getPageSize(totalCount), nil)
+}
+
+func (p *Lister) count(ctx context.Context, filters map[string]interface{}) (int, error) {
+	if err := p.reqThrottler.Throttle(ctx); err != nil {
+		return 0, err
 	}
 
-	cursorsChan := p.getCursors(ctx, totalCount)
+	return p.listingDataProvider.Count(ctx, filters)
+}
+
+func newErrorStream(err error, totalCount int) ItemsStream {
+	outputChan := make(ItemsStream, 1)
+	defer close(outputChan)
+
+	outputChan <- Item{
+		Err:        err,
+		TotalCount: totalCount,
+		Payload:    nil,
+	}
+	return outputChan
+}
+
+//fetch reads the pages of pageSize items in parallel, the completed pages are skipped,
+//the fetchers give the completedPages items only if completedPages is not nil
+func (p *Lister) fetch(ctx context.Context, filters map[string]interface{}, totalCount, pageSize int, completedPages map[int]bool) ItemsStream {
+	if p.adaptive != nil {
+		p.adaptive.setPageSize(pageSize)
+	}
+
+	cursorsChan := p.getCursors(ctx, totalCount, pageSize, completedPages)
+	if p.listingSettings.OrderedDelivery {
+		return p.fetchOrdered(ctx, cursorsChan, totalCount, filters, completedPages != nil)
+	}
 
-	childChans := make([]ItemsStream, 0, p.listingSettings.MaxFetchersCount)
-	for i := 0; i < p.listingSettings.MaxFetchersCount; i++ {
-		childChan := p.fetchItemsChunk(ctx, cursorsChan, totalCount, filters)
+	fetchersCount := p.getFetchersCount()
+	childChans := make([]ItemsStream, 0, fetchersCount)
+	for i := 0; i < fetchersCount; i++ {
+		childChan := p.fetchItemsChunk(ctx, cursorsChan, totalCount, filters, completedPages != nil)
 		childChans = append(childChans, childChan)
 	}
 
 	return p.mergeChannels(ctx, childChans...)
 }
 
-func (p *Lister) fetchItemsChunk(ctx context.Context, cursorChan chan []Cursor, totalCount int, filters map[string]interface{}) ItemsStream {
+type indexedCursors struct {
+	index   int
+	cursors []Cursor
+}
+
+type fetchedChunk struct {
+	index int
+	items []Item
+}
+
+//fetchOrdered reads the bulk requests in parallel and emits their items in the order of the requests,
+//the count of requests which are read or wait for the preceding ones is limited to 2 * MaxFetchersCount
+func (p *Lister) fetchOrdered(ctx context.Context, cursorsChan chan []Cursor, totalCount int, filters map[string]interface{}, trackPages bool) ItemsStream {
+	fetchersCount := p.getFetchersCount()
+	window := make(chan struct{}, 2*fetchersCount)
+	indexedChan := make(chan indexedCursors)
+	go func() {
+		defer close(indexedChan)
+		index := 0
+		for cursors := range cursorsChan {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case indexedChan <- indexedCursors{index: index, cursors: cursors}:
+				index++
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	chunksChan := make(chan fetchedChunk, fetchersCount)
+	var wg sync.WaitGroup
+	wg.Add(fetchersCount)
+	for i := 0; i < fetchersCount; i++ {
+		go func() {
+			defer wg.Done()
+			for indexed := range indexedChan {
+				items := make([]Item, 0)
+				p.fetchItemsFromAPI(ctx, indexed.cursors, totalCount, filters, trackPages, func(item Item) {
+					items = append(items, item)
+				})
+
+				select {
+				case chunksChan <- fetchedChunk{index: indexed.index, items: items}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(chunksChan)
+	}()
+
+	outputChan := make(ItemsStream, p.listingSettings.StreamBufferLength)
+	go func() {
+		defer close(outputChan)
+		pendingChunks := map[int][]Item{}
+		nextIndex := 0
+		for chunk := range chunksChan {
+			pendingChunks[chunk.index] = chunk.items
+			for {
+				items, ok := pendingChunks[nextIndex]
+				if !ok {
+					break
+				}
+				delete(pendingChunks, nextIndex)
+				nextIndex++
+				<-window
+
+				for _, item := range items {
+					select {
+					case outputChan <- item:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return outputChan
+}
+
+func (p *Lister) fetchItemsChunk(ctx context.Context, cursorChan chan []Cursor, totalCount int, filters map[string]interface{}, trackPages bool) ItemsStream {
 	prodStream := make(chan Item, p.listingSettings.StreamBufferLength)
 	go func() {
 		defer close(prodStream)
 		for cursors := range cursorChan {
-			p.fetchItemsFromAPI(ctx, cursors, totalCount, prodStream, filters)
+			//the items are dropped after cancelling, so the fetcher doesn't wait for the consumer which is gone
+			p.fetchItemsFromAPI(ctx, cursors, totalCount, filters, trackPages, func(item Item) {
+				select {
+				case prodStream <- item:
+				case <-ctx.Done():
+				}
+			})
 
 			select {
 			case <-ctx.Done():
@@ -159,8 +318,55 @@ func (p *Lister) fetchItemsChunk(ctx context.Context, cursorChan chan []Cursor,
 	return prodStream
 }
 
-func (p *Lister) getCursors(ctx context.Context, totalCount int) chan []Cursor {
+//getFetchersCount gives the count of the fetcher goroutines, in the adaptive mode only AdaptiveState.FetchersCount of them read in parallel
+func (p *Lister) getFetchersCount() int {
+	if p.adaptive != nil {
+		return p.adaptive.settings.MaxFetchersCount
+	}
+
+	return p.listingSettings.MaxFetchersCount
+}
+
+func (p *Lister) getMaxItemsPerRequest() int {
+	if p.listingSettings.MaxItemsPerRequest > MaxCountPerBulkRequestItem*MaxBulkRequestsCount {
+		return MaxCountPerBulkRequestItem * MaxBulkRequestsCount
+	}
+
+	return p.listingSettings.MaxItemsPerRequest
+}
+
+//getPageSize gives recordsOnPage of the listing, the items of one bulk request are spread evenly over its pages
+func (p *Lister) getPageSize(totalCount int) int {
+	maxItemsPerRequest := p.getMaxItemsPerRequest()
+
+	countToFetchForBulkRequest := totalCount
+	if totalCount > maxItemsPerRequest {
+		countToFetchForBulkRequest = maxItemsPerRequest
+	}
+
+	bulkItemsCount := CeilDivisionInt(countToFetchForBulkRequest, MaxCountPerBulkRequestItem)
+	if bulkItemsCount > MaxBulkRequestsCount {
+		bulkItemsCount = MaxBulkRequestsCount
+	}
+	if bulkItemsCount < 1 {
+		bulkItemsCount = 1
+	}
+
+	pageSize := CeilDivisionInt(maxItemsPerRequest, bulkItemsCount)
+	if pageSize > MaxCountPerBulkRequestItem {
+		pageSize = MaxCountPerBulkRequestItem
+	}
+
+	return pageSize
+}
+
+//getCursors plans the pages of pageSize items which are read in one bulk request, the completed pages are skipped
+func (p *Lister) getCursors(ctx context.Context, totalCount, pageSize int, completedPages map[int]bool) chan []Cursor {
 	out := make(chan []Cursor, p.listingSettings.MaxFetchersCount)
+	if p.adaptive != nil {
+		//the bulk size is taken when the request is planned, so the planned requests are not kept ahead
+		out = make(chan []Cursor)
+	}
 
 	leftCount := totalCount
 
@@ -168,38 +374,36 @@ func (p *Lister) getCursors(ctx context.Context, totalCount int) chan []Cursor {
 		defer close(out)
 
 		curPage := 1
-		if p.listingSettings.MaxItemsPerRequest > MaxCountPerBulkRequestItem*MaxBulkRequestsCount {
-			p.listingSettings.MaxItemsPerRequest = MaxCountPerBulkRequestItem * MaxBulkRequestsCount
-		}
-
 		for leftCount > 0 {
+			maxItemsPerRequest := p.AdaptiveState().ItemsPerRequest
 			countToFetchForBulkRequest := leftCount
-			if leftCount > p.listingSettings.MaxItemsPerRequest {
-				countToFetchForBulkRequest = p.listingSettings.MaxItemsPerRequest
+			if leftCount > maxItemsPerRequest {
+				countToFetchForBulkRequest = maxItemsPerRequest
 			}
 
-			bulkItemsCount := CeilDivisionInt(countToFetchForBulkRequest, MaxCountPerBulkRequestItem)
+			bulkItemsCount := CeilDivisionInt(countToFetchForBulkRequest, pageSize)
 			if bulkItemsCount > MaxBulkRequestsCount {
 				bulkItemsCount = MaxBulkRequestsCount
 			}
 
-			limit := CeilDivisionInt(p.listingSettings.MaxItemsPerRequest, bulkItemsCount)
-			if limit > MaxCountPerBulkRequestItem {
-				limit = MaxCountPerBulkRequestItem
-			}
-
 			cursorsForBulkRequest := make([]Cursor, 0, bulkItemsCount)
 			for i := 0; i < bulkItemsCount; i++ {
-				cursorsForBulkRequest = append(
-					cursorsForBulkRequest,
-					Cursor{
-						Limit:  limit,
-						Offset: curPage,
-					},
-				)
+				if !completedPages[curPage] {
+					cursorsForBulkRequest = append(
+						cursorsForBulkRequest,
+						Cursor{
+							Limit:  pageSize,
+							Offset: curPage,
+						},
+					)
+				}
 				curPage++
-				leftCount -= limit
+				leftCount -= pageSize
+			}
+			if len(cursorsForBulkRequest) == 0 {
+				continue
 			}
+
 			select {
 			case out <- cursorsForBulkRequest:
 				continue
@@ -216,37 +420,16 @@ func (p *Lister) fetchItemsFromAPI(
 	ctx context.Context,
 	cursors []Cursor,
 	totalCount int,
-	outputChan ItemsStream,
 	filters map[string]interface{},
+	trackPages bool,
+	emit func(item Item),
 ) {
-	bulkFilters := make([]map[string]interface{}, 0, len(cursors))
-	for _, cursor := range cursors {
-		bulkFilter := make(map[string]interface{})
-		for filterKey, filterValue := range filters {
-			bulkFilter[filterKey] = filterValue
-		}
-		bulkFilter["recordsOnPage"] = cursor.Limit
-		bulkFilter["pageNo"] = cursor.Offset
-		bulkFilters = append(bulkFilters, bulkFilter)
-	}
-
-	p.reqThrottler.Throttle()
-
-	err := p.listingDataProvider.Read(ctx, bulkFilters, func(item interface{}) {
-		outputChan <- Item{
-			Err:        nil,
-			TotalCount: totalCount,
-			Payload:    item,
-		}
-	})
-
-	if err != nil {
-		outputChan <- Item{
-			Err:        err,
+	for _, pagesErr := range p.fetchCursors(ctx, cursors, totalCount, filters, trackPages, emit) {
+		emit(Item{
+			Err:        pagesErr,
 			TotalCount: totalCount,
 			Payload:    nil,
-		}
-		return
+		})
 	}
 }
 
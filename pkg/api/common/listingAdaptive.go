@@ -0,0 +1,255 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"github.com/erply/api-go-wrapper/pkg/api/log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//DefaultAdaptiveTargetLatency is the response time of a bulk request up to which the adaptive Lister grows
+const DefaultAdaptiveTargetLatency = 5 * time.Second
+
+//AdaptiveListingSettings enables the adaptive mode of Lister, it starts with MaxFetchersCount and MaxItemsPerRequest
+//of ListingSettings and adjusts them at runtime within the bounds:
+//the fetchers count is halved on throttling and quota errors, the bulk size is halved on slow or failed responses,
+//both grow by one step after each fetcher got a fast response
+type AdaptiveListingSettings struct {
+	MinFetchersCount   int                       //1 by default
+	MaxFetchersCount   int                       //ListingSettings.MaxFetchersCount by default
+	MinItemsPerRequest int                       //the page size by default
+	MaxItemsPerRequest int                       //ListingSettings.MaxItemsPerRequest by default
+	TargetLatency      time.Duration             //slower responses shrink the bulk size, DefaultAdaptiveTargetLatency by default
+	OnAdjust           func(state AdaptiveState) //optional, called after each change, it should not block
+}
+
+//AdaptiveState is the current concurrency of the adaptive Lister
+type AdaptiveState struct {
+	FetchersCount   int //amount of bulk requests which are read in parallel
+	ItemsPerRequest int //amount of items of the next bulk requests
+}
+
+//adaptiveController keeps the AdaptiveState of Lister, it observes the metrics of the bulk reads
+//and limits the count of the parallel reads
+type adaptiveController struct {
+	settings  AdaptiveListingSettings
+	state     AdaptiveState
+	pageSize  int //step of the bulk size growth
+	active    int //amount of the reads in progress
+	successes int //amount of fast responses since the last change
+	changed   chan struct{}
+	lock      sync.Mutex
+}
+
+func newAdaptiveController(settings AdaptiveListingSettings, listingSettings ListingSettings) *adaptiveController {
+	if settings.MinFetchersCount <= 0 {
+		settings.MinFetchersCount = 1
+	}
+	if settings.MaxFetchersCount <= 0 {
+		settings.MaxFetchersCount = listingSettings.MaxFetchersCount
+	}
+	if settings.MaxFetchersCount < settings.MinFetchersCount {
+		settings.MaxFetchersCount = settings.MinFetchersCount
+	}
+	if settings.MaxItemsPerRequest <= 0 || settings.MaxItemsPerRequest > MaxCountPerBulkRequestItem*MaxBulkRequestsCount {
+		settings.MaxItemsPerRequest = listingSettings.MaxItemsPerRequest
+	}
+	if settings.MinItemsPerRequest > settings.MaxItemsPerRequest {
+		settings.MinItemsPerRequest = settings.MaxItemsPerRequest
+	}
+	if settings.TargetLatency <= 0 {
+		settings.TargetLatency = DefaultAdaptiveTargetLatency
+	}
+
+	ac := &adaptiveController{
+		settings: settings,
+		pageSize: MaxCountPerBulkRequestItem,
+		changed:  make(chan struct{}),
+	}
+	ac.state = ac.clamp(AdaptiveState{
+		FetchersCount:   listingSettings.MaxFetchersCount,
+		ItemsPerRequest: listingSettings.MaxItemsPerRequest,
+	})
+
+	return ac
+}
+
+//setPageSize sets the step of the bulk size growth to the page size of the listing
+func (ac *adaptiveController) setPageSize(pageSize int) {
+	ac.lock.Lock()
+	defer ac.lock.Unlock()
+
+	ac.pageSize = pageSize
+	ac.state = ac.clamp(ac.state)
+}
+
+func (ac *adaptiveController) getState() AdaptiveState {
+	ac.lock.Lock()
+	defer ac.lock.Unlock()
+
+	return ac.state
+}
+
+//acquire waits until the amount of reads in progress is below the fetchers count
+func (ac *adaptiveController) acquire(ctx context.Context) error {
+	for {
+		ac.lock.Lock()
+		if ac.active < ac.state.FetchersCount {
+			ac.active++
+			ac.lock.Unlock()
+			return nil
+		}
+		changed := ac.changed
+		ac.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (ac *adaptiveController) release() {
+	ac.lock.Lock()
+	defer ac.lock.Unlock()
+
+	ac.active--
+	ac.notify()
+}
+
+//Collect MetricsCollector interface implementation, it adjusts the state by the metrics of a bulk read
+func (ac *adaptiveController) Collect(m RequestMetrics) {
+	//the generation time doesn't include the network and the retries of the client, so it's preferred if it's known
+	latency := m.Duration
+	if m.GenerationTime > 0 {
+		latency = time.Duration(m.GenerationTime * float64(time.Second))
+	}
+
+	state, changed := ac.adjust(m, latency)
+	if !changed {
+		return
+	}
+
+	log.LogFields(
+		log.Debug,
+		"adjusted listing concurrency",
+		log.F("fetchersCount", state.FetchersCount),
+		log.F("itemsPerRequest", state.ItemsPerRequest),
+		log.F(log.FieldDuration, latency),
+		log.F(log.FieldErrorCode, m.ErrorCode),
+	)
+	if ac.settings.OnAdjust != nil {
+		ac.settings.OnAdjust(state)
+	}
+}
+
+func (ac *adaptiveController) adjust(m RequestMetrics, latency time.Duration) (AdaptiveState, bool) {
+	ac.lock.Lock()
+	defer ac.lock.Unlock()
+
+	prevState := ac.state
+	state := ac.state
+	switch {
+	case m.ErrorCode == HourlyRequestQuota || m.HTTPStatus == http.StatusTooManyRequests:
+		state.FetchersCount /= 2
+		ac.successes = 0
+	case m.Failed || latency > ac.settings.TargetLatency:
+		state.ItemsPerRequest /= 2
+		ac.successes = 0
+	default:
+		ac.successes++
+		if ac.successes < state.FetchersCount {
+			return state, false
+		}
+		ac.successes = 0
+		state.FetchersCount++
+		state.ItemsPerRequest += ac.pageSize
+	}
+
+	ac.state = ac.clamp(state)
+	if ac.state == prevState {
+		return ac.state, false
+	}
+	ac.notify()
+
+	return ac.state, true
+}
+
+func (ac *adaptiveController) clamp(state AdaptiveState) AdaptiveState {
+	if state.FetchersCount < ac.settings.MinFetchersCount {
+		state.FetchersCount = ac.settings.MinFetchersCount
+	}
+	if state.FetchersCount > ac.settings.MaxFetchersCount {
+		state.FetchersCount = ac.settings.MaxFetchersCount
+	}
+
+	minItemsPerRequest := ac.settings.MinItemsPerRequest
+	if minItemsPerRequest < ac.pageSize {
+		minItemsPerRequest = ac.pageSize
+	}
+	if state.ItemsPerRequest < minItemsPerRequest {
+		state.ItemsPerRequest = minItemsPerRequest
+	}
+	if state.ItemsPerRequest > ac.settings.MaxItemsPerRequest {
+		state.ItemsPerRequest = ac.settings.MaxItemsPerRequest
+	}
+
+	return state
+}
+
+//notify wakes up the reads which wait for a free fetcher
+func (ac *adaptiveController) notify() {
+	close(ac.changed)
+	ac.changed = make(chan struct{})
+}
+
+//readObserver gives the metrics of one bulk read to the controller, if the data provider doesn't call the API client
+//with the context of the read, the metrics are measured by Lister
+type readObserver struct {
+	controller *adaptiveController
+	reported   bool
+	lock       sync.Mutex
+}
+
+func (ro *readObserver) Collect(m RequestMetrics) {
+	ro.lock.Lock()
+	ro.reported = true
+	ro.lock.Unlock()
+
+	ro.controller.Collect(m)
+}
+
+//observedRead reads the bulk filters when a fetcher is free and gives the metrics of the read to the controller
+func (ac *adaptiveController) observedRead(ctx context.Context, read func(ctx context.Context) error) error {
+	if err := ac.acquire(ctx); err != nil {
+		return err
+	}
+	defer ac.release()
+
+	observer := &readObserver{controller: ac}
+	startTime := time.Now()
+	err := read(WithMetricsCollector(ctx, observer))
+
+	observer.lock.Lock()
+	reported := observer.reported
+	observer.lock.Unlock()
+	if reported || ctx.Err() != nil {
+		return err
+	}
+
+	m := RequestMetrics{
+		Duration: time.Since(startTime),
+		Failed:   err != nil,
+		IsBulk:   true,
+	}
+	var erplyErr *ErplyError
+	if errors.As(err, &erplyErr) {
+		m.ErrorCode = erplyErr.Code
+	}
+	ac.Collect(m)
+
+	return err
+}
@@ -0,0 +1,181 @@
+package common
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+//metricsReportingProviderMock reports the metrics of each read to the collector of the context like the API client does
+type metricsReportingProviderMock struct {
+	pagedProviderMock
+	generationTime float64
+	bulkSizes      []int
+}
+
+func (mrpm *metricsReportingProviderMock) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	mrpm.lock.Lock()
+	mrpm.bulkSizes = append(mrpm.bulkSizes, len(bulkFilters))
+	mrpm.lock.Unlock()
+
+	err := mrpm.pagedProviderMock.Read(ctx, bulkFilters, callback)
+	if collector := MetricsCollectorFromContext(ctx); collector != nil {
+		collector.Collect(RequestMetrics{IsBulk: true, GenerationTime: mrpm.generationTime, Failed: err != nil})
+	}
+
+	return err
+}
+
+//quotaProviderMock fails the first failuresCount reads with HourlyRequestQuota error
+type quotaProviderMock struct {
+	pagedProviderMock
+	failuresCount int
+	readsCount    int
+}
+
+func (qpm *quotaProviderMock) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	qpm.lock.Lock()
+	qpm.readsCount++
+	fail := qpm.readsCount <= qpm.failuresCount
+	qpm.lock.Unlock()
+
+	if fail {
+		return NewErplyError("Error", "quota exceeded", HourlyRequestQuota)
+	}
+
+	return qpm.pagedProviderMock.Read(ctx, bulkFilters, callback)
+}
+
+func newTestAdaptiveController(settings AdaptiveListingSettings) *adaptiveController {
+	listingSettings := setListingSettingsDefaults(ListingSettings{MaxFetchersCount: 4, MaxItemsPerRequest: 1000})
+	ac := newAdaptiveController(settings, listingSettings)
+	ac.setPageSize(100)
+
+	return ac
+}
+
+func TestAdaptiveControllerAdjusting(t *testing.T) {
+	var adjustedStates []AdaptiveState
+	ac := newTestAdaptiveController(AdaptiveListingSettings{
+		MaxFetchersCount:   6,
+		MaxItemsPerRequest: 2000,
+		TargetLatency:      time.Second,
+		OnAdjust: func(state AdaptiveState) {
+			adjustedStates = append(adjustedStates, state)
+		},
+	})
+	assert.Equal(t, AdaptiveState{FetchersCount: 4, ItemsPerRequest: 1000}, ac.getState())
+
+	//grows after each fetcher got a fast response
+	for i := 0; i < 4; i++ {
+		ac.Collect(RequestMetrics{Duration: 100 * time.Millisecond})
+	}
+	assert.Equal(t, AdaptiveState{FetchersCount: 5, ItemsPerRequest: 1100}, ac.getState())
+
+	//the generation time is preferred to the duration which includes the retries of the client
+	ac.Collect(RequestMetrics{Duration: 3 * time.Second, GenerationTime: 0.2})
+	assert.Equal(t, AdaptiveState{FetchersCount: 5, ItemsPerRequest: 1100}, ac.getState())
+
+	ac.Collect(RequestMetrics{Duration: 200 * time.Millisecond, GenerationTime: 1.5})
+	assert.Equal(t, AdaptiveState{FetchersCount: 5, ItemsPerRequest: 550}, ac.getState())
+
+	ac.Collect(RequestMetrics{Failed: true, ErrorCode: HourlyRequestQuota})
+	assert.Equal(t, AdaptiveState{FetchersCount: 2, ItemsPerRequest: 550}, ac.getState())
+
+	ac.Collect(RequestMetrics{Failed: true, HTTPStatus: 429})
+	ac.Collect(RequestMetrics{Failed: true, ErrorCode: HourlyRequestQuota})
+	ac.Collect(RequestMetrics{Failed: true})
+	ac.Collect(RequestMetrics{Failed: true})
+	ac.Collect(RequestMetrics{Failed: true})
+	//the bounds are respected
+	assert.Equal(t, AdaptiveState{FetchersCount: 1, ItemsPerRequest: 100}, ac.getState())
+
+	assert.Equal(t, []AdaptiveState{
+		{FetchersCount: 5, ItemsPerRequest: 1100},
+		{FetchersCount: 5, ItemsPerRequest: 550},
+		{FetchersCount: 2, ItemsPerRequest: 550},
+		{FetchersCount: 1, ItemsPerRequest: 550},
+		{FetchersCount: 1, ItemsPerRequest: 275},
+		{FetchersCount: 1, ItemsPerRequest: 137},
+		{FetchersCount: 1, ItemsPerRequest: 100},
+	}, adjustedStates)
+}
+
+func TestAdaptiveControllerLimitsParallelReads(t *testing.T) {
+	ac := newTestAdaptiveController(AdaptiveListingSettings{MaxFetchersCount: 2})
+	ac.Collect(RequestMetrics{ErrorCode: HourlyRequestQuota})
+	assert.Equal(t, 1, ac.getState().FetchersCount)
+
+	assert.NoError(t, ac.acquire(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, ac.acquire(ctx))
+
+	acquired := make(chan struct{})
+	go func() {
+		assert.NoError(t, ac.acquire(context.Background()))
+		close(acquired)
+	}()
+	ac.release()
+	<-acquired
+}
+
+func TestAdaptiveListerShrinksBulkSize(t *testing.T) {
+	dp := &metricsReportingProviderMock{
+		pagedProviderMock: pagedProviderMock{total: 3000},
+		generationTime:    10,
+	}
+	settings := ListingSettings{
+		MaxItemsPerRequest: 1000,
+		MaxFetchersCount:   1,
+		Adaptive:           &AdaptiveListingSettings{TargetLatency: time.Second},
+	}
+
+	lister := NewLister(settings, dp, NullSleeper)
+	ids, errs := collectIDs(t, lister.Get(context.Background(), map[string]interface{}{}))
+
+	assert.Empty(t, errs)
+	assert.Len(t, ids, 3000)
+	assert.Equal(t, 10, dp.bulkSizes[0])
+	assert.True(t, dp.bulkSizes[len(dp.bulkSizes)-1] < 10)
+	for i := 1; i < len(dp.bulkSizes)-1; i++ {
+		assert.True(t, dp.bulkSizes[i] <= dp.bulkSizes[i-1])
+	}
+	assert.Equal(t, AdaptiveState{FetchersCount: 1, ItemsPerRequest: 100}, lister.AdaptiveState())
+}
+
+func TestAdaptiveListerReducesFetchersOnQuotaErrors(t *testing.T) {
+	dp := &quotaProviderMock{
+		pagedProviderMock: pagedProviderMock{total: 1000},
+		failuresCount:     2,
+	}
+	lock := sync.Mutex{}
+	var fetchersCounts []int
+	settings := ListingSettings{
+		MaxItemsPerRequest: 100,
+		MaxFetchersCount:   4,
+		Adaptive: &AdaptiveListingSettings{
+			OnAdjust: func(state AdaptiveState) {
+				lock.Lock()
+				defer lock.Unlock()
+				fetchersCounts = append(fetchersCounts, state.FetchersCount)
+			},
+		},
+	}
+
+	lister := NewLister(settings, dp, NullSleeper)
+	ids, errs := collectIDs(t, lister.Get(context.Background(), map[string]interface{}{}))
+
+	//the failed reads are retried
+	assert.Empty(t, errs)
+	assert.Len(t, ids, 1000)
+	assert.Equal(t, []int{2, 1}, fetchersCounts[:2])
+}
+
+func TestNotAdaptiveListerState(t *testing.T) {
+	lister := NewLister(ListingSettings{MaxItemsPerRequest: 500, MaxFetchersCount: 3}, &pagedProviderMock{}, NullSleeper)
+	assert.Equal(t, AdaptiveState{FetchersCount: 3, ItemsPerRequest: 500}, lister.AdaptiveState())
+}
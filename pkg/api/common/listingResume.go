@@ -0,0 +1,373 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+//ResumeToken is the state of a resumable listing, it's encoded to JSON to be kept between the runs
+type ResumeToken struct {
+	Filters        map[string]interface{} `json:"filters"`
+	TotalCount     int                    `json:"totalCount"`     //the count of items when the listing was started
+	PageSize       int                    `json:"pageSize"`       //recordsOnPage of all pages, 0 if the listing wasn't planned yet
+	CompletedPages []int                  `json:"completedPages"` //pageNo of the pages which items were received by the consumer
+}
+
+//PagesCount gives the count of pages of the listing
+func (rt ResumeToken) PagesCount() int {
+	if rt.PageSize <= 0 {
+		return 0
+	}
+
+	return CeilDivisionInt(rt.TotalCount, rt.PageSize)
+}
+
+//IsCompleted tells if the items of all pages were received
+func (rt ResumeToken) IsCompleted() bool {
+	return rt.PageSize > 0 && len(rt.CompletedPages) >= rt.PagesCount()
+}
+
+//PendingCursors gives the pages which are not completed yet
+func (rt ResumeToken) PendingCursors() []Cursor {
+	completed := make(map[int]bool, len(rt.CompletedPages))
+	for _, page := range rt.CompletedPages {
+		completed[page] = true
+	}
+
+	cursors := make([]Cursor, 0, rt.PagesCount()-len(completed))
+	for page := 1; page <= rt.PagesCount(); page++ {
+		if !completed[page] {
+			cursors = append(cursors, Cursor{Limit: rt.PageSize, Offset: page})
+		}
+	}
+
+	return cursors
+}
+
+//ResumeStore persists the resume tokens of listings, e.g. in the database of the export
+type ResumeStore interface {
+	//LoadResumeToken gives nil if there is no token for the key
+	LoadResumeToken(ctx context.Context, key string) (*ResumeToken, error)
+	SaveResumeToken(ctx context.Context, key string, token ResumeToken) error
+	DeleteResumeToken(ctx context.Context, key string) error
+}
+
+//InMemoryResumeStore keeps the resume tokens in memory of the process, mostly for tests
+type InMemoryResumeStore struct {
+	tokens map[string]ResumeToken
+	lock   sync.Mutex
+}
+
+//NewInMemoryResumeStore creates an empty InMemoryResumeStore
+func NewInMemoryResumeStore() *InMemoryResumeStore {
+	return &InMemoryResumeStore{tokens: map[string]ResumeToken{}}
+}
+
+//LoadResumeToken ResumeStore interface implementation
+func (imrs *InMemoryResumeStore) LoadResumeToken(ctx context.Context, key string) (*ResumeToken, error) {
+	imrs.lock.Lock()
+	defer imrs.lock.Unlock()
+
+	token, ok := imrs.tokens[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return &token, nil
+}
+
+//SaveResumeToken ResumeStore interface implementation
+func (imrs *InMemoryResumeStore) SaveResumeToken(ctx context.Context, key string, token ResumeToken) error {
+	imrs.lock.Lock()
+	defer imrs.lock.Unlock()
+
+	imrs.tokens[key] = token
+	return nil
+}
+
+//DeleteResumeToken ResumeStore interface implementation
+func (imrs *InMemoryResumeStore) DeleteResumeToken(ctx context.Context, key string) error {
+	imrs.lock.Lock()
+	defer imrs.lock.Unlock()
+
+	delete(imrs.tokens, key)
+	return nil
+}
+
+//FileResumeStore keeps the resume tokens of all listings in a JSON file, the file is replaced atomically on saving
+type FileResumeStore struct {
+	path string
+	lock sync.Mutex
+}
+
+//NewFileResumeStore creates FileResumeStore, the file is created with the first saved token
+func NewFileResumeStore(path string) *FileResumeStore {
+	return &FileResumeStore{path: path}
+}
+
+//LoadResumeToken ResumeStore interface implementation, the numbers of the filters are given as json.Number
+func (frs *FileResumeStore) LoadResumeToken(ctx context.Context, key string) (*ResumeToken, error) {
+	frs.lock.Lock()
+	defer frs.lock.Unlock()
+
+	tokens, err := frs.read()
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := tokens[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return &token, nil
+}
+
+//SaveResumeToken ResumeStore interface implementation
+func (frs *FileResumeStore) SaveResumeToken(ctx context.Context, key string, token ResumeToken) error {
+	frs.lock.Lock()
+	defer frs.lock.Unlock()
+
+	tokens, err := frs.read()
+	if err != nil {
+		return err
+	}
+	tokens[key] = token
+
+	return frs.write(tokens)
+}
+
+//DeleteResumeToken ResumeStore interface implementation
+func (frs *FileResumeStore) DeleteResumeToken(ctx context.Context, key string) error {
+	frs.lock.Lock()
+	defer frs.lock.Unlock()
+
+	tokens, err := frs.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[key]; !ok {
+		return nil
+	}
+	delete(tokens, key)
+
+	return frs.write(tokens)
+}
+
+func (frs *FileResumeStore) read() (map[string]ResumeToken, error) {
+	tokens := map[string]ResumeToken{}
+
+	content, err := ioutil.ReadFile(frs.path)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("failed to read resume tokens from %s: %v", frs.path, err)
+	}
+
+	return tokens, nil
+}
+
+func (frs *FileResumeStore) write(tokens map[string]ResumeToken) error {
+	content, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(frs.path), 0755); err != nil {
+		return err
+	}
+
+	tmpPath := frs.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, content, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, frs.path)
+}
+
+//ListingProgress tracks the pages of a resumable listing which items were received by the consumer
+type ListingProgress struct {
+	token     ResumeToken
+	completed map[int]bool
+	store     ResumeStore
+	key       string
+	lock      sync.Mutex
+}
+
+func newListingProgress(token ResumeToken) *ListingProgress {
+	lp := &ListingProgress{
+		token:     token,
+		completed: make(map[int]bool, len(token.CompletedPages)),
+	}
+	for _, page := range token.CompletedPages {
+		lp.completed[page] = true
+	}
+
+	return lp
+}
+
+//Token gives the current state of the listing, a page is completed when the consumer received all its items.
+//Take the token after processing the received items, so the resumed listing gives no items which weren't processed.
+func (lp *ListingProgress) Token() ResumeToken {
+	lp.lock.Lock()
+	defer lp.lock.Unlock()
+
+	token := lp.token
+	token.CompletedPages = make([]int, 0, len(lp.completed))
+	for page := range lp.completed {
+		token.CompletedPages = append(token.CompletedPages, page)
+	}
+	sort.Ints(token.CompletedPages)
+
+	return token
+}
+
+//Save persists the token in the store of the listing started with GetWithResumeStore,
+//the token is removed when the listing is completed, so the next run starts from the beginning
+func (lp *ListingProgress) Save(ctx context.Context) error {
+	if lp.store == nil {
+		return errors.New("listing progress has no resume store")
+	}
+
+	token := lp.Token()
+	if token.IsCompleted() {
+		return lp.store.DeleteResumeToken(ctx, lp.key)
+	}
+
+	return lp.store.SaveResumeToken(ctx, lp.key, token)
+}
+
+func (lp *ListingProgress) complete(pages []int) {
+	lp.lock.Lock()
+	defer lp.lock.Unlock()
+
+	for _, page := range pages {
+		lp.completed[page] = true
+	}
+}
+
+//GetResumable is Get which tracks the completed pages, see ListingProgress.Token.
+//A page is read again after resuming if its request failed, so some items can be given twice.
+func (p *Lister) GetResumable(ctx context.Context, filters map[string]interface{}) (ItemsStream, *ListingProgress) {
+	return p.Resume(ctx, ResumeToken{Filters: filters})
+}
+
+//Resume continues the listing from the token skipping its completed pages, the total count of the token is used,
+//so the pages are the same as in the interrupted run. The token without PageSize starts the listing from the beginning.
+func (p *Lister) Resume(ctx context.Context, token ResumeToken) (ItemsStream, *ListingProgress) {
+	filters := withoutPaging(token.Filters)
+
+	if token.PageSize <= 0 {
+		countFilters := make(map[string]interface{}, len(filters)+2)
+		for k, v := range filters {
+			countFilters[k] = v
+		}
+		countFilters["recordsOnPage"] = 1
+		countFilters["pageNo"] = 1
+
+		totalCount, err := p.count(ctx, countFilters)
+		if err != nil {
+			return newErrorStream(err, totalCount), newListingProgress(ResumeToken{Filters: filters})
+		}
+		token = ResumeToken{
+			Filters:    filters,
+			TotalCount: totalCount,
+			PageSize:   p.getPageSize(totalCount),
+		}
+	}
+	token.Filters = filters
+
+	progress := newListingProgress(token)
+	completedPages := make(map[int]bool, len(token.CompletedPages))
+	for _, page := range token.CompletedPages {
+		completedPages[page] = true
+	}
+
+	fetchFilters := make(map[string]interface{}, len(filters))
+	for k, v := range filters {
+		fetchFilters[k] = v
+	}
+	itemsStream := p.fetch(ctx, fetchFilters, token.TotalCount, token.PageSize, completedPages)
+
+	//the output is not buffered, so the pages are completed only after the consumer received all their items
+	outputChan := make(ItemsStream)
+	go func() {
+		defer close(outputChan)
+		for item := range itemsStream {
+			if item.completedPages != nil {
+				progress.complete(item.completedPages)
+				continue
+			}
+
+			select {
+			case outputChan <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outputChan, progress
+}
+
+//GetWithResumeStore resumes the listing from the token saved with the key or starts it if there is no token
+//or its filters differ, call ListingProgress.Save to persist the progress
+func (p *Lister) GetWithResumeStore(ctx context.Context, key string, filters map[string]interface{}, store ResumeStore) (ItemsStream, *ListingProgress, error) {
+	token, err := store.LoadResumeToken(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if token == nil || !sameFilters(withoutPaging(token.Filters), withoutPaging(filters)) {
+		token = &ResumeToken{Filters: filters}
+	}
+
+	itemsStream, progress := p.Resume(ctx, *token)
+	progress.store = store
+	progress.key = key
+
+	return itemsStream, progress, nil
+}
+
+//sameFilters compares the filters by their printed values as the stored tokens can have e.g. json.Number instead of int
+func sameFilters(filters1, filters2 map[string]interface{}) bool {
+	if len(filters1) != len(filters2) {
+		return false
+	}
+	for k, v := range filters1 {
+		v2, ok := filters2[k]
+		if !ok || fmt.Sprint(v) != fmt.Sprint(v2) {
+			return false
+		}
+	}
+
+	return true
+}
+
+//withoutPaging copies the filters without recordsOnPage and pageNo which are set by Lister
+func withoutPaging(filters map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(filters))
+	for k, v := range filters {
+		if k == "recordsOnPage" || k == "pageNo" {
+			continue
+		}
+		res[k] = v
+	}
+
+	return res
+}
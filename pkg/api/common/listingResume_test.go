@@ -0,0 +1,197 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+)
+
+//pagedProviderMock gives the ids from 1 to total paged by pageNo and recordsOnPage, the pages from failPages fail
+type pagedProviderMock struct {
+	lock       sync.Mutex
+	total      int
+	failPages  map[int]bool
+	countCalls int
+	readPages  []int
+}
+
+func (ppm *pagedProviderMock) Count(ctx context.Context, filters map[string]interface{}) (int, error) {
+	ppm.lock.Lock()
+	defer ppm.lock.Unlock()
+
+	ppm.countCalls++
+	return ppm.total, nil
+}
+
+func (ppm *pagedProviderMock) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	ppm.lock.Lock()
+	defer ppm.lock.Unlock()
+
+	for _, filters := range bulkFilters {
+		pageNo := filters["pageNo"].(int)
+		recordsOnPage := filters["recordsOnPage"].(int)
+		if ppm.failPages[pageNo] {
+			return errors.New("some page error")
+		}
+		ppm.readPages = append(ppm.readPages, pageNo)
+		for id := (pageNo-1)*recordsOnPage + 1; id <= pageNo*recordsOnPage && id <= ppm.total; id++ {
+			callback(payloadMock{ID: id})
+		}
+	}
+
+	return nil
+}
+
+func collectIDs(t *testing.T, itemsStream ItemsStream) (ids []int, errs []error) {
+	for item := range itemsStream {
+		if item.Err != nil {
+			errs = append(errs, item.Err)
+			continue
+		}
+		ids = append(ids, item.Payload.(payloadMock).ID)
+	}
+	sort.Ints(ids)
+
+	return ids, errs
+}
+
+func TestResumeFailedListing(t *testing.T) {
+	dp := &pagedProviderMock{total: 10, failPages: map[int]bool{3: true}}
+	store := NewInMemoryResumeStore()
+	settings := ListingSettings{MaxItemsPerRequest: 2, MaxFetchersCount: 2}
+	filters := map[string]interface{}{"filterKey": "filterVal"}
+
+	itemsStream, progress, err := NewLister(settings, dp, NullSleeper).GetWithResumeStore(context.Background(), "products", filters, store)
+	assert.NoError(t, err)
+
+	ids, errs := collectIDs(t, itemsStream)
+	assert.Equal(t, []int{1, 2, 3, 4, 7, 8, 9, 10}, ids)
+	assert.Len(t, errs, 1)
+	assert.NoError(t, progress.Save(context.Background()))
+
+	token, err := store.LoadResumeToken(context.Background(), "products")
+	assert.NoError(t, err)
+	assert.Equal(t, &ResumeToken{
+		Filters:        map[string]interface{}{"filterKey": "filterVal"},
+		TotalCount:     10,
+		PageSize:       2,
+		CompletedPages: []int{1, 2, 4, 5},
+	}, token)
+	assert.Equal(t, []Cursor{{Limit: 2, Offset: 3}}, token.PendingCursors())
+	assert.False(t, token.IsCompleted())
+
+	//the total count is taken from the token, so the new items don't shift the pages
+	dp.failPages = nil
+	dp.total = 12
+	dp.readPages = nil
+	itemsStream, progress, err = NewLister(settings, dp, NullSleeper).GetWithResumeStore(context.Background(), "products", filters, store)
+	assert.NoError(t, err)
+
+	ids, errs = collectIDs(t, itemsStream)
+	assert.Equal(t, []int{5, 6}, ids)
+	assert.Empty(t, errs)
+	assert.Equal(t, []int{3}, dp.readPages)
+	assert.Equal(t, 1, dp.countCalls)
+	assert.True(t, progress.Token().IsCompleted())
+
+	assert.NoError(t, progress.Save(context.Background()))
+	token, err = store.LoadResumeToken(context.Background(), "products")
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+}
+
+func TestResumeWithOtherFiltersStartsAgain(t *testing.T) {
+	dp := &pagedProviderMock{total: 4}
+	store := NewInMemoryResumeStore()
+	err := store.SaveResumeToken(context.Background(), "products", ResumeToken{
+		Filters:        map[string]interface{}{"filterKey": "oldVal"},
+		TotalCount:     4,
+		PageSize:       2,
+		CompletedPages: []int{1},
+	})
+	assert.NoError(t, err)
+
+	itemsStream, _, err := NewLister(ListingSettings{MaxItemsPerRequest: 2}, dp, NullSleeper).GetWithResumeStore(
+		context.Background(),
+		"products",
+		map[string]interface{}{"filterKey": "newVal"},
+		store,
+	)
+	assert.NoError(t, err)
+
+	ids, errs := collectIDs(t, itemsStream)
+	assert.Equal(t, []int{1, 2, 3, 4}, ids)
+	assert.Empty(t, errs)
+	assert.Equal(t, 1, dp.countCalls)
+}
+
+func TestResumeInterruptedListing(t *testing.T) {
+	dp := &pagedProviderMock{total: 6}
+	lister := NewLister(ListingSettings{MaxItemsPerRequest: 2, MaxFetchersCount: 1}, dp, NullSleeper)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	itemsStream, progress := lister.GetResumable(ctx, map[string]interface{}{})
+
+	//the consumer stops after the first page and a half
+	receivedIDs := make([]int, 0, 3)
+	for item := range itemsStream {
+		receivedIDs = append(receivedIDs, item.Payload.(payloadMock).ID)
+		if len(receivedIDs) == 3 {
+			break
+		}
+	}
+	token := progress.Token()
+	cancel()
+	//the fetchers of the cancelled listing are done once the stream is closed
+	for range itemsStream {
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, receivedIDs)
+	assert.Equal(t, []int{1}, token.CompletedPages)
+
+	dp.lock.Lock()
+	dp.readPages = nil
+	dp.lock.Unlock()
+	itemsStream, _ = NewLister(ListingSettings{MaxItemsPerRequest: 2}, dp, NullSleeper).Resume(context.Background(), token)
+	ids, errs := collectIDs(t, itemsStream)
+	assert.Equal(t, []int{3, 4, 5, 6}, ids)
+	assert.Empty(t, errs)
+	assert.Equal(t, []int{2, 3}, dp.readPages)
+}
+
+func TestFileResumeStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "listing", "tokens.json")
+	store := NewFileResumeStore(path)
+
+	token, err := store.LoadResumeToken(context.Background(), "products")
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+
+	saved := ResumeToken{
+		Filters:        map[string]interface{}{"active": 1, "type": "PRODUCT"},
+		TotalCount:     300,
+		PageSize:       100,
+		CompletedPages: []int{2},
+	}
+	assert.NoError(t, store.SaveResumeToken(context.Background(), "products", saved))
+	assert.NoError(t, store.SaveResumeToken(context.Background(), "customers", ResumeToken{TotalCount: 1}))
+
+	token, err = NewFileResumeStore(path).LoadResumeToken(context.Background(), "products")
+	assert.NoError(t, err)
+	assert.Equal(t, 300, token.TotalCount)
+	assert.Equal(t, []int{2}, token.CompletedPages)
+	assert.True(t, sameFilters(saved.Filters, token.Filters))
+
+	assert.NoError(t, store.DeleteResumeToken(context.Background(), "products"))
+	token, err = store.LoadResumeToken(context.Background(), "products")
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+
+	token, err = store.LoadResumeToken(context.Background(), "customers")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, token.TotalCount)
+}
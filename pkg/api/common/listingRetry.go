@@ -0,0 +1,356 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//maxListingSplitDepth limits how many times the failing parts of a chunk are split again,
+//it bounds the amount of the requests made for one failing chunk
+const maxListingSplitDepth = 4
+
+//ListingPagesError is the error of the listing pages which couldn't be read after all retries and splits of their bulk request
+type ListingPagesError struct {
+	Cursors []Cursor //pages which items were not received, ordered by Offset
+	Err     error    //last error of reading the pages
+}
+
+func (lpe *ListingPagesError) Error() string {
+	return fmt.Sprintf("failed to read listing pages %s: %v", FormatCursorRanges(lpe.Cursors), lpe.Err)
+}
+
+//Unwrap gives the error of reading the pages
+func (lpe *ListingPagesError) Unwrap() error {
+	return lpe.Err
+}
+
+//FormatCursorRanges describes the cursors as ranges of consecutive pages, e.g. "3-5, 9 (recordsOnPage 100)"
+func FormatCursorRanges(cursors []Cursor) string {
+	if len(cursors) == 0 {
+		return "none"
+	}
+
+	sorted := make([]Cursor, len(cursors))
+	copy(sorted, cursors)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Offset < sorted[j].Offset
+	})
+
+	ranges := make([]string, 0, len(sorted))
+	first := sorted[0]
+	last := sorted[0]
+	addRange := func() {
+		if first.Offset == last.Offset {
+			ranges = append(ranges, fmt.Sprint(first.Offset))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", first.Offset, last.Offset))
+		}
+	}
+	for _, cursor := range sorted[1:] {
+		if cursor.Offset == last.Offset+1 && cursor.Limit == last.Limit {
+			last = cursor
+			continue
+		}
+		addRange()
+		first = cursor
+		last = cursor
+	}
+	addRange()
+
+	return fmt.Sprintf("%s (recordsOnPage %d)", strings.Join(ranges, ", "), first.Limit)
+}
+
+//NonRetryableError marks the error of a data provider which repeating the same request won't fix, e.g. a validation error,
+//Lister doesn't retry it
+type NonRetryableError struct {
+	Err error
+}
+
+//NewNonRetryableError wraps err into NonRetryableError
+func NewNonRetryableError(err error) error {
+	return &NonRetryableError{Err: err}
+}
+
+func (nre *NonRetryableError) Error() string {
+	return nre.Err.Error()
+}
+
+//Unwrap gives the wrapped error
+func (nre *NonRetryableError) Unwrap() error {
+	return nre.Err
+}
+
+//AsListingPagesError gives ListingPagesError if err is or wraps it
+func AsListingPagesError(err error) (*ListingPagesError, bool) {
+	var pagesErr *ListingPagesError
+	if errors.As(err, &pagesErr) {
+		return pagesErr, true
+	}
+
+	return nil, false
+}
+
+//fetchCursors reads the pages of the cursors retrying the failed bulk requests, the chunk which keeps failing
+//is split into smaller requests to isolate the failing pages, it gives the errors of the pages which couldn't be read
+func (p *Lister) fetchCursors(
+	ctx context.Context,
+	cursors []Cursor,
+	totalCount int,
+	filters map[string]interface{},
+	trackPages bool,
+	emit func(item Item),
+) []*ListingPagesError {
+	err := p.readCursors(ctx, cursors, totalCount, filters, trackPages, emit)
+	if err == nil {
+		return nil
+	}
+
+	return p.splitFailedCursors(ctx, cursors, err, 1, totalCount, filters, trackPages, emit)
+}
+
+func (p *Lister) splitFailedCursors(
+	ctx context.Context,
+	cursors []Cursor,
+	err error,
+	depth int,
+	totalCount int,
+	filters map[string]interface{},
+	trackPages bool,
+	emit func(item Item),
+) []*ListingPagesError {
+	if len(cursors) == 1 || depth > maxListingSplitDepth || ctx.Err() != nil {
+		return []*ListingPagesError{{Cursors: cursors, Err: err}}
+	}
+
+	groups := splitCursors(cursors, err)
+	groupErrs := make([]error, len(groups))
+	sameErrCount := 0
+	for i, group := range groups {
+		groupErrs[i] = p.readCursors(ctx, group, totalCount, filters, trackPages, emit)
+		if groupErrs[i] != nil && isSameListingError(groupErrs[i], err) {
+			sameErrCount++
+		}
+	}
+
+	//the error doesn't depend on the pages, e.g. a wrong filter, so the smaller requests won't help
+	if sameErrCount == len(groups) {
+		return []*ListingPagesError{{Cursors: cursors, Err: err}}
+	}
+
+	var pagesErrs []*ListingPagesError
+	for i, group := range groups {
+		if groupErrs[i] != nil {
+			pagesErrs = append(pagesErrs, p.splitFailedCursors(ctx, group, groupErrs[i], depth+1, totalCount, filters, trackPages, emit)...)
+		}
+	}
+
+	return pagesErrs
+}
+
+//splitCursors separates the pages of the failed sub-requests if err is BulkError, otherwise it splits the cursors in halves
+func splitCursors(cursors []Cursor, err error) [][]Cursor {
+	if bulkErr, ok := AsBulkError(err); ok {
+		failed := make(map[int]bool, len(bulkErr.Items))
+		for _, index := range bulkErr.FailedIndexes() {
+			if index >= 0 && index < len(cursors) {
+				failed[index] = true
+			}
+		}
+
+		if len(failed) > 0 && len(failed) < len(cursors) {
+			groups := make([][]Cursor, 0, len(failed)+1)
+			succeeded := make([]Cursor, 0, len(cursors)-len(failed))
+			for i, cursor := range cursors {
+				if failed[i] {
+					groups = append(groups, []Cursor{cursor})
+				} else {
+					succeeded = append(succeeded, cursor)
+				}
+			}
+
+			return append([][]Cursor{succeeded}, groups...)
+		}
+	}
+
+	middle := len(cursors) / 2
+	return [][]Cursor{cursors[:middle], cursors[middle:]}
+}
+
+//readCursors reads the pages as one bulk request, the items are emitted only if the request succeeded,
+//so a failed attempt gives neither partial pages nor duplicates
+func (p *Lister) readCursors(
+	ctx context.Context,
+	cursors []Cursor,
+	totalCount int,
+	filters map[string]interface{},
+	trackPages bool,
+	emit func(item Item),
+) error {
+	bulkFilters := make([]map[string]interface{}, 0, len(cursors))
+	for _, cursor := range cursors {
+		bulkFilter := make(map[string]interface{})
+		for filterKey, filterValue := range filters {
+			bulkFilter[filterKey] = filterValue
+		}
+		bulkFilter["recordsOnPage"] = cursor.Limit
+		bulkFilter["pageNo"] = cursor.Offset
+		bulkFilters = append(bulkFilters, bulkFilter)
+	}
+
+	items, err := p.readWithRetry(ctx, bulkFilters, totalCount)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		emit(item)
+	}
+	if trackPages {
+		pages := make([]int, 0, len(cursors))
+		for _, cursor := range cursors {
+			pages = append(pages, cursor.Offset)
+		}
+		emit(Item{
+			TotalCount:     totalCount,
+			completedPages: pages,
+		})
+	}
+
+	return nil
+}
+
+//readWithRetry reads the bulk filters repeating the failed requests according to the retry policy,
+//it gives the items of the successful attempt
+func (p *Lister) readWithRetry(ctx context.Context, bulkFilters []map[string]interface{}, totalCount int) ([]Item, error) {
+	retryPolicy := p.getRetryPolicy()
+	for attempt := 1; ; attempt++ {
+		items := make([]Item, 0)
+		read := func(ctx context.Context) error {
+			return p.listingDataProvider.Read(ctx, bulkFilters, func(item interface{}) {
+				items = append(items, Item{
+					Err:        nil,
+					TotalCount: totalCount,
+					Payload:    item,
+				})
+			})
+		}
+
+		err := p.reqThrottler.Throttle(ctx)
+		if err == nil && p.adaptive != nil {
+			err = p.adaptive.observedRead(ctx, read)
+		} else if err == nil {
+			err = read(ctx)
+		}
+
+		if err == nil {
+			return items, nil
+		}
+
+		if attempt >= retryPolicy.MaxAttempts || ctx.Err() != nil || !isRetryableListingError(err, retryPolicy) {
+			return nil, err
+		}
+
+		if p.sleeper != nil {
+			p.sleeper(retryPolicy.Backoff(attempt))
+		} else if waitErr := retryPolicy.Wait(ctx, attempt); waitErr != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *Lister) getRetryPolicy() RetryPolicy {
+	if p.listingSettings.RetryPolicy != nil {
+		return *p.listingSettings.RetryPolicy
+	}
+
+	return NewDefaultRetryPolicy()
+}
+
+//isRetryableListingError tells if reading the pages can succeed later, the API errors are checked by the retry policy,
+//decoding errors and NonRetryableError are permanent, other errors, e.g. network failures, are considered temporary
+func isRetryableListingError(err error, retryPolicy RetryPolicy) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || isPermanentListingError(err) {
+		return false
+	}
+
+	if bulkErr, ok := AsBulkError(err); ok {
+		return bulkErr.IsRetryable(retryPolicy)
+	}
+
+	var erplyErr *ErplyError
+	if errors.As(err, &erplyErr) {
+		return retryPolicy.IsRetryableCode(erplyErr.Code)
+	}
+
+	return true
+}
+
+//isPermanentListingError tells if the error doesn't depend on the attempt, e.g. an invalid response which couldn't be decoded
+func isPermanentListingError(err error) bool {
+	var nonRetryableErr *NonRetryableError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var invalidUnmarshalErr *json.InvalidUnmarshalError
+
+	return errors.As(err, &nonRetryableErr) ||
+		errors.As(err, &syntaxErr) ||
+		errors.As(err, &typeErr) ||
+		errors.As(err, &invalidUnmarshalErr)
+}
+
+//isSameListingError compares the errors by the API error codes or by the type of the underlying error,
+//the messages are not compared as they can contain the response body or the pages
+func isSameListingError(err, parentErr error) bool {
+	errCodes, isAPIErr := getListingErrorCodes(err)
+	parentCodes, isParentAPIErr := getListingErrorCodes(parentErr)
+	if isAPIErr || isParentAPIErr {
+		return isAPIErr && isParentAPIErr && reflect.DeepEqual(errCodes, parentCodes)
+	}
+
+	return reflect.TypeOf(getRootError(err)) == reflect.TypeOf(getRootError(parentErr))
+}
+
+//getListingErrorCodes gives the distinct sorted codes of ErplyError or of the items of BulkError
+func getListingErrorCodes(err error) ([]ApiError, bool) {
+	if bulkErr, ok := AsBulkError(err); ok {
+		codesMap := map[ApiError]bool{}
+		for _, item := range bulkErr.Items {
+			var code ApiError
+			if item.Err != nil {
+				code = item.Err.Code
+			}
+			codesMap[code] = true
+		}
+		codes := make([]ApiError, 0, len(codesMap))
+		for code := range codesMap {
+			codes = append(codes, code)
+		}
+		sort.Slice(codes, func(i, j int) bool {
+			return codes[i] < codes[j]
+		})
+
+		return codes, true
+	}
+
+	var erplyErr *ErplyError
+	if errors.As(err, &erplyErr) {
+		return []ApiError{erplyErr.Code}, true
+	}
+
+	return nil, false
+}
+
+func getRootError(err error) error {
+	for {
+		unwrapped := errors.Unwrap(err)
+		if unwrapped == nil {
+			return err
+		}
+		err = unwrapped
+	}
+}
@@ -0,0 +1,260 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+//flakyProviderMock fails the first failuresCount reads, the items of the failed reads are given partially
+type flakyProviderMock struct {
+	pagedProviderMock
+	failuresCount int
+	readsCount    int
+	err           error
+}
+
+func (fpm *flakyProviderMock) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	fpm.lock.Lock()
+	fpm.readsCount++
+	fail := fpm.readsCount <= fpm.failuresCount
+	fpm.lock.Unlock()
+
+	if fail {
+		callback(payloadMock{ID: 1})
+		return fpm.err
+	}
+
+	return fpm.pagedProviderMock.Read(ctx, bulkFilters, callback)
+}
+
+//bulkErrorProviderMock fails the sub-requests of failPages with BulkError like the bulk API calls do
+type bulkErrorProviderMock struct {
+	pagedProviderMock
+	bulkCalls [][]int
+}
+
+func (bepm *bulkErrorProviderMock) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	pages := make([]int, 0, len(bulkFilters))
+	bulkErr := &BulkError{TotalCount: len(bulkFilters)}
+	for i, filters := range bulkFilters {
+		pageNo := filters["pageNo"].(int)
+		pages = append(pages, pageNo)
+		if bepm.failPages[pageNo] {
+			bulkErr.Items = append(bulkErr.Items, BulkItemError{
+				Index:       i,
+				RequestName: "getProducts",
+				Err:         NewErplyError("Error", "some page error", MalformedRequest),
+			})
+		}
+	}
+
+	bepm.lock.Lock()
+	bepm.bulkCalls = append(bepm.bulkCalls, pages)
+	bepm.lock.Unlock()
+
+	if len(bulkErr.Items) > 0 {
+		return bulkErr
+	}
+
+	return bepm.pagedProviderMock.Read(ctx, bulkFilters, callback)
+}
+
+//failingProviderMock fails every read with the error given by readErr for the bulk filters of the read
+type failingProviderMock struct {
+	pagedProviderMock
+	readsCount int
+	readErr    func(bulkFilters []map[string]interface{}) error
+}
+
+func (fpm *failingProviderMock) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	fpm.lock.Lock()
+	defer fpm.lock.Unlock()
+
+	fpm.readsCount++
+	return fpm.readErr(bulkFilters)
+}
+
+type sleepsRecorder struct {
+	lock   sync.Mutex
+	sleeps []time.Duration
+}
+
+func (sr *sleepsRecorder) Sleep(sleepTime time.Duration) {
+	sr.lock.Lock()
+	defer sr.lock.Unlock()
+
+	sr.sleeps = append(sr.sleeps, sleepTime)
+}
+
+func TestRetryFailedChunk(t *testing.T) {
+	dp := &flakyProviderMock{
+		pagedProviderMock: pagedProviderMock{total: 5},
+		failuresCount:     2,
+		err:               errors.New("some network error"),
+	}
+	sleeper := &sleepsRecorder{}
+	retryPolicy := &RetryPolicy{MaxAttempts: 3, InitialInterval: time.Second, Multiplier: 2}
+
+	lister := NewLister(ListingSettings{RetryPolicy: retryPolicy}, dp, sleeper.Sleep)
+	ids, errs := collectIDs(t, lister.Get(context.Background(), map[string]interface{}{}))
+
+	assert.Empty(t, errs)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+	assert.Equal(t, 3, dp.readsCount)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, sleeper.sleeps)
+}
+
+func TestNoRetryForNotRetryableApiError(t *testing.T) {
+	dp := &flakyProviderMock{
+		pagedProviderMock: pagedProviderMock{total: 5},
+		failuresCount:     1,
+		err:               NewErplyError("Error", "wrong filter", MalformedRequest),
+	}
+
+	lister := NewLister(ListingSettings{}, dp, NullSleeper)
+	ids, errs := collectIDs(t, lister.Get(context.Background(), map[string]interface{}{}))
+
+	assert.Empty(t, ids)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, 1, dp.readsCount)
+
+	var erplyErr *ErplyError
+	assert.True(t, errors.As(errs[0], &erplyErr))
+	assert.Equal(t, MalformedRequest, erplyErr.Code)
+}
+
+func TestNoRetryForDecodingError(t *testing.T) {
+	dp := &failingProviderMock{
+		pagedProviderMock: pagedProviderMock{total: 1000},
+		readErr: func(bulkFilters []map[string]interface{}) error {
+			var res interface{}
+			err := json.Unmarshal([]byte("some junk value"), &res)
+			//the message differs for each request like the errors which include the response body
+			return fmt.Errorf("failed to unmarshal response of %d pages: %w", len(bulkFilters), err)
+		},
+	}
+
+	lister := NewLister(ListingSettings{MaxItemsPerRequest: 1000, MaxFetchersCount: 1}, dp, NullSleeper)
+	ids, errs := collectIDs(t, lister.Get(context.Background(), map[string]interface{}{}))
+
+	assert.Empty(t, ids)
+	assert.Len(t, errs, 1)
+	var syntaxErr *json.SyntaxError
+	assert.True(t, errors.As(errs[0], &syntaxErr))
+	//the chunk and its halves without retries
+	assert.Equal(t, 3, dp.readsCount)
+}
+
+func TestNoRetryForNonRetryableError(t *testing.T) {
+	dp := &flakyProviderMock{
+		pagedProviderMock: pagedProviderMock{total: 5},
+		failuresCount:     1,
+		err:               NewNonRetryableError(errors.New("some validation error")),
+	}
+
+	lister := NewLister(ListingSettings{}, dp, NullSleeper)
+	ids, errs := collectIDs(t, lister.Get(context.Background(), map[string]interface{}{}))
+
+	assert.Empty(t, ids)
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "failed to read listing pages 1 (recordsOnPage 100): some validation error")
+	assert.Equal(t, 1, dp.readsCount)
+}
+
+func TestSplitDepthIsLimited(t *testing.T) {
+	dp := &failingProviderMock{
+		pagedProviderMock: pagedProviderMock{total: 3200},
+		readErr: func(bulkFilters []map[string]interface{}) error {
+			//each split gives a different error, so only the depth limit stops the splitting
+			return NewErplyError("Error", "some error", ApiError(2000+len(bulkFilters)))
+		},
+	}
+
+	lister := NewLister(ListingSettings{MaxItemsPerRequest: 3200, MaxFetchersCount: 1}, dp, NullSleeper)
+	ids, errs := collectIDs(t, lister.Get(context.Background(), map[string]interface{}{}))
+
+	assert.Empty(t, ids)
+	assert.Len(t, errs, 16)
+	for _, err := range errs {
+		pagesErr, ok := AsListingPagesError(err)
+		assert.True(t, ok)
+		assert.Len(t, pagesErr.Cursors, 2)
+	}
+	//the chunk of 32 pages is split 4 times
+	assert.Equal(t, 1+2+4+8+16, dp.readsCount)
+}
+
+func TestSplitChunkIsolatesFailingPage(t *testing.T) {
+	dp := &pagedProviderMock{total: 1000, failPages: map[int]bool{3: true}}
+
+	lister := NewLister(ListingSettings{MaxItemsPerRequest: 1000, MaxFetchersCount: 1}, dp, NullSleeper)
+	ids, errs := collectIDs(t, lister.Get(context.Background(), map[string]interface{}{}))
+
+	//all items are given once except the ones of the failing page
+	assert.Len(t, ids, 900)
+	assert.Equal(t, 200, ids[199])
+	assert.Equal(t, 301, ids[200])
+	assert.Equal(t, 1000, ids[899])
+
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "failed to read listing pages 3 (recordsOnPage 100): some page error")
+	pagesErr, ok := AsListingPagesError(errs[0])
+	assert.True(t, ok)
+	assert.Equal(t, []Cursor{{Limit: 100, Offset: 3}}, pagesErr.Cursors)
+}
+
+func TestSplitChunkByBulkError(t *testing.T) {
+	dp := &bulkErrorProviderMock{pagedProviderMock: pagedProviderMock{total: 500, failPages: map[int]bool{2: true, 4: true}}}
+
+	lister := NewLister(ListingSettings{MaxItemsPerRequest: 500, MaxFetchersCount: 1}, dp, NullSleeper)
+	ids, errs := collectIDs(t, lister.Get(context.Background(), map[string]interface{}{}))
+
+	assert.Len(t, ids, 300)
+	assert.Len(t, errs, 2)
+	for i, page := range []int{2, 4} {
+		pagesErr, ok := AsListingPagesError(errs[i])
+		assert.True(t, ok)
+		assert.Equal(t, []Cursor{{Limit: 100, Offset: page}}, pagesErr.Cursors)
+		_, ok = AsBulkError(errs[i])
+		assert.True(t, ok)
+	}
+
+	//the error code is not retryable, so the succeeded pages and each failed page are requested once
+	assert.Equal(t, [][]int{{1, 2, 3, 4, 5}, {1, 3, 5}, {2}, {4}}, dp.bulkCalls)
+}
+
+func TestSplitStopsOnErrorOfAllPages(t *testing.T) {
+	dp := &flakyProviderMock{
+		pagedProviderMock: pagedProviderMock{total: 1000},
+		failuresCount:     1000,
+		err:               errors.New("some filter error"),
+	}
+
+	lister := NewLister(ListingSettings{MaxItemsPerRequest: 1000, RetryPolicy: &RetryPolicy{MaxAttempts: 1}}, dp, NullSleeper)
+	ids, errs := collectIDs(t, lister.Get(context.Background(), map[string]interface{}{}))
+
+	assert.Empty(t, ids)
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "failed to read listing pages 1-10 (recordsOnPage 100): some filter error")
+	//the chunk and its halves
+	assert.Equal(t, 3, dp.readsCount)
+}
+
+func TestFormatCursorRanges(t *testing.T) {
+	assert.Equal(t, "none", FormatCursorRanges(nil))
+	assert.Equal(t, "7 (recordsOnPage 50)", FormatCursorRanges([]Cursor{{Limit: 50, Offset: 7}}))
+	assert.Equal(t, "1-3, 5, 8-9 (recordsOnPage 100)", FormatCursorRanges([]Cursor{
+		{Limit: 100, Offset: 8},
+		{Limit: 100, Offset: 1},
+		{Limit: 100, Offset: 2},
+		{Limit: 100, Offset: 3},
+		{Limit: 100, Offset: 5},
+		{Limit: 100, Offset: 9},
+	}))
+}
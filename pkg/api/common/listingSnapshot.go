@@ -0,0 +1,243 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+//SnapshotKey describes the stable key by which the snapshot listing orders the records and ranges the pages
+type SnapshotKey struct {
+	OrderBy    string                          //value of the orderBy filter which sorts the records by the key
+	FromFilter string                          //filter which gives the records with the key greater or equal to its value
+	Value      func(payload interface{}) int64 //the key of a record
+}
+
+//LastModifiedSnapshotKey ranges the records by lastModified with the changedSince filter,
+//orderBy is the value of the orderBy filter of the API method which sorts the records by lastModified
+func LastModifiedSnapshotKey(orderBy string) SnapshotKey {
+	return SnapshotKey{
+		OrderBy:    orderBy,
+		FromFilter: ChangedSinceFilter,
+		Value:      GetLastModified,
+	}
+}
+
+//IDSnapshotKey ranges the records by the numeric idField of the payload, e.g. ProductID,
+//fromFilter is the filter of the API method which gives the records with the id greater or equal to its value
+func IDSnapshotKey(orderBy, fromFilter, idField string) SnapshotKey {
+	return SnapshotKey{
+		OrderBy:    orderBy,
+		FromFilter: fromFilter,
+		Value: func(payload interface{}) int64 {
+			id, _ := getIntField(payload, idField)
+			return id
+		},
+	}
+}
+
+//SnapshotSettings configures Lister.GetSnapshot
+type SnapshotSettings struct {
+	Key      SnapshotKey
+	IDField  string //numeric field of the payload which identifies the entity, e.g. ProductID or CustomerID
+	PageSize int    //records per request, MaxCountPerBulkRequestItem by default
+}
+
+//SnapshotReport describes the drift of the records during the snapshot listing, it's complete when the items stream is closed
+type SnapshotReport struct {
+	InitialCount    int //recordsTotal when the listing started
+	ListedCount     int //distinct records given to the consumer
+	DuplicatesCount int //records which were received again e.g. because they were changed during the listing, they were skipped
+	ChangedCount    int //listed records which were added or changed after the listing started according to their LastModified field
+}
+
+//Drift gives the difference between the count of the listed records and the initial count,
+//it's positive if more records were added than deleted during the listing
+func (sr SnapshotReport) Drift() int {
+	return sr.ListedCount - sr.InitialCount
+}
+
+//GetSnapshot lists the records ordered by the stable key reading the pages by the ranges of the key instead of pageNo,
+//so the records added or deleted during a long listing don't shift the pages. Every entity is given once by its IDField.
+//The pages are read one by one, MaxFetchersCount and OrderedDelivery are not used, the retries and the throttling are.
+//The filters are not changed.
+func (p *Lister) GetSnapshot(ctx context.Context, filters map[string]interface{}, settings SnapshotSettings) (ItemsStream, *SnapshotReport) {
+	report := &SnapshotReport{}
+	if settings.Key.Value == nil || settings.Key.FromFilter == "" || settings.IDField == "" {
+		return newErrorStream(errors.New("snapshot listing needs the key and the id field"), 0), report
+	}
+	if settings.PageSize <= 0 || settings.PageSize > MaxCountPerBulkRequestItem {
+		settings.PageSize = MaxCountPerBulkRequestItem
+	}
+
+	snapshotFilters := withoutPaging(filters)
+	from := int64(0)
+	if rawFrom, ok := snapshotFilters[settings.Key.FromFilter]; ok {
+		from, _ = strconv.ParseInt(fmt.Sprint(rawFrom), 10, 64)
+	}
+
+	countFilters := make(map[string]interface{}, len(snapshotFilters)+2)
+	for k, v := range snapshotFilters {
+		countFilters[k] = v
+	}
+	countFilters["recordsOnPage"] = 1
+	countFilters["pageNo"] = 1
+	startTime := time.Now()
+
+	totalCount, err := p.count(ctx, countFilters)
+	if err != nil {
+		return newErrorStream(err, totalCount), report
+	}
+	report.InitialCount = totalCount
+
+	outputChan := make(ItemsStream, p.listingSettings.StreamBufferLength)
+	go func() {
+		defer close(outputChan)
+
+		reader := snapshotReader{
+			settings:  settings,
+			report:    report,
+			startTime: startTime.Unix(),
+			seen:      map[int64]bool{},
+			boundary:  map[int64]bool{},
+		}
+		pageNo := 1
+		for {
+			pageFilters := make(map[string]interface{}, len(snapshotFilters)+5)
+			for k, v := range snapshotFilters {
+				pageFilters[k] = v
+			}
+			if settings.Key.OrderBy != "" {
+				pageFilters["orderBy"] = settings.Key.OrderBy
+				pageFilters["orderByDir"] = "asc"
+			}
+			pageFilters[settings.Key.FromFilter] = from
+			pageFilters["recordsOnPage"] = settings.PageSize
+			pageFilters["pageNo"] = pageNo
+
+			items, err := p.readWithRetry(ctx, []map[string]interface{}{pageFilters}, totalCount)
+			if err != nil {
+				err = fmt.Errorf("failed to read records from %s %d page %d: %w", settings.Key.FromFilter, from, pageNo, err)
+				items = []Item{{Err: err, TotalCount: totalCount}}
+			}
+
+			maxKey := from
+			for _, item := range items {
+				if item.Err == nil {
+					if key := settings.Key.Value(item.Payload); key > maxKey {
+						maxKey = key
+					}
+					isNew, idErr := reader.isNew(item.Payload, from)
+					if idErr != nil {
+						item = Item{Err: idErr, TotalCount: totalCount}
+					} else if !isNew {
+						continue
+					}
+				}
+
+				select {
+				case outputChan <- item:
+				case <-ctx.Done():
+					return
+				}
+				if item.Err != nil {
+					return
+				}
+			}
+
+			if err != nil || len(items) < settings.PageSize {
+				return
+			}
+
+			//all records of the page have the same key, so the next ones with this key are read by pageNo
+			if maxKey == from {
+				pageNo++
+				continue
+			}
+			reader.nextRange(items, maxKey)
+			from = maxKey
+			pageNo = 1
+		}
+	}()
+
+	return outputChan, report
+}
+
+//snapshotReader dedupes the records of the snapshot listing, the records with the key at the lower bound of the range
+//are read again with the next range, they are skipped without counting as duplicates
+type snapshotReader struct {
+	settings  SnapshotSettings
+	report    *SnapshotReport
+	startTime int64
+	seen      map[int64]bool
+	boundary  map[int64]bool //ids of the records with the key equal to the lower bound of the current range
+}
+
+func (sr *snapshotReader) isNew(payload interface{}, from int64) (bool, error) {
+	id, ok := getIntField(payload, sr.settings.IDField)
+	if !ok {
+		return false, fmt.Errorf("payload %T has no numeric field %s to dedupe the snapshot listing", payload, sr.settings.IDField)
+	}
+	if sr.seen[id] {
+		if !sr.boundary[id] {
+			sr.report.DuplicatesCount++
+		}
+		return false, nil
+	}
+
+	sr.seen[id] = true
+	if sr.settings.Key.Value(payload) == from {
+		sr.boundary[id] = true
+	}
+	sr.report.ListedCount++
+	if GetLastModified(payload) > sr.startTime {
+		sr.report.ChangedCount++
+	}
+
+	return true, nil
+}
+
+//nextRange keeps the ids of the records which are at the lower bound of the next range
+func (sr *snapshotReader) nextRange(items []Item, from int64) {
+	sr.boundary = map[int64]bool{}
+	for _, item := range items {
+		if sr.settings.Key.Value(item.Payload) == from {
+			id, _ := getIntField(item.Payload, sr.settings.IDField)
+			sr.boundary[id] = true
+		}
+	}
+}
+
+//getIntField gives the value of a numeric or numeric string field of a struct
+func getIntField(item interface{}, name string) (int64, bool) {
+	val := reflect.ValueOf(item)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return 0, false
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return 0, false
+	}
+
+	field := val.FieldByName(name)
+	if !field.IsValid() {
+		return 0, false
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(field.Uint()), true
+	case reflect.String:
+		value, err := strconv.ParseInt(field.String(), 10, 64)
+		return value, err == nil
+	}
+
+	return 0, false
+}
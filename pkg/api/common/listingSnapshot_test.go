@@ -0,0 +1,244 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type snapshotRecordMock struct {
+	ProductID    int
+	LastModified int64
+}
+
+//keysetProviderMock pages the records ordered by lastModified or productID from the value of changedSince or productIDFrom,
+//beforeRead changes the records between the requests like the other users of the account do
+type keysetProviderMock struct {
+	lock       sync.Mutex
+	records    []snapshotRecordMock
+	readsCount int
+	beforeRead func(readsCount int, records []snapshotRecordMock) []snapshotRecordMock
+	readErr    error
+}
+
+func (kpm *keysetProviderMock) Count(ctx context.Context, filters map[string]interface{}) (int, error) {
+	kpm.lock.Lock()
+	defer kpm.lock.Unlock()
+
+	return len(kpm.records), nil
+}
+
+func (kpm *keysetProviderMock) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	kpm.lock.Lock()
+	defer kpm.lock.Unlock()
+
+	kpm.readsCount++
+	if kpm.beforeRead != nil {
+		kpm.records = kpm.beforeRead(kpm.readsCount, kpm.records)
+	}
+	if kpm.readErr != nil {
+		return kpm.readErr
+	}
+
+	for _, filters := range bulkFilters {
+		key := func(rec snapshotRecordMock) int64 {
+			return rec.LastModified
+		}
+		fromFilter := "changedSince"
+		if filters["orderBy"] == "productID" {
+			key = func(rec snapshotRecordMock) int64 {
+				return int64(rec.ProductID)
+			}
+			fromFilter = "productIDFrom"
+		}
+		from, _ := strconv.ParseInt(fmt.Sprint(filters[fromFilter]), 10, 64)
+
+		matched := make([]snapshotRecordMock, 0)
+		for _, rec := range kpm.records {
+			if key(rec) >= from {
+				matched = append(matched, rec)
+			}
+		}
+		sort.Slice(matched, func(i, j int) bool {
+			if key(matched[i]) != key(matched[j]) {
+				return key(matched[i]) < key(matched[j])
+			}
+			return matched[i].ProductID < matched[j].ProductID
+		})
+
+		recordsOnPage := filters["recordsOnPage"].(int)
+		pageNo := filters["pageNo"].(int)
+		for i := (pageNo - 1) * recordsOnPage; i < pageNo*recordsOnPage && i < len(matched); i++ {
+			callback(matched[i])
+		}
+	}
+
+	return nil
+}
+
+func newSnapshotRecords(count int, lastModified func(id int) int64) []snapshotRecordMock {
+	records := make([]snapshotRecordMock, 0, count)
+	for id := 1; id <= count; id++ {
+		records = append(records, snapshotRecordMock{ProductID: id, LastModified: lastModified(id)})
+	}
+
+	return records
+}
+
+func collectSnapshotIDs(t *testing.T, itemsStream ItemsStream) (ids []int, errs []error) {
+	for item := range itemsStream {
+		if item.Err != nil {
+			errs = append(errs, item.Err)
+			continue
+		}
+		ids = append(ids, item.Payload.(snapshotRecordMock).ProductID)
+	}
+
+	return ids, errs
+}
+
+var lastModifiedSnapshotSettings = SnapshotSettings{
+	Key:      LastModifiedSnapshotKey("lastModified"),
+	IDField:  "ProductID",
+	PageSize: 100,
+}
+
+func TestSnapshotListingWithChangesDuringListing(t *testing.T) {
+	now := time.Now().Unix()
+	dp := &keysetProviderMock{
+		records: newSnapshotRecords(250, func(id int) int64 {
+			return 1000 + int64(id)
+		}),
+		beforeRead: func(readsCount int, records []snapshotRecordMock) []snapshotRecordMock {
+			if readsCount != 2 {
+				return records
+			}
+			//the records of the first page are deleted, so pageNo would skip the next ones
+			res := make([]snapshotRecordMock, 0, len(records))
+			for _, rec := range records {
+				if rec.ProductID <= 10 {
+					continue
+				}
+				//the listed record is changed and comes again at the end
+				if rec.ProductID == 50 {
+					rec.LastModified = now + 100
+				}
+				res = append(res, rec)
+			}
+			for id := 251; id <= 255; id++ {
+				res = append(res, snapshotRecordMock{ProductID: id, LastModified: now + 100})
+			}
+			return res
+		},
+	}
+
+	itemsStream, report := NewLister(ListingSettings{}, dp, NullSleeper).GetSnapshot(context.Background(), map[string]interface{}{}, lastModifiedSnapshotSettings)
+	ids, errs := collectSnapshotIDs(t, itemsStream)
+
+	assert.Empty(t, errs)
+	expectedIDs := make([]int, 0, 255)
+	for id := 1; id <= 255; id++ {
+		expectedIDs = append(expectedIDs, id)
+	}
+	assert.Equal(t, expectedIDs, ids)
+	assert.Equal(t, SnapshotReport{
+		InitialCount:    250,
+		ListedCount:     255,
+		DuplicatesCount: 1,
+		ChangedCount:    5,
+	}, *report)
+	assert.Equal(t, 5, report.Drift())
+}
+
+func TestSnapshotListingOfSameKeys(t *testing.T) {
+	dp := &keysetProviderMock{
+		records: newSnapshotRecords(250, func(id int) int64 {
+			return 1000 + int64(id/120)
+		}),
+	}
+
+	itemsStream, report := NewLister(ListingSettings{}, dp, NullSleeper).GetSnapshot(
+		context.Background(),
+		map[string]interface{}{"changedSince": 1000},
+		lastModifiedSnapshotSettings,
+	)
+	ids, errs := collectSnapshotIDs(t, itemsStream)
+
+	assert.Empty(t, errs)
+	assert.Len(t, ids, 250)
+	sort.Ints(ids)
+	assert.Equal(t, 250, ids[249])
+	assert.Equal(t, 0, report.DuplicatesCount)
+	assert.Equal(t, 0, report.Drift())
+}
+
+func TestSnapshotListingByID(t *testing.T) {
+	dp := &keysetProviderMock{
+		records: newSnapshotRecords(230, func(id int) int64 {
+			return 0
+		}),
+		beforeRead: func(readsCount int, records []snapshotRecordMock) []snapshotRecordMock {
+			if readsCount != 2 {
+				return records
+			}
+			return records[20:]
+		},
+	}
+
+	settings := SnapshotSettings{
+		Key:      IDSnapshotKey("productID", "productIDFrom", "ProductID"),
+		IDField:  "ProductID",
+		PageSize: 100,
+	}
+	itemsStream, report := NewTypedLister[snapshotRecordMock](ListingSettings{}, dp, NullSleeper).GetSnapshot(
+		context.Background(),
+		map[string]interface{}{},
+		settings,
+	)
+
+	ids := make([]int, 0, 230)
+	for item := range itemsStream {
+		assert.NoError(t, item.Err)
+		ids = append(ids, item.Payload.ProductID)
+	}
+
+	assert.Len(t, ids, 230)
+	assert.Equal(t, 230, ids[229])
+	assert.Equal(t, SnapshotReport{InitialCount: 230, ListedCount: 230}, *report)
+}
+
+func TestSnapshotListingErrors(t *testing.T) {
+	dp := &keysetProviderMock{
+		records: newSnapshotRecords(10, func(id int) int64 {
+			return 1000
+		}),
+		readErr: errors.New("some read error"),
+	}
+	lister := NewLister(ListingSettings{RetryPolicy: &RetryPolicy{MaxAttempts: 1}}, dp, NullSleeper)
+
+	itemsStream, _ := lister.GetSnapshot(context.Background(), map[string]interface{}{}, lastModifiedSnapshotSettings)
+	ids, errs := collectSnapshotIDs(t, itemsStream)
+	assert.Empty(t, ids)
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "failed to read records from changedSince 0 page 1: some read error")
+
+	dp.readErr = nil
+	settings := lastModifiedSnapshotSettings
+	settings.IDField = "CustomerID"
+	itemsStream, _ = lister.GetSnapshot(context.Background(), map[string]interface{}{}, settings)
+	ids, errs = collectSnapshotIDs(t, itemsStream)
+	assert.Empty(t, ids)
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "payload common.snapshotRecordMock has no numeric field CustomerID to dedupe the snapshot listing")
+
+	itemsStream, _ = lister.GetSnapshot(context.Background(), map[string]interface{}{}, SnapshotSettings{})
+	ids, errs = collectSnapshotIDs(t, itemsStream)
+	assert.Empty(t, ids)
+	assert.Len(t, errs, 1)
+}
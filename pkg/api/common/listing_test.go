@@ -309,7 +309,12 @@ func TestReadItemsError(t *testing.T) {
 	actualProds := collectProdsFromChannel(prodsChan)
 
 	assert.Len(t, actualProds, 1)
-	assert.EqualError(t, actualProds[0].Err, "some read items error")
+	assert.EqualError(t, actualProds[0].Err, "failed to read listing pages 1 (recordsOnPage 100): some read items error")
+
+	pagesErr, ok := AsListingPagesError(actualProds[0].Err)
+	assert.True(t, ok)
+	assert.Equal(t, []Cursor{{Limit: 100, Offset: 1}}, pagesErr.Cursors)
+	assert.Len(t, dp.ReadBulkFilters, DefaultRetryAttempts)
 }
 
 func TestReadingGroupedSuccess(t *testing.T) {
@@ -0,0 +1,337 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//BulkMethodName is the method of a bulk call in metrics if its sub-requests call different API methods
+const BulkMethodName = "bulk"
+
+//DefaultLatencyBuckets are the upper bounds of the latency histogram in seconds
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+//RequestMetrics describes one API call, retries and session renewals of the call are included into its duration
+type RequestMetrics struct {
+	Method               string        //the API method, for bulk calls it's the method of the sub-requests or BulkMethodName if they differ
+	ClientCode           string        //client code of the account
+	Duration             time.Duration //latency of the call
+	HTTPStatus           int           //0 if no HTTP response was received
+	ErrorCode            ApiError      //the error code of the response status or of the ErplyError of a failed call
+	Failed               bool          //true if no valid response was received or the response status is not ok
+	IsBulk               bool          //true for bulk calls
+	BulkSubRequestsCount int           //amount of the sub-requests of a bulk call
+	GenerationTime       float64       //Status.GenerationTime, the time in seconds which the server spent to generate the response
+	RecordsInResponse    int           //Status.RecordsInResponse, for bulk calls it's the sum of all sub-requests
+}
+
+//MetricsCollector receives the metrics of every API call of the client, it should not block
+type MetricsCollector interface {
+	Collect(m RequestMetrics)
+}
+
+//MetricsCollectorFunc allows to use a function as MetricsCollector
+type MetricsCollectorFunc func(m RequestMetrics)
+
+//Collect implements MetricsCollector interface
+func (mcf MetricsCollectorFunc) Collect(m RequestMetrics) {
+	mcf(m)
+}
+
+type metricsCollectorKey struct{}
+
+//WithMetricsCollector gives a context which makes the client report the metrics of the calls made with it
+//also to the collector, e.g. the adaptive Lister observes its bulk requests this way
+func WithMetricsCollector(ctx context.Context, collector MetricsCollector) context.Context {
+	return context.WithValue(ctx, metricsCollectorKey{}, collector)
+}
+
+//MetricsCollectorFromContext gives the collector set with WithMetricsCollector or nil
+func MetricsCollectorFromContext(ctx context.Context) MetricsCollector {
+	collector, _ := ctx.Value(metricsCollectorKey{}).(MetricsCollector)
+	return collector
+}
+
+//MetricsKey identifies aggregated metrics
+type MetricsKey struct {
+	Method     string
+	ClientCode string
+}
+
+//MethodMetrics are the aggregated metrics of one API method and client code
+type MethodMetrics struct {
+	MetricsKey
+	RequestsCount          int
+	FailedRequestsCount    int
+	BulkRequestsCount      int
+	BulkSubRequestsCount   int
+	TotalDuration          time.Duration
+	MaxDuration            time.Duration
+	LatencyBucketsCounts   []int //cumulative counts of calls which were faster or equal to the corresponding bucket
+	HTTPStatusesCount      map[int]int
+	ErrorCodesCount        map[ApiError]int
+	TotalGenerationTime    float64
+	TotalRecordsInResponse int
+}
+
+//AverageDuration gives mean latency of the calls
+func (mm MethodMetrics) AverageDuration() time.Duration {
+	if mm.RequestsCount == 0 {
+		return 0
+	}
+
+	return mm.TotalDuration / time.Duration(mm.RequestsCount)
+}
+
+func (mm MethodMetrics) copy() MethodMetrics {
+	res := mm
+	res.LatencyBucketsCounts = append([]int{}, mm.LatencyBucketsCounts...)
+	res.HTTPStatusesCount = make(map[int]int, len(mm.HTTPStatusesCount))
+	for k, v := range mm.HTTPStatusesCount {
+		res.HTTPStatusesCount[k] = v
+	}
+	res.ErrorCodesCount = make(map[ApiError]int, len(mm.ErrorCodesCount))
+	for k, v := range mm.ErrorCodesCount {
+		res.ErrorCodesCount[k] = v
+	}
+
+	return res
+}
+
+//InMemoryMetricsCollector aggregates metrics per API method and client code in memory
+type InMemoryMetricsCollector struct {
+	latencyBuckets []float64
+	metrics        map[MetricsKey]*MethodMetrics
+	lock           sync.Mutex
+}
+
+//NewInMemoryMetricsCollector creates InMemoryMetricsCollector, empty latencyBuckets means DefaultLatencyBuckets
+func NewInMemoryMetricsCollector(latencyBuckets ...float64) *InMemoryMetricsCollector {
+	if len(latencyBuckets) == 0 {
+		latencyBuckets = DefaultLatencyBuckets
+	}
+
+	buckets := append([]float64{}, latencyBuckets...)
+	sort.Float64s(buckets)
+
+	return &InMemoryMetricsCollector{
+		latencyBuckets: buckets,
+		metrics:        map[MetricsKey]*MethodMetrics{},
+		lock:           sync.Mutex{},
+	}
+}
+
+//Collect implements MetricsCollector interface
+func (mc *InMemoryMetricsCollector) Collect(m RequestMetrics) {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+
+	key := MetricsKey{Method: m.Method, ClientCode: m.ClientCode}
+	mm, ok := mc.metrics[key]
+	if !ok {
+		mm = &MethodMetrics{
+			MetricsKey:           key,
+			LatencyBucketsCounts: make([]int, len(mc.latencyBuckets)),
+			HTTPStatusesCount:    map[int]int{},
+			ErrorCodesCount:      map[ApiError]int{},
+		}
+		mc.metrics[key] = mm
+	}
+
+	mm.RequestsCount++
+	if m.Failed {
+		mm.FailedRequestsCount++
+	}
+	if m.IsBulk {
+		mm.BulkRequestsCount++
+		mm.BulkSubRequestsCount += m.BulkSubRequestsCount
+	}
+
+	mm.TotalDuration += m.Duration
+	if m.Duration > mm.MaxDuration {
+		mm.MaxDuration = m.Duration
+	}
+	for i, bucket := range mc.latencyBuckets {
+		if m.Duration.Seconds() <= bucket {
+			mm.LatencyBucketsCounts[i]++
+		}
+	}
+
+	mm.HTTPStatusesCount[m.HTTPStatus]++
+	if m.ErrorCode != 0 {
+		mm.ErrorCodesCount[m.ErrorCode]++
+	}
+
+	mm.TotalGenerationTime += m.GenerationTime
+	mm.TotalRecordsInResponse += m.RecordsInResponse
+}
+
+//Get gives a copy of the metrics of the method and client code
+func (mc *InMemoryMetricsCollector) Get(method, clientCode string) (MethodMetrics, bool) {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+
+	mm, ok := mc.metrics[MetricsKey{Method: method, ClientCode: clientCode}]
+	if !ok {
+		return MethodMetrics{}, false
+	}
+
+	return mm.copy(), true
+}
+
+//Snapshot gives a copy of all metrics sorted by method and client code
+func (mc *InMemoryMetricsCollector) Snapshot() []MethodMetrics {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+
+	res := make([]MethodMetrics, 0, len(mc.metrics))
+	for _, mm := range mc.metrics {
+		res = append(res, mm.copy())
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Method != res[j].Method {
+			return res[i].Method < res[j].Method
+		}
+		return res[i].ClientCode < res[j].ClientCode
+	})
+
+	return res
+}
+
+//Reset removes all collected metrics
+func (mc *InMemoryMetricsCollector) Reset() {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+
+	mc.metrics = map[MetricsKey]*MethodMetrics{}
+}
+
+//WritePrometheus writes the metrics in the Prometheus text exposition format
+func (mc *InMemoryMetricsCollector) WritePrometheus(w io.Writer) error {
+	snapshot := mc.Snapshot()
+
+	pw := &prometheusWriter{w: w}
+
+	pw.header("erply_api_requests_total", "counter", "Total number of Erply API calls.")
+	for _, mm := range snapshot {
+		pw.sample("erply_api_requests_total", mm.labels(), float64(mm.RequestsCount))
+	}
+
+	pw.header("erply_api_failed_requests_total", "counter", "Number of Erply API calls which failed or got a not ok status.")
+	for _, mm := range snapshot {
+		pw.sample("erply_api_failed_requests_total", mm.labels(), float64(mm.FailedRequestsCount))
+	}
+
+	pw.header("erply_api_responses_total", "counter", "Number of Erply API responses by HTTP status, 0 means no response.")
+	for _, mm := range snapshot {
+		for _, status := range sortedIntKeys(mm.HTTPStatusesCount) {
+			pw.sample("erply_api_responses_total", mm.labels("http_status", fmt.Sprint(status)), float64(mm.HTTPStatusesCount[status]))
+		}
+	}
+
+	pw.header("erply_api_errors_total", "counter", "Number of Erply API errors by error code.")
+	for _, mm := range snapshot {
+		codes := make([]int, 0, len(mm.ErrorCodesCount))
+		for code := range mm.ErrorCodesCount {
+			codes = append(codes, int(code))
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			pw.sample("erply_api_errors_total", mm.labels("error_code", fmt.Sprint(code)), float64(mm.ErrorCodesCount[ApiError(code)]))
+		}
+	}
+
+	pw.header("erply_api_bulk_subrequests_total", "counter", "Number of sub-requests sent in Erply API bulk calls.")
+	for _, mm := range snapshot {
+		pw.sample("erply_api_bulk_subrequests_total", mm.labels(), float64(mm.BulkSubRequestsCount))
+	}
+
+	pw.header("erply_api_request_duration_seconds", "histogram", "Latency of Erply API calls including retries.")
+	for _, mm := range snapshot {
+		for i, bucket := range mc.latencyBuckets {
+			pw.sample("erply_api_request_duration_seconds_bucket", mm.labels("le", formatFloat(bucket)), float64(mm.LatencyBucketsCounts[i]))
+		}
+		pw.sample("erply_api_request_duration_seconds_bucket", mm.labels("le", "+Inf"), float64(mm.RequestsCount))
+		pw.sample("erply_api_request_duration_seconds_sum", mm.labels(), mm.TotalDuration.Seconds())
+		pw.sample("erply_api_request_duration_seconds_count", mm.labels(), float64(mm.RequestsCount))
+	}
+
+	pw.header("erply_api_generation_time_seconds_total", "counter", "Time which the Erply API server spent to generate the responses.")
+	for _, mm := range snapshot {
+		pw.sample("erply_api_generation_time_seconds_total", mm.labels(), mm.TotalGenerationTime)
+	}
+
+	pw.header("erply_api_records_in_response_total", "counter", "Number of records received from the Erply API.")
+	for _, mm := range snapshot {
+		pw.sample("erply_api_records_in_response_total", mm.labels(), float64(mm.TotalRecordsInResponse))
+	}
+
+	return pw.err
+}
+
+//ServeHTTP exposes the metrics in the Prometheus text format, so the collector can be registered e.g. as /metrics handler
+func (mc *InMemoryMetricsCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := mc.WritePrometheus(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (mm MethodMetrics) labels(extraLabels ...string) string {
+	pairs := []string{
+		fmt.Sprintf(`method="%s"`, escapeLabelValue(mm.Method)),
+		fmt.Sprintf(`client_code="%s"`, escapeLabelValue(mm.ClientCode)),
+	}
+	for i := 0; i+1 < len(extraLabels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extraLabels[i], escapeLabelValue(extraLabels[i+1])))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+type prometheusWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (pw *prometheusWriter) header(name, metricType, help string) {
+	pw.write(fmt.Sprintf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType))
+}
+
+func (pw *prometheusWriter) sample(name, labels string, value float64) {
+	pw.write(fmt.Sprintf("%s%s %s\n", name, labels, formatFloat(value)))
+}
+
+func (pw *prometheusWriter) write(text string) {
+	if pw.err != nil {
+		return
+	}
+	_, pw.err = io.WriteString(pw.w, text)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return fmt.Sprintf("%g", value)
+}
+
+func sortedIntKeys(m map[int]int) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	return keys
+}
@@ -0,0 +1,121 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func getTestMetricsCollector() *InMemoryMetricsCollector {
+	mc := NewInMemoryMetricsCollector(0.1, 1)
+
+	mc.Collect(RequestMetrics{
+		Method:            "getProducts",
+		ClientCode:        "123",
+		Duration:          50 * time.Millisecond,
+		HTTPStatus:        http.StatusOK,
+		GenerationTime:    0.02,
+		RecordsInResponse: 20,
+	})
+	mc.Collect(RequestMetrics{
+		Method:               "getProducts",
+		ClientCode:           "123",
+		Duration:             500 * time.Millisecond,
+		HTTPStatus:           http.StatusOK,
+		IsBulk:               true,
+		BulkSubRequestsCount: 3,
+		GenerationTime:       0.2,
+		RecordsInResponse:    300,
+	})
+	mc.Collect(RequestMetrics{
+		Method:     "getProducts",
+		ClientCode: "123",
+		Duration:   2 * time.Second,
+		HTTPStatus: http.StatusOK,
+		ErrorCode:  HourlyRequestQuota,
+		Failed:     true,
+	})
+	mc.Collect(RequestMetrics{
+		Method:     "getCustomers",
+		ClientCode: "123",
+		Duration:   time.Second,
+		Failed:     true,
+	})
+
+	return mc
+}
+
+func TestInMemoryMetricsCollector(t *testing.T) {
+	mc := getTestMetricsCollector()
+
+	mm, ok := mc.Get("getProducts", "123")
+	assert.True(t, ok)
+	assert.Equal(t, 3, mm.RequestsCount)
+	assert.Equal(t, 1, mm.FailedRequestsCount)
+	assert.Equal(t, 1, mm.BulkRequestsCount)
+	assert.Equal(t, 3, mm.BulkSubRequestsCount)
+	assert.Equal(t, 2550*time.Millisecond, mm.TotalDuration)
+	assert.Equal(t, 850*time.Millisecond, mm.AverageDuration())
+	assert.Equal(t, 2*time.Second, mm.MaxDuration)
+	assert.Equal(t, []int{1, 2}, mm.LatencyBucketsCounts)
+	assert.Equal(t, map[int]int{http.StatusOK: 3}, mm.HTTPStatusesCount)
+	assert.Equal(t, map[ApiError]int{HourlyRequestQuota: 1}, mm.ErrorCodesCount)
+	assert.InDelta(t, 0.22, mm.TotalGenerationTime, 0.0001)
+	assert.Equal(t, 320, mm.TotalRecordsInResponse)
+
+	_, ok = mc.Get("getProducts", "456")
+	assert.False(t, ok)
+
+	snapshot := mc.Snapshot()
+	assert.Len(t, snapshot, 2)
+	assert.Equal(t, "getCustomers", snapshot[0].Method)
+	assert.Equal(t, "getProducts", snapshot[1].Method)
+
+	mc.Reset()
+	assert.Len(t, mc.Snapshot(), 0)
+}
+
+func TestPrometheusHandler(t *testing.T) {
+	mc := getTestMetricsCollector()
+
+	srv := httptest.NewServer(mc)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+
+	output := string(body)
+	expectedLines := []string{
+		"# TYPE erply_api_requests_total counter",
+		`erply_api_requests_total{method="getProducts",client_code="123"} 3`,
+		`erply_api_requests_total{method="getCustomers",client_code="123"} 1`,
+		`erply_api_failed_requests_total{method="getProducts",client_code="123"} 1`,
+		`erply_api_responses_total{method="getProducts",client_code="123",http_status="200"} 3`,
+		`erply_api_responses_total{method="getCustomers",client_code="123",http_status="0"} 1`,
+		`erply_api_errors_total{method="getProducts",client_code="123",error_code="1002"} 1`,
+		`erply_api_bulk_subrequests_total{method="getProducts",client_code="123"} 3`,
+		"# TYPE erply_api_request_duration_seconds histogram",
+		`erply_api_request_duration_seconds_bucket{method="getProducts",client_code="123",le="0.1"} 1`,
+		`erply_api_request_duration_seconds_bucket{method="getProducts",client_code="123",le="1"} 2`,
+		`erply_api_request_duration_seconds_bucket{method="getProducts",client_code="123",le="+Inf"} 3`,
+		`erply_api_request_duration_seconds_sum{method="getProducts",client_code="123"} 2.55`,
+		`erply_api_request_duration_seconds_count{method="getProducts",client_code="123"} 3`,
+		`erply_api_records_in_response_total{method="getProducts",client_code="123"} 320`,
+	}
+	for _, expectedLine := range expectedLines {
+		assert.Contains(t, output, expectedLine+"\n")
+	}
+}
@@ -0,0 +1,52 @@
+package common
+
+import (
+	"context"
+	"net/http"
+)
+
+//Request describes an API call which goes through the middleware chain
+type Request struct {
+	Method     string            //API method name, it's empty for bulk calls
+	Filters    map[string]string //request parameters, for bulk calls they are shared by all sub-requests
+	BulkInputs []BulkInput       //sub-requests of a bulk call
+	ClientCode string            //client code of the account, it's set by the client
+}
+
+//IsBulk tells if the request is a bulk API call
+func (r *Request) IsBulk() bool {
+	return r.BulkInputs != nil
+}
+
+//Response is the result of an API call which goes through the middleware chain
+type Response struct {
+	HTTPResponse *http.Response
+	Body         []byte  //raw response body
+	Status       *Status //decoded response status, nil if the body has no JSON status
+}
+
+//RequestHandler executes an API call
+type RequestHandler func(ctx context.Context, req *Request) (*Response, error)
+
+//Middleware wraps every API call of the client, it can inspect or change the request before calling the next handler,
+//inspect the response after it or return its own response without calling the next handler at all
+type Middleware interface {
+	Wrap(next RequestHandler) RequestHandler
+}
+
+//MiddlewareFunc allows using ordinary functions as Middleware
+type MiddlewareFunc func(next RequestHandler) RequestHandler
+
+//Wrap Middleware interface implementation
+func (mf MiddlewareFunc) Wrap(next RequestHandler) RequestHandler {
+	return mf(next)
+}
+
+//ChainMiddlewares wraps the handler into the middlewares, the first middleware is the outermost one
+func ChainMiddlewares(handler RequestHandler, middlewares ...Middleware) RequestHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i].Wrap(handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,180 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+//DefaultHourlyRequestsQuota is the default hourly requests limit of an Erply account
+const DefaultHourlyRequestsQuota = 1000
+
+//QuotaMode defines what happens to a request which would exceed the configured budget
+type QuotaMode int
+
+const (
+	//QuotaModeTrack only counts the requests
+	QuotaModeTrack QuotaMode = iota
+	//QuotaModeBlock waits until the budget has room for the request
+	QuotaModeBlock
+	//QuotaModeFailFast rejects the request with HourlyRequestQuota error without calling the API
+	QuotaModeFailFast
+)
+
+type quotaRecord struct {
+	timestamp     time.Time
+	requestsCount int
+}
+
+//QuotaAccountant counts the requests made per client code in a rolling hour and enforces the configured budget,
+//it can be shared between clients so all requests of one account are counted together
+type QuotaAccountant struct {
+	Limit                int           //the budget of requests in the window, it should be below the real API quota to leave room for other applications
+	Mode                 QuotaMode     //what to do with requests which exceed the budget
+	Window               time.Duration //length of the rolling window
+	CountBulkSubRequests bool          //count each sub-request of a bulk call separately instead of counting the whole call as one request
+	records              map[string][]quotaRecord
+	now                  func() time.Time
+	lock                 sync.Mutex
+}
+
+//NewQuotaAccountant creates QuotaAccountant with a rolling hour window
+func NewQuotaAccountant(limit int, mode QuotaMode) *QuotaAccountant {
+	return &QuotaAccountant{
+		Limit:   limit,
+		Mode:    mode,
+		Window:  time.Hour,
+		records: map[string][]quotaRecord{},
+		now:     time.Now,
+		lock:    sync.Mutex{},
+	}
+}
+
+//RequestsCount gives the amount of requests a call with the given count of bulk sub-requests is counted as,
+//bulkSubRequestsCount is 0 for a non bulk call
+func (qa *QuotaAccountant) RequestsCount(bulkSubRequestsCount int) int {
+	if qa.CountBulkSubRequests && bulkSubRequestsCount > 0 {
+		return bulkSubRequestsCount
+	}
+
+	return 1
+}
+
+//Acquire registers requests of the client code according to the mode, it blocks or fails if the budget is exceeded
+func (qa *QuotaAccountant) Acquire(ctx context.Context, clientCode string, requestsCount int) error {
+	for {
+		waitingTime, err := qa.tryAcquire(clientCode, requestsCount)
+		if err != nil || waitingTime <= 0 {
+			return err
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && qa.getNow().Add(waitingTime).After(deadline) {
+			return qa.newQuotaError(clientCode)
+		}
+
+		timer := time.NewTimer(waitingTime)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (qa *QuotaAccountant) tryAcquire(clientCode string, requestsCount int) (time.Duration, error) {
+	qa.lock.Lock()
+	defer qa.lock.Unlock()
+
+	now := qa.getNow()
+	records := qa.removeOutdatedRecords(clientCode, now)
+
+	if qa.Mode != QuotaModeTrack && qa.Limit > 0 && countRequests(records)+requestsCount > qa.Limit {
+		if qa.Mode == QuotaModeFailFast || requestsCount > qa.Limit {
+			return 0, qa.newQuotaError(clientCode)
+		}
+
+		return qa.getWaitingTime(records, requestsCount, now), nil
+	}
+
+	qa.records[clientCode] = append(records, quotaRecord{timestamp: now, requestsCount: requestsCount})
+	return 0, nil
+}
+
+//getWaitingTime calculates when enough of the oldest records leave the window to give room for the requests
+func (qa *QuotaAccountant) getWaitingTime(records []quotaRecord, requestsCount int, now time.Time) time.Duration {
+	toFree := countRequests(records) + requestsCount - qa.Limit
+	for _, record := range records {
+		toFree -= record.requestsCount
+		if toFree <= 0 {
+			return record.timestamp.Add(qa.Window).Sub(now)
+		}
+	}
+
+	return qa.Window
+}
+
+func (qa *QuotaAccountant) removeOutdatedRecords(clientCode string, now time.Time) []quotaRecord {
+	if qa.records == nil {
+		qa.records = map[string][]quotaRecord{}
+	}
+	if qa.Window <= 0 {
+		qa.Window = time.Hour
+	}
+
+	records := qa.records[clientCode]
+	windowStart := now.Add(-qa.Window)
+
+	i := 0
+	for i < len(records) && !records[i].timestamp.After(windowStart) {
+		i++
+	}
+	records = records[i:]
+	qa.records[clientCode] = records
+
+	return records
+}
+
+func (qa *QuotaAccountant) getNow() time.Time {
+	if qa.now == nil {
+		return time.Now()
+	}
+
+	return qa.now()
+}
+
+//Used gives the amount of requests of the client code counted in the current window
+func (qa *QuotaAccountant) Used(clientCode string) int {
+	qa.lock.Lock()
+	defer qa.lock.Unlock()
+
+	return countRequests(qa.removeOutdatedRecords(clientCode, qa.getNow()))
+}
+
+//Remaining gives the amount of requests the client code can still make in the current window
+func (qa *QuotaAccountant) Remaining(clientCode string) int {
+	remaining := qa.Limit - qa.Used(clientCode)
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining
+}
+
+func (qa *QuotaAccountant) newQuotaError(clientCode string) *ErplyError {
+	return NewErplyError(
+		HourlyRequestQuota.String(),
+		fmt.Sprintf("requests budget of %d per %v is exhausted for client code %s", qa.Limit, qa.Window, clientCode),
+		HourlyRequestQuota,
+	)
+}
+
+func countRequests(records []quotaRecord) int {
+	count := 0
+	for _, record := range records {
+		count += record.requestsCount
+	}
+
+	return count
+}
@@ -0,0 +1,96 @@
+package common
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type clockMock struct {
+	now time.Time
+}
+
+func (cm *clockMock) Now() time.Time {
+	return cm.now
+}
+
+func newQuotaAccountantWithClock(limit int, mode QuotaMode) (*QuotaAccountant, *clockMock) {
+	clock := &clockMock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+	qa := NewQuotaAccountant(limit, mode)
+	qa.now = clock.Now
+
+	return qa, clock
+}
+
+func TestQuotaAccountantRollingWindow(t *testing.T) {
+	qa, clock := newQuotaAccountantWithClock(10, QuotaModeTrack)
+
+	assert.NoError(t, qa.Acquire(context.Background(), "123", 3))
+	clock.now = clock.now.Add(30 * time.Minute)
+	assert.NoError(t, qa.Acquire(context.Background(), "123", 5))
+	assert.NoError(t, qa.Acquire(context.Background(), "456", 1))
+
+	assert.Equal(t, 8, qa.Used("123"))
+	assert.Equal(t, 2, qa.Remaining("123"))
+	assert.Equal(t, 9, qa.Remaining("456"))
+
+	clock.now = clock.now.Add(31 * time.Minute)
+	assert.Equal(t, 5, qa.Used("123"))
+
+	clock.now = clock.now.Add(30 * time.Minute)
+	assert.Equal(t, 0, qa.Used("123"))
+	assert.Equal(t, 10, qa.Remaining("123"))
+}
+
+func TestQuotaAccountantTrackModeExceedsLimit(t *testing.T) {
+	qa, _ := newQuotaAccountantWithClock(2, QuotaModeTrack)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, qa.Acquire(context.Background(), "123", 1))
+	}
+	assert.Equal(t, 3, qa.Used("123"))
+	assert.Equal(t, 0, qa.Remaining("123"))
+}
+
+func TestQuotaAccountantFailFast(t *testing.T) {
+	qa, _ := newQuotaAccountantWithClock(2, QuotaModeFailFast)
+
+	assert.NoError(t, qa.Acquire(context.Background(), "123", 2))
+
+	err := qa.Acquire(context.Background(), "123", 1)
+	assert.Error(t, err)
+	erplyErr, ok := err.(*ErplyError)
+	assert.True(t, ok)
+	if ok {
+		assert.Equal(t, HourlyRequestQuota, erplyErr.Code)
+	}
+	assert.Equal(t, 2, qa.Used("123"))
+}
+
+func TestQuotaAccountantBlocks(t *testing.T) {
+	qa := NewQuotaAccountant(2, QuotaModeBlock)
+	qa.Window = 50 * time.Millisecond
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, qa.Acquire(context.Background(), "123", 1))
+	}
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	qa.Window = time.Hour
+	assert.NoError(t, qa.Acquire(ctx, "456", 2))
+	assert.Error(t, qa.Acquire(ctx, "456", 1))
+}
+
+func TestQuotaAccountantBulkRequestsCount(t *testing.T) {
+	qa := NewQuotaAccountant(100, QuotaModeTrack)
+	assert.Equal(t, 1, qa.RequestsCount(0))
+	assert.Equal(t, 1, qa.RequestsCount(10))
+
+	qa.CountBulkSubRequests = true
+	assert.Equal(t, 1, qa.RequestsCount(0))
+	assert.Equal(t, 10, qa.RequestsCount(10))
+}
@@ -1,19 +1,19 @@
 package common
 
 import (
+	"context"
 	"sync"
 	"time"
 )
 
 //Throttler abstracts limiting of API requests
 type Throttler interface {
-	Throttle()
+	//Throttle blocks until the next request is allowed, it fails if the request cannot be made within the context
+	Throttle(ctx context.Context) error
 }
 
 type Sleeper func(sleepTime time.Duration)
 
-var sleepThrottler *SleepThrottler
-
 //SleepThrottler implements sleeping logic for requests throttling
 type SleepThrottler struct {
 	LimitPerSecond int
@@ -25,26 +25,22 @@ type SleepThrottler struct {
 
 //NewSleepThrottler creates SleepThrottler
 func NewSleepThrottler(limitPerSecond int, sl Sleeper) *SleepThrottler {
-	if sleepThrottler == nil {
-		sleepThrottler = &SleepThrottler{
-			LimitPerSecond: limitPerSecond,
-			LastTimestamp:  time.Now().Unix(),
-			Count:          0,
-			sl:             sl,
-			lock:           sync.Mutex{},
-		}
+	return &SleepThrottler{
+		LimitPerSecond: limitPerSecond,
+		LastTimestamp:  time.Now().Unix(),
+		Count:          0,
+		sl:             sl,
+		lock:           sync.Mutex{},
 	}
-
-	return sleepThrottler
 }
 
 //Throttle implements throttling method
-func (rt *SleepThrottler) Throttle() {
+func (rt *SleepThrottler) Throttle(ctx context.Context) error {
 	rt.lock.Lock()
 	defer rt.lock.Unlock()
 
 	if rt.LimitPerSecond <= 0 {
-		return
+		return ctx.Err()
 	}
 
 	rt.Count++
@@ -52,18 +48,152 @@ func (rt *SleepThrottler) Throttle() {
 	if now != rt.LastTimestamp {
 		rt.LastTimestamp = now
 		rt.Count = 1
-		return
+		return ctx.Err()
 	}
 
 	if rt.Count >= rt.LimitPerSecond {
 		rt.sl(time.Second)
 	}
+
+	return ctx.Err()
+}
+
+//TokenBucketThrottler allows bursts of requests up to the bucket capacity and refills the bucket
+//with the given rate, the waiting requests are served in the order of their arrival
+type TokenBucketThrottler struct {
+	limitPerSecond float64
+	burst          float64
+	tokens         float64
+	lastRefill     time.Time
+	sl             Sleeper
+	lock           sync.Mutex
+}
+
+//NewTokenBucketThrottler creates TokenBucketThrottler, limitPerSecond below or equal to 0 disables throttling,
+//burst is the amount of requests which can be made without waiting, it's at least 1
+func NewTokenBucketThrottler(limitPerSecond float64, burst int) *TokenBucketThrottler {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &TokenBucketThrottler{
+		limitPerSecond: limitPerSecond,
+		burst:          float64(burst),
+		tokens:         float64(burst),
+		lastRefill:     time.Now(),
+		lock:           sync.Mutex{},
+	}
+}
+
+//WithSleeper replaces context aware waiting with a custom sleeping logic, e.g. for tests
+func (tb *TokenBucketThrottler) WithSleeper(sl Sleeper) *TokenBucketThrottler {
+	tb.sl = sl
+	return tb
+}
+
+//Throttle implements Throttler interface
+func (tb *TokenBucketThrottler) Throttle(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if tb.limitPerSecond <= 0 {
+		return nil
+	}
+
+	waitingTime := tb.reserve()
+	if waitingTime <= 0 {
+		return nil
+	}
+
+	if tb.sl != nil {
+		tb.sl(waitingTime)
+		return ctx.Err()
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(waitingTime).After(deadline) {
+		tb.cancelReservation()
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(waitingTime)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		tb.cancelReservation()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+//reserve takes a token from the bucket and gives the time which is needed to wait till the token is available
+func (tb *TokenBucketThrottler) reserve() time.Duration {
+	tb.lock.Lock()
+	defer tb.lock.Unlock()
+
+	now := time.Now()
+	tb.tokens += now.Sub(tb.lastRefill).Seconds() * tb.limitPerSecond
+	if tb.tokens > tb.burst {
+		tb.tokens = tb.burst
+	}
+	tb.lastRefill = now
+
+	tb.tokens--
+	if tb.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-tb.tokens / tb.limitPerSecond * float64(time.Second))
+}
+
+func (tb *TokenBucketThrottler) cancelReservation() {
+	tb.lock.Lock()
+	defer tb.lock.Unlock()
+
+	tb.tokens++
+}
+
+//ThrottlersPool gives the same throttler for the same key, e.g. a client code, so that all clients
+//working with one account share one requests limit
+type ThrottlersPool struct {
+	limitPerSecond float64
+	burst          int
+	throttlers     map[string]*TokenBucketThrottler
+	lock           sync.Mutex
+}
+
+//NewThrottlersPool creates ThrottlersPool where each throttler has the given limit and burst
+func NewThrottlersPool(limitPerSecond float64, burst int) *ThrottlersPool {
+	return &ThrottlersPool{
+		limitPerSecond: limitPerSecond,
+		burst:          burst,
+		throttlers:     map[string]*TokenBucketThrottler{},
+		lock:           sync.Mutex{},
+	}
+}
+
+//Get gives the throttler of the key creating it on the first call
+func (tp *ThrottlersPool) Get(key string) *TokenBucketThrottler {
+	tp.lock.Lock()
+	defer tp.lock.Unlock()
+
+	thrl, ok := tp.throttlers[key]
+	if !ok {
+		thrl = NewTokenBucketThrottler(tp.limitPerSecond, tp.burst)
+		tp.throttlers[key] = thrl
+	}
+
+	return thrl
 }
 
 type ThrottlerMock struct {
 	WasTriggered bool
+	ErrToGive    error
 }
 
-func (tm *ThrottlerMock) Throttle() {
+func (tm *ThrottlerMock) Throttle(ctx context.Context) error {
 	tm.WasTriggered = true
+	return tm.ErrToGive
 }
@@ -0,0 +1,87 @@
+package common
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type SleeperMock struct {
+	SleepDurations []time.Duration
+	lock           sync.Mutex
+}
+
+func (sm *SleeperMock) Sleep(dur time.Duration) {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+	sm.SleepDurations = append(sm.SleepDurations, dur)
+}
+
+func TestTokenBucketThrottlerBurst(t *testing.T) {
+	sl := &SleeperMock{}
+	thrl := NewTokenBucketThrottler(1, 3).WithSleeper(sl.Sleep)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, thrl.Throttle(context.Background()))
+	}
+	assert.Len(t, sl.SleepDurations, 0)
+
+	assert.NoError(t, thrl.Throttle(context.Background()))
+	assert.NoError(t, thrl.Throttle(context.Background()))
+	assert.Len(t, sl.SleepDurations, 2)
+	assert.InDelta(t, time.Second, sl.SleepDurations[0], float64(time.Millisecond*50))
+	assert.InDelta(t, 2*time.Second, sl.SleepDurations[1], float64(time.Millisecond*50))
+}
+
+func TestTokenBucketThrottlerWaits(t *testing.T) {
+	thrl := NewTokenBucketThrottler(100, 1)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, thrl.Throttle(context.Background()))
+	}
+
+	assert.True(t, time.Since(start) >= 35*time.Millisecond)
+}
+
+func TestTokenBucketThrottlerRespectsContext(t *testing.T) {
+	thrl := NewTokenBucketThrottler(0.1, 1)
+	assert.NoError(t, thrl.Throttle(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, thrl.Throttle(ctx))
+
+	cancelledCtx, cancelFunc := context.WithCancel(context.Background())
+	cancelFunc()
+	assert.Equal(t, context.Canceled, thrl.Throttle(cancelledCtx))
+
+	assert.True(t, thrl.tokens > -1)
+}
+
+func TestTokenBucketThrottlerDisabled(t *testing.T) {
+	sl := &SleeperMock{}
+	thrl := NewTokenBucketThrottler(0, 0).WithSleeper(sl.Sleep)
+
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, thrl.Throttle(context.Background()))
+	}
+	assert.Len(t, sl.SleepDurations, 0)
+}
+
+func TestThrottlersPool(t *testing.T) {
+	pool := NewThrottlersPool(5, 10)
+
+	assert.True(t, pool.Get("123") == pool.Get("123"))
+	assert.False(t, pool.Get("123") == pool.Get("456"))
+}
+
+func TestSleepThrottlersAreIndependent(t *testing.T) {
+	first := NewSleepThrottler(1, func(sleepTime time.Duration) {})
+	second := NewSleepThrottler(5, func(sleepTime time.Duration) {})
+
+	assert.False(t, first == second)
+	assert.Equal(t, 5, second.LimitPerSecond)
+}
@@ -0,0 +1,116 @@
+package common
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultRetryAttempts        = 3
+	DefaultRetryInitialInterval = 500 * time.Millisecond
+	DefaultRetryMaxInterval     = 10 * time.Second
+	DefaultRetryMultiplier      = 2
+	DefaultRetryJitter          = 0.2
+)
+
+//DefaultRetryableCodes are API errors which are expected to disappear after some time
+var DefaultRetryableCodes = []ApiError{
+	ServerMaintenance,
+	HourlyRequestQuota,
+	AccountDbConnError,
+}
+
+//RetryPolicy describes when and how often a failed API request is repeated,
+//network errors and HTTP 5xx responses are always considered as retryable
+type RetryPolicy struct {
+	MaxAttempts     int           //total amount of attempts including the first one, retries are disabled if it's below 2
+	InitialInterval time.Duration //waiting time before the first retry
+	MaxInterval     time.Duration //upper limit of waiting time between attempts
+	Multiplier      float64       //growth factor of the waiting time after each attempt
+	Jitter          float64       //randomization factor in range [0, 1] applied to each waiting time
+	RetryableCodes  []ApiError    //API error codes which should trigger a retry
+}
+
+//NewDefaultRetryPolicy creates RetryPolicy with the recommended settings
+func NewDefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     DefaultRetryAttempts,
+		InitialInterval: DefaultRetryInitialInterval,
+		MaxInterval:     DefaultRetryMaxInterval,
+		Multiplier:      DefaultRetryMultiplier,
+		Jitter:          DefaultRetryJitter,
+		RetryableCodes:  DefaultRetryableCodes,
+	}
+}
+
+//IsEnabled tells if a failed request can be repeated at all
+func (rp RetryPolicy) IsEnabled() bool {
+	return rp.MaxAttempts > 1
+}
+
+//IsRetryableCode tells if a response with the given API error should be repeated
+func (rp RetryPolicy) IsRetryableCode(code ApiError) bool {
+	for _, retryableCode := range rp.RetryableCodes {
+		if retryableCode == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+//IsRetryableHTTPStatus tells if a response with the given HTTP status code should be repeated
+func (rp RetryPolicy) IsRetryableHTTPStatus(statusCode int) bool {
+	return statusCode >= http.StatusInternalServerError
+}
+
+//Backoff gives the waiting time before the next attempt, attempt is the number of the failed attempt starting from 1
+func (rp RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	multiplier := rp.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	interval := float64(rp.InitialInterval) * math.Pow(multiplier, float64(attempt-1))
+	if rp.MaxInterval > 0 && interval > float64(rp.MaxInterval) {
+		interval = float64(rp.MaxInterval)
+	}
+
+	jitter := rp.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+	if jitter > 0 {
+		delta := interval * jitter
+		interval = interval - delta + rand.Float64()*2*delta
+	}
+
+	return time.Duration(interval)
+}
+
+//Wait sleeps the backoff interval of the given attempt, it fails if the context is cancelled
+//or if its deadline comes earlier than the end of waiting
+func (rp RetryPolicy) Wait(ctx context.Context, attempt int) error {
+	dur := rp.Backoff(attempt)
+
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(dur).After(deadline) {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(dur)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,65 @@
+package common
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	rp := RetryPolicy{
+		MaxAttempts:     5,
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Second,
+		Multiplier:      2,
+	}
+
+	assert.Equal(t, time.Second, rp.Backoff(1))
+	assert.Equal(t, 2*time.Second, rp.Backoff(2))
+	assert.Equal(t, 4*time.Second, rp.Backoff(3))
+	assert.Equal(t, 5*time.Second, rp.Backoff(4))
+}
+
+func TestRetryPolicyBackoffJitter(t *testing.T) {
+	rp := RetryPolicy{
+		InitialInterval: time.Second,
+		Multiplier:      2,
+		Jitter:          0.5,
+	}
+
+	for i := 0; i < 100; i++ {
+		backoff := rp.Backoff(2)
+		assert.True(t, backoff >= time.Second, backoff)
+		assert.True(t, backoff <= 3*time.Second, backoff)
+	}
+}
+
+func TestRetryPolicyCodes(t *testing.T) {
+	rp := NewDefaultRetryPolicy()
+
+	assert.True(t, rp.IsEnabled())
+	assert.True(t, rp.IsRetryableCode(ServerMaintenance))
+	assert.True(t, rp.IsRetryableCode(HourlyRequestQuota))
+	assert.True(t, rp.IsRetryableCode(AccountDbConnError))
+	assert.False(t, rp.IsRetryableCode(MalformedRequest))
+	assert.True(t, rp.IsRetryableHTTPStatus(503))
+	assert.False(t, rp.IsRetryableHTTPStatus(404))
+
+	assert.False(t, RetryPolicy{}.IsEnabled())
+}
+
+func TestRetryPolicyWaitRespectsDeadline(t *testing.T) {
+	rp := RetryPolicy{InitialInterval: time.Minute}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := rp.Wait(ctx, 1)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = RetryPolicy{InitialInterval: time.Hour}.Wait(cancelledCtx, 1)
+	assert.Equal(t, context.Canceled, err)
+}
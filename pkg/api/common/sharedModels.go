@@ -27,15 +27,18 @@ type (
 		Attributes []ObjAttribute `json:"attributes"`
 	}
 
+	//ObjAttribute is also a save input, use it in a slice field with `filter:",indexed"` tag
+	//to send attributeName1, attributeType1, attributeValue1 and so on
 	ObjAttribute struct {
-		AttributeName  string `json:"attributeName"`
-		AttributeType  string `json:"attributeType"`
-		AttributeValue string `json:"attributeValue"`
+		AttributeName  string `json:"attributeName" filter:"attributeName"`
+		AttributeType  string `json:"attributeType" filter:"attributeType"`
+		AttributeValue string `json:"attributeValue" filter:"attributeValue"`
 	}
 
+	//LongAttribute is sent as longAttributeName1, longAttributeValue1 and so on in save requests
 	LongAttribute struct {
-		AttributeName  string `json:"attributeName"`
-		AttributeValue string `json:"attributeValue"`
+		AttributeName  string `json:"attributeName" filter:"longAttributeName"`
+		AttributeValue string `json:"attributeValue" filter:"longAttributeValue"`
 	}
 
 	LongAttributes struct {
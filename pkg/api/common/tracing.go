@@ -0,0 +1,101 @@
+package common
+
+import "context"
+
+//names of the span attributes and events which are recorded for API calls
+const (
+	SpanNameRequest     = "erply.request"
+	SpanNameBulkRequest = "erply.bulk_request"
+
+	EventBulkSubRequest = "erply.bulk.subrequest"
+	EventRetry          = "erply.retry"
+	EventSessionRenewal = "erply.session_renewal"
+	AttrMethod          = "erply.method"
+	AttrClientCode      = "erply.client_code"
+	AttrBulkSubRequests = "erply.bulk.subrequests_count"
+	AttrHTTPStatusCode  = "http.status_code"
+	AttrErrorCode       = "erply.error_code"
+	AttrErrorField      = "erply.error_field"
+	AttrResponseStatus  = "erply.response_status"
+	AttrGenerationTime  = "erply.generation_time"
+	AttrRecordsTotal    = "erply.records_total"
+	AttrRecordsInResp   = "erply.records_in_response"
+	AttrRequestName     = "erply.request_name"
+	AttrRequestID       = "erply.request_id"
+	AttrSubRequestIndex = "erply.bulk.subrequest_index"
+	AttrAttempt         = "erply.attempt"
+	AttrError           = "erply.error"
+)
+
+//Attribute is a key value pair attached to a span or an event
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+//Attr creates Attribute
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+//SpanStatusCode is the outcome of a span
+type SpanStatusCode int
+
+const (
+	SpanStatusUnset SpanStatusCode = iota
+	SpanStatusError
+	SpanStatusOK
+)
+
+//Span is a unit of traced work, its methods follow the OpenTelemetry trace.Span so an adapter is a thin wrapper
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	AddEvent(name string, attrs ...Attribute)
+	RecordError(err error)
+	SetStatus(code SpanStatusCode, description string)
+	End()
+}
+
+//Tracer starts spans, the returned context carries the span, so the spans started with it become children,
+//it follows the OpenTelemetry trace.Tracer
+type Tracer interface {
+	Start(ctx context.Context, spanName string, attrs ...Attribute) (context.Context, Span)
+}
+
+//NoopTracer starts spans which record nothing
+type NoopTracer struct{}
+
+//Start implements Tracer interface
+func (nt NoopTracer) Start(ctx context.Context, spanName string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, NoopSpan{}
+}
+
+//NoopSpan records nothing
+type NoopSpan struct{}
+
+func (ns NoopSpan) SetAttributes(attrs ...Attribute) {}
+
+func (ns NoopSpan) AddEvent(name string, attrs ...Attribute) {}
+
+func (ns NoopSpan) RecordError(err error) {}
+
+func (ns NoopSpan) SetStatus(code SpanStatusCode, description string) {}
+
+func (ns NoopSpan) End() {}
+
+type spanContextKey struct{}
+
+//ContextWithSpan gives a context which carries the span of the API call
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+//SpanFromContext gives the span of the API call which is in progress, e.g. to add events from a middleware,
+//it gives NoopSpan if the context has no span
+func SpanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanContextKey{}).(Span); ok {
+		return span
+	}
+
+	return NoopSpan{}
+}
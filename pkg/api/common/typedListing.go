@@ -0,0 +1,109 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+//TypedItem is Item with the payload of the listed type
+type TypedItem[T any] struct {
+	Err        error
+	TotalCount int
+	Payload    T
+}
+
+type TypedItemsStream[T any] chan TypedItem[T]
+
+//TypedLister is Lister which gives the payloads of the DataProvider as T, e.g. products.Product
+//for products.ListingDataProvider. Set ListingSettings.OrderedDelivery to get the items in the order of pages.
+type TypedLister[T any] struct {
+	lister *Lister
+}
+
+//NewTypedLister creates TypedLister, see NewLister
+func NewTypedLister[T any](settings ListingSettings, dataProvider DataProvider, sl Sleeper) *TypedLister[T] {
+	return &TypedLister[T]{lister: NewLister(settings, dataProvider, sl)}
+}
+
+//SetRequestThrottler concurrent unsafe setter, call it before calling any Get or All method
+func (tl *TypedLister[T]) SetRequestThrottler(thrl Throttler) {
+	tl.lister.SetRequestThrottler(thrl)
+}
+
+//Get is Lister.Get with typed items, a payload of another type gives an item with an error,
+//the filters are not changed
+func (tl *TypedLister[T]) Get(ctx context.Context, filters map[string]interface{}) TypedItemsStream[T] {
+	return tl.convert(ctx, tl.lister.Get(ctx, withoutPaging(filters)))
+}
+
+//GetResumable is Lister.GetResumable with typed items
+func (tl *TypedLister[T]) GetResumable(ctx context.Context, filters map[string]interface{}) (TypedItemsStream[T], *ListingProgress) {
+	itemsStream, progress := tl.lister.GetResumable(ctx, filters)
+	return tl.convert(ctx, itemsStream), progress
+}
+
+//Resume is Lister.Resume with typed items
+func (tl *TypedLister[T]) Resume(ctx context.Context, token ResumeToken) (TypedItemsStream[T], *ListingProgress) {
+	itemsStream, progress := tl.lister.Resume(ctx, token)
+	return tl.convert(ctx, itemsStream), progress
+}
+
+//GetSnapshot is Lister.GetSnapshot with typed items
+func (tl *TypedLister[T]) GetSnapshot(ctx context.Context, filters map[string]interface{}, settings SnapshotSettings) (TypedItemsStream[T], *SnapshotReport) {
+	itemsStream, report := tl.lister.GetSnapshot(ctx, filters, settings)
+	return tl.convert(ctx, itemsStream), report
+}
+
+//All gives the listed items as an iterator, the iteration can be stopped at any time, the fetching is cancelled then:
+//
+//	for product, err := range lister.All(ctx, filters) {
+//		if err != nil {
+//			return err
+//		}
+//	}
+func (tl *TypedLister[T]) All(ctx context.Context, filters map[string]interface{}) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		itemsStream := tl.Get(ctx, filters)
+		defer func() {
+			cancel()
+			for range itemsStream {
+			}
+		}()
+
+		for item := range itemsStream {
+			if !yield(item.Payload, item.Err) {
+				return
+			}
+		}
+	}
+}
+
+func (tl *TypedLister[T]) convert(ctx context.Context, itemsStream ItemsStream) TypedItemsStream[T] {
+	typedItemsStream := make(TypedItemsStream[T], tl.lister.listingSettings.StreamBufferLength)
+	go func() {
+		defer close(typedItemsStream)
+		for item := range itemsStream {
+			typedItem := TypedItem[T]{Err: item.Err, TotalCount: item.TotalCount}
+			if item.Err == nil {
+				payload, ok := item.Payload.(T)
+				if ok {
+					typedItem.Payload = payload
+				} else {
+					typedItem.Err = fmt.Errorf("unexpected listing payload type %T, expected %T", item.Payload, typedItem.Payload)
+				}
+			}
+
+			select {
+			case typedItemsStream <- typedItem:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return typedItemsStream
+}
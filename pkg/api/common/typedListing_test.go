@@ -0,0 +1,123 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+	"time"
+)
+
+//slowFirstPagesProviderMock gives the ids from 1 to total, the first pages are read slower than the others,
+//so the parallel fetchers finish them last
+type slowFirstPagesProviderMock struct {
+	total int
+	err   error
+}
+
+func (sfpm *slowFirstPagesProviderMock) Count(ctx context.Context, filters map[string]interface{}) (int, error) {
+	return sfpm.total, nil
+}
+
+func (sfpm *slowFirstPagesProviderMock) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	for _, filters := range bulkFilters {
+		pageNo := filters["pageNo"].(int)
+		recordsOnPage := filters["recordsOnPage"].(int)
+		if pageNo <= 2 {
+			time.Sleep(20 * time.Millisecond)
+		}
+		if sfpm.err != nil && pageNo == 3 {
+			return sfpm.err
+		}
+		for id := (pageNo-1)*recordsOnPage + 1; id <= pageNo*recordsOnPage && id <= sfpm.total; id++ {
+			callback(payloadMock{ID: id})
+		}
+	}
+
+	return nil
+}
+
+func TestTypedListerGet(t *testing.T) {
+	dp := &pagedProviderMock{total: 5}
+	lister := NewTypedLister[payloadMock](ListingSettings{MaxItemsPerRequest: 2, MaxFetchersCount: 3}, dp, NullSleeper)
+
+	filters := map[string]interface{}{"filterKey": "filterVal"}
+	ids := make([]int, 0, 5)
+	for item := range lister.Get(context.Background(), filters) {
+		assert.NoError(t, item.Err)
+		assert.Equal(t, 5, item.TotalCount)
+		ids = append(ids, item.Payload.ID)
+	}
+	sort.Ints(ids)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+	assert.Equal(t, map[string]interface{}{"filterKey": "filterVal"}, filters)
+}
+
+func TestTypedListerWrongPayloadType(t *testing.T) {
+	lister := NewTypedLister[string](ListingSettings{}, &pagedProviderMock{total: 1}, NullSleeper)
+
+	items := make([]TypedItem[string], 0, 1)
+	for item := range lister.Get(context.Background(), map[string]interface{}{}) {
+		items = append(items, item)
+	}
+
+	assert.Len(t, items, 1)
+	assert.EqualError(t, items[0].Err, "unexpected listing payload type common.payloadMock, expected string")
+}
+
+func TestOrderedDelivery(t *testing.T) {
+	dp := &slowFirstPagesProviderMock{total: 12}
+	settings := ListingSettings{MaxItemsPerRequest: 2, MaxFetchersCount: 4, OrderedDelivery: true}
+
+	ids := make([]int, 0, 12)
+	for product, err := range NewTypedLister[payloadMock](settings, dp, NullSleeper).All(context.Background(), map[string]interface{}{}) {
+		assert.NoError(t, err)
+		ids = append(ids, product.ID)
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, ids)
+
+	//the plain Lister gives the same order
+	ids = ids[:0]
+	for item := range NewLister(settings, dp, NullSleeper).Get(context.Background(), map[string]interface{}{}) {
+		ids = append(ids, item.Payload.(payloadMock).ID)
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, ids)
+}
+
+func TestOrderedDeliveryWithError(t *testing.T) {
+	dp := &slowFirstPagesProviderMock{total: 8, err: errors.New("some read error")}
+	settings := ListingSettings{MaxItemsPerRequest: 2, MaxFetchersCount: 2, OrderedDelivery: true}
+
+	ids := make([]int, 0, 8)
+	var errs []error
+	for product, err := range NewTypedLister[payloadMock](settings, dp, NullSleeper).All(context.Background(), map[string]interface{}{}) {
+		if err != nil {
+			errs = append(errs, err)
+			//the error is given in the place of its page
+			assert.Equal(t, []int{1, 2, 3, 4}, ids)
+			continue
+		}
+		ids = append(ids, product.ID)
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4, 7, 8}, ids)
+	assert.Len(t, errs, 1)
+}
+
+func TestTypedListerAllStopsEarly(t *testing.T) {
+	dp := &pagedProviderMock{total: 1000}
+	lister := NewTypedLister[payloadMock](ListingSettings{MaxItemsPerRequest: 10, MaxFetchersCount: 2}, dp, NullSleeper)
+
+	count := 0
+	for _, err := range lister.All(context.Background(), map[string]interface{}{}) {
+		assert.NoError(t, err)
+		count++
+		if count == 15 {
+			break
+		}
+	}
+
+	assert.Equal(t, 15, count)
+}
@@ -2,27 +2,15 @@ package customers
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
-	"fmt"
 	"github.com/erply/api-go-wrapper/internal/common"
 	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
-	"io/ioutil"
-	"net/http"
 )
 
 func (cli *Client) SaveCustomer(ctx context.Context, filters map[string]string) (*CustomerImportReport, error) {
-	resp, err := cli.SendRequest(ctx, "saveCustomer", filters)
+	res, err := common.Call[PostCustomerResponse](ctx, cli.Client, "saveCustomer", filters)
 	if err != nil {
-		return nil, sharedCommon.NewFromError("PostCustomer request failed", err, 0)
-	}
-	res := &PostCustomerResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("unmarshaling CustomerImportReport failed", err, 0)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
+		return nil, err
 	}
 
 	if len(res.CustomerImportReports) == 0 {
@@ -34,54 +22,36 @@ func (cli *Client) SaveCustomer(ctx context.Context, filters map[string]string)
 
 // GetCustomers will list customers according to specified filters.
 func (cli *Client) GetCustomers(ctx context.Context, filters map[string]string) ([]Customer, error) {
-	resp, err := cli.SendRequest(ctx, "getCustomers", filters)
+	res, err := common.Call[GetCustomersResponse](ctx, cli.Client, "getCustomers", filters)
 	if err != nil {
 		return nil, err
 	}
-	var res GetCustomersResponse
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("failed to unmarshal GetCustomersResponse", err, 0)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 	return res.Customers, nil
 }
 
 // GetCustomersBulk will list customers according to specified filters sending a bulk request to fetch more customers than the default limit
 func (cli *Client) GetCustomersBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetCustomersResponseBulk, error) {
-	var customersResponse GetCustomersResponseBulk
-	bulkInputs := make([]common.BulkInput, 0, len(bulkFilters))
-	for _, bulkFilterMap := range bulkFilters {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "getCustomers",
-			Filters:    bulkFilterMap,
-		})
-	}
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return customersResponse, err
-	}
+	return common.CallBulk[GetCustomersResponseBulk](ctx, cli.Client, "getCustomers", bulkFilters, baseFilters)
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
+//GetCustomersWithFilter is GetCustomers with a typed filter
+func (cli *Client) GetCustomersWithFilter(ctx context.Context, filter CustomerFilter) ([]Customer, error) {
+	filters, err := filter.ToFilters()
 	if err != nil {
-		return customersResponse, err
+		return nil, err
 	}
 
-	if err := json.Unmarshal(body, &customersResponse); err != nil {
-		return customersResponse, fmt.Errorf("ERPLY API: failed to unmarshal GetCustomersResponseBulk from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&customersResponse.Status) {
-		return customersResponse, sharedCommon.NewErplyError(customersResponse.Status.ErrorCode.String(), customersResponse.Status.Request+": "+customersResponse.Status.ResponseStatus, customersResponse.Status.ErrorCode)
-	}
+	return cli.GetCustomers(ctx, filters)
+}
 
-	for _, supplierBulkItem := range customersResponse.BulkItems {
-		if !common.IsJSONResponseOK(&supplierBulkItem.Status.Status) {
-			return customersResponse, sharedCommon.NewErplyError(supplierBulkItem.Status.ErrorCode.String(), supplierBulkItem.Status.Request+": "+supplierBulkItem.Status.ResponseStatus, customersResponse.Status.ErrorCode)
-		}
+//GetCustomersBulkWithFilters is GetCustomersBulk with typed filters of the sub-requests
+func (cli *Client) GetCustomersBulkWithFilters(ctx context.Context, filters []CustomerFilter, baseFilters map[string]string) (GetCustomersResponseBulk, error) {
+	bulkFilters, err := sharedCommon.ToBulkFilters(filters)
+	if err != nil {
+		return GetCustomersResponseBulk{}, err
 	}
 
-	return customersResponse, nil
+	return cli.GetCustomersBulk(ctx, bulkFilters, baseFilters)
 }
 
 //username and password are required fields here
@@ -90,21 +60,12 @@ func (cli *Client) VerifyCustomerUser(ctx context.Context, username, password st
 		"username": username,
 		"password": password,
 	}
-	resp, err := cli.SendRequest(ctx, "verifyCustomerUser", filters)
-	if err != nil {
-		return nil, sharedCommon.NewFromError("VerifyCustomerUser: request failed", err, 0)
-	}
-
-	var res struct {
+	res, err := common.Call[struct {
 		Status  sharedCommon.Status
 		Records []WebshopClient
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("VerifyCustomerUser: unmarhsalling response failed", err, 0)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
+	}](ctx, cli.Client, "verifyCustomerUser", filters)
+	if err != nil {
+		return nil, err
 	}
 	if len(res.Records) != 1 {
 		return nil, sharedCommon.NewFromError("VerifyCustomerUser: no records in response", nil, res.Status.ErrorCode)
@@ -113,40 +74,19 @@ func (cli *Client) VerifyCustomerUser(ctx context.Context, username, password st
 	return &res.Records[0], nil
 }
 func (cli *Client) ValidateCustomerUsername(ctx context.Context, username string) (bool, error) {
-	method := "validateCustomerUsername"
 	params := map[string]string{"username": username}
-	resp, err := cli.SendRequest(ctx, method, params)
+	_, err := common.Call[struct{ Status sharedCommon.Status }](ctx, cli.Client, "validateCustomerUsername", params)
 	if err != nil {
-		return false, sharedCommon.NewFromError("IsCustomerUsernameAvailable: error sending request", err, 0)
-	}
-	if resp.StatusCode != http.StatusOK {
-		return false, sharedCommon.NewFromError(fmt.Sprintf(method+": bad response status code: %d", resp.StatusCode), nil, 0)
-	}
-
-	var respData struct {
-		Status sharedCommon.Status
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&respData); err != nil {
-		return false, sharedCommon.NewFromError(method+": unmarshaling response failed", err, 0)
-	}
-	if respData.Status.ErrorCode != 0 {
-		return false, sharedCommon.NewFromError(fmt.Sprintf(method+": bad response error code: %s", respData.Status.ErrorCode), nil, respData.Status.ErrorCode)
+		return false, err
 	}
 	return true, nil
 }
 
 func (cli *Client) AddCustomerRewardPoints(ctx context.Context, filters map[string]string) (AddCustomerRewardPointsResult, error) {
-	resp, err := cli.SendRequest(ctx, "addCustomerRewardPoints", filters)
+	res, err := common.Call[AddCustomerRewardPointsResponse](ctx, cli.Client, "addCustomerRewardPoints", filters)
 	if err != nil {
 		return AddCustomerRewardPointsResult{}, err
 	}
-	var res AddCustomerRewardPointsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return AddCustomerRewardPointsResult{}, sharedCommon.NewFromError("failed to unmarshal AddCustomerRewardPointsResponse", err, 0)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return AddCustomerRewardPointsResult{}, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 	if len(res.AddCustomerRewardPointsResults) > 0 {
 		return res.AddCustomerRewardPointsResults[0], nil
 	}
@@ -155,150 +95,42 @@ func (cli *Client) AddCustomerRewardPoints(ctx context.Context, filters map[stri
 }
 
 func (cli *Client) AddCustomerRewardPointsBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (AddCustomerRewardPointsResponseBulk, error) {
-	var respBulk AddCustomerRewardPointsResponseBulk
-	bulkInputs := make([]common.BulkInput, 0, len(bulkFilters))
-	for _, bulkFilterMap := range bulkFilters {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "addCustomerRewardPoints",
-			Filters:    bulkFilterMap,
-		})
-	}
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return respBulk, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return respBulk, err
-	}
-
-	if err := json.Unmarshal(body, &respBulk); err != nil {
-		return respBulk, fmt.Errorf("ERPLY API: failed to unmarshal AddCustomerRewardPointsResponseBulk from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&respBulk.Status) {
-		return respBulk, sharedCommon.NewErplyError(respBulk.Status.ErrorCode.String(), respBulk.Status.Request+": "+respBulk.Status.ResponseStatus, respBulk.Status.ErrorCode)
-	}
-
-	for _, bulkItem := range respBulk.BulkItems {
-		if !common.IsJSONResponseOK(&bulkItem.Status.Status) {
-			return respBulk, sharedCommon.NewErplyError(bulkItem.Status.ErrorCode.String(), bulkItem.Status.Request+": "+bulkItem.Status.ResponseStatus, respBulk.Status.ErrorCode)
-		}
-	}
-
-	return respBulk, nil
+	return common.CallBulk[AddCustomerRewardPointsResponseBulk](ctx, cli.Client, "addCustomerRewardPoints", bulkFilters, baseFilters)
 }
 
 func (cli *Client) SaveCustomerBulk(ctx context.Context, customerMap []map[string]interface{}, attrs map[string]string) (SaveCustomerResponseBulk, error) {
-	var saveCustomerResponseBulk SaveCustomerResponseBulk
-
-	if len(customerMap) > sharedCommon.MaxBulkRequestsCount {
-		return saveCustomerResponseBulk, fmt.Errorf("cannot save more than %d customers in one request", sharedCommon.MaxBulkRequestsCount)
-	}
-
-	bulkInputs := make([]common.BulkInput, 0, len(customerMap))
-	for _, customer := range customerMap {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "saveCustomer",
-			Filters:    customer,
-		})
-	}
-
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, attrs)
-	if err != nil {
-		return saveCustomerResponseBulk, err
-	}
+	return common.CallBulk[SaveCustomerResponseBulk](ctx, cli.Client, "saveCustomer", customerMap, attrs)
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
+//SaveCustomerWithInput is SaveCustomer with a typed input
+func (cli *Client) SaveCustomerWithInput(ctx context.Context, input CustomerInput) (*CustomerImportReport, error) {
+	filters, err := input.ToFilters()
 	if err != nil {
-		return saveCustomerResponseBulk, err
-	}
-
-	if err := json.Unmarshal(body, &saveCustomerResponseBulk); err != nil {
-		return saveCustomerResponseBulk, fmt.Errorf("ERPLY API: failed to unmarshal SaveCustomerResponseBulk from '%s': %v", string(body), err)
+		return nil, err
 	}
 
-	if !common.IsJSONResponseOK(&saveCustomerResponseBulk.Status) {
-		return saveCustomerResponseBulk, sharedCommon.NewErplyError(saveCustomerResponseBulk.Status.ErrorCode.String(), saveCustomerResponseBulk.Status.Request+": "+saveCustomerResponseBulk.Status.ResponseStatus, saveCustomerResponseBulk.Status.ErrorCode)
-	}
+	return cli.SaveCustomer(ctx, filters)
+}
 
-	for _, bulkItem := range saveCustomerResponseBulk.BulkItems {
-		if !common.IsJSONResponseOK(&bulkItem.Status.Status) {
-			return saveCustomerResponseBulk, sharedCommon.NewErplyError(
-				bulkItem.Status.ErrorCode.String(),
-				fmt.Sprintf("%+v", bulkItem.Status),
-				saveCustomerResponseBulk.Status.ErrorCode,
-			)
-		}
+//SaveCustomerBulkWithInputs is SaveCustomerBulk with typed inputs of the sub-requests
+func (cli *Client) SaveCustomerBulkWithInputs(ctx context.Context, inputs []CustomerInput, baseFilters map[string]string) (SaveCustomerResponseBulk, error) {
+	bulkFilters, err := sharedCommon.ToBulkFilters(inputs)
+	if err != nil {
+		return SaveCustomerResponseBulk{}, err
 	}
 
-	return saveCustomerResponseBulk, nil
+	return cli.SaveCustomerBulk(ctx, bulkFilters, baseFilters)
 }
 
 func (cli *Client) DeleteCustomer(ctx context.Context, filters map[string]string) error {
-	resp, err := cli.SendRequest(ctx, "deleteCustomer", filters)
+	_, err := common.Call[DeleteCustomerResponse](ctx, cli.Client, "deleteCustomer", filters)
 	if err != nil {
 		return err
 	}
 
-	var res DeleteCustomerResponse
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return sharedCommon.NewFromError("failed to unmarshal DeleteCustomerResponse ", err, 0)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return sharedCommon.NewFromResponseStatus(&res.Status)
-	}
-
 	return nil
 }
 
 func (cli *Client) DeleteCustomerBulk(ctx context.Context, customerMap []map[string]interface{}, attrs map[string]string) (DeleteCustomersResponseBulk, error) {
-	var deleteCustomersResponse DeleteCustomersResponseBulk
-
-	if len(customerMap) > sharedCommon.MaxBulkRequestsCount {
-		return deleteCustomersResponse, fmt.Errorf("cannot delete more than %d customers in one request", sharedCommon.MaxBulkRequestsCount)
-	}
-
-	bulkInputs := make([]common.BulkInput, 0, len(customerMap))
-	for _, filter := range customerMap {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "deleteCustomer",
-			Filters:    filter,
-		})
-	}
-
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, attrs)
-	if err != nil {
-		return deleteCustomersResponse, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return deleteCustomersResponse, err
-	}
-
-	if err := json.Unmarshal(body, &deleteCustomersResponse); err != nil {
-		return deleteCustomersResponse, fmt.Errorf("ERPLY API: failed to unmarshal DeleteCustomersResponseBulk from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&deleteCustomersResponse.Status) {
-		return deleteCustomersResponse, sharedCommon.NewErplyError(
-			deleteCustomersResponse.Status.ErrorCode.String(),
-			deleteCustomersResponse.Status.Request+": "+deleteCustomersResponse.Status.ResponseStatus,
-			deleteCustomersResponse.Status.ErrorCode,
-		)
-	}
-
-	for _, bulkItem := range deleteCustomersResponse.BulkItems {
-		if !common.IsJSONResponseOK(&bulkItem.Status.Status) {
-			return deleteCustomersResponse, sharedCommon.NewErplyError(
-				bulkItem.Status.ErrorCode.String(),
-				fmt.Sprintf("%+v", bulkItem.Status),
-				deleteCustomersResponse.Status.ErrorCode,
-			)
-		}
-	}
-
-	return deleteCustomersResponse, nil
+	return common.CallBulk[DeleteCustomersResponseBulk](ctx, cli.Client, "deleteCustomer", customerMap, attrs)
 }
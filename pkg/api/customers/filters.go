@@ -0,0 +1,56 @@
+package customers
+
+import (
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"time"
+)
+
+//CustomerFilter is a typed filter of getCustomers
+type CustomerFilter struct {
+	CustomerID             int       `filter:"customerID"`
+	CustomerIDs            []int     `filter:"customerIDs"`
+	CustomerCardNumber     string    `filter:"customerCardNumber"`
+	SearchName             string    `filter:"searchName"`
+	SearchRegistryCode     string    `filter:"searchRegistryCode"`
+	SearchVATNumber        string    `filter:"searchVATNumber"`
+	SearchEmail            string    `filter:"searchEmail"`
+	SearchPhone            string    `filter:"searchPhone"`
+	SearchFromMiddle       bool      `filter:"searchFromMiddle"`
+	GroupID                int       `filter:"groupID"`
+	Mode                   string    `filter:"mode"`
+	ResponseMode           string    `filter:"responseMode"`
+	ChangedSince           time.Time `filter:"changedSince"`
+	CreatedUnixTimeFrom    time.Time `filter:"createdUnixTimeFrom"`
+	GetBalanceInfo         bool      `filter:"getBalanceInfo"`
+	GetAddresses           bool      `filter:"getAddresses"`
+	GetContactPersons      bool      `filter:"getContactPersons"`
+	GetAllowedWarehouses   bool      `filter:"getAllowedWarehouses"`
+	GetRewardPointsBalance bool      `filter:"getRewardPointsBalance"`
+	sharedCommon.Paging
+	Extra map[string]string `filter:",extra"` //parameters which have no field
+}
+
+//ToFilters sharedCommon.Filter interface implementation
+func (cf CustomerFilter) ToFilters() (map[string]string, error) {
+	return sharedCommon.EncodeFilters(cf)
+}
+
+//SupplierFilter is a typed filter of getSuppliers
+type SupplierFilter struct {
+	SupplierID         int       `filter:"supplierID"`
+	SupplierIDs        []int     `filter:"supplierIDs"`
+	SearchName         string    `filter:"searchName"`
+	SearchRegistryCode string    `filter:"searchRegistryCode"`
+	SearchVATNumber    string    `filter:"searchVATNumber"`
+	GroupID            int       `filter:"groupID"`
+	ChangedSince       time.Time `filter:"changedSince"`
+	GetAddresses       bool      `filter:"getAddresses"`
+	GetContactPersons  bool      `filter:"getContactPersons"`
+	sharedCommon.Paging
+	Extra map[string]string `filter:",extra"` //parameters which have no field
+}
+
+//ToFilters sharedCommon.Filter interface implementation
+func (sf SupplierFilter) ToFilters() (map[string]string, error) {
+	return sharedCommon.EncodeFilters(sf)
+}
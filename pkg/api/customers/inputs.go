@@ -0,0 +1,47 @@
+package customers
+
+import (
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"time"
+)
+
+//CustomerInput is a typed input of saveCustomer, set CustomerID to update an existing customer
+type CustomerInput struct {
+	CustomerID       int       `filter:"customerID"`
+	CompanyName      string    `filter:"companyName"`
+	FirstName        string    `filter:"firstName"`
+	LastName         string    `filter:"lastName"`
+	PersonTitleID    int       `filter:"personTitleID"`
+	Gender           string    `filter:"gender"`
+	GroupID          int       `filter:"groupID"`
+	CountryID        int       `filter:"countryID"`
+	CompanyTypeID    int       `filter:"companyTypeID"`
+	Code             string    `filter:"code"`
+	VatNumber        string    `filter:"vatNumber"`
+	Email            string    `filter:"email"`
+	Phone            string    `filter:"phone"`
+	Mobile           string    `filter:"mobile"`
+	Fax              string    `filter:"fax"`
+	Birthday         time.Time `filter:"birthday,date"`
+	Notes            string    `filter:"notes"`
+	PaymentDays      int       `filter:"paymentDays"`
+	Credit           int       `filter:"credit"`
+	EuCustomerType   string    `filter:"euCustomerType"`
+	PayerID          int       `filter:"payerID"`
+	EmailEnabled     *bool     `filter:"emailEnabled"`
+	EInvoiceEnabled  *bool     `filter:"eInvoiceEnabled"`
+	DocuraEDIEnabled *bool     `filter:"docuraEDIEnabled"`
+	MailEnabled      *bool     `filter:"mailEnabled"`
+	TaxExempt        *bool     `filter:"taxExempt"`
+	SalesBlocked     *bool     `filter:"salesBlocked"`
+
+	//sent as attributeName1, attributeType1, attributeValue1 and so on
+	Attributes []sharedCommon.ObjAttribute `filter:",indexed"`
+
+	Extra map[string]string `filter:",extra"` //parameters which have no field
+}
+
+//ToFilters sharedCommon.Filter interface implementation
+func (ci CustomerInput) ToFilters() (map[string]string, error) {
+	return sharedCommon.EncodeFilters(ci)
+}
@@ -5,14 +5,20 @@ import "context"
 type Manager interface {
 	SaveCustomer(ctx context.Context, filters map[string]string) (*CustomerImportReport, error)
 	SaveCustomerBulk(ctx context.Context, customerMap []map[string]interface{}, attrs map[string]string) (SaveCustomerResponseBulk, error)
+	SaveCustomerWithInput(ctx context.Context, input CustomerInput) (*CustomerImportReport, error)
+	SaveCustomerBulkWithInputs(ctx context.Context, inputs []CustomerInput, baseFilters map[string]string) (SaveCustomerResponseBulk, error)
 	GetCustomers(ctx context.Context, filters map[string]string) ([]Customer, error)
 	GetCustomersBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetCustomersResponseBulk, error)
+	GetCustomersWithFilter(ctx context.Context, filter CustomerFilter) ([]Customer, error)
+	GetCustomersBulkWithFilters(ctx context.Context, filters []CustomerFilter, baseFilters map[string]string) (GetCustomersResponseBulk, error)
 	DeleteCustomer(ctx context.Context, filters map[string]string) error
 	DeleteCustomerBulk(ctx context.Context, customerMap []map[string]interface{}, attrs map[string]string) (DeleteCustomersResponseBulk, error)
 	VerifyCustomerUser(ctx context.Context, username, password string) (*WebshopClient, error)
 	ValidateCustomerUsername(ctx context.Context, username string) (bool, error)
 	GetSuppliers(ctx context.Context, filters map[string]string) ([]Supplier, error)
 	GetSuppliersBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetSuppliersResponseBulk, error)
+	GetSuppliersWithFilter(ctx context.Context, filter SupplierFilter) ([]Supplier, error)
+	GetSuppliersBulkWithFilters(ctx context.Context, filters []SupplierFilter, baseFilters map[string]string) (GetSuppliersResponseBulk, error)
 	SaveSupplier(ctx context.Context, filters map[string]string) (*CustomerImportReport, error)
 	SaveSupplierBulk(ctx context.Context, suppliers []map[string]interface{}, attrs map[string]string) (SaveSuppliersResponseBulk, error)
 	DeleteSupplier(ctx context.Context, filters map[string]string) error
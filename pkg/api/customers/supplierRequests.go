@@ -2,77 +2,48 @@ package customers
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"github.com/erply/api-go-wrapper/internal/common"
 	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
-	"io/ioutil"
 )
 
 // GetSuppliers will list suppliers according to specified filters.
 func (cli *Client) GetSuppliers(ctx context.Context, filters map[string]string) ([]Supplier, error) {
-	resp, err := cli.SendRequest(ctx, "getSuppliers", filters)
+	res, err := common.Call[GetSuppliersResponse](ctx, cli.Client, "getSuppliers", filters)
 	if err != nil {
 		return nil, err
 	}
-	var res GetSuppliersResponse
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("failed to unmarshal GetSuppliersResponse ", err, 0)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 	return res.Suppliers, nil
 }
 
 // GetSuppliersBulk will list suppliers according to specified filters sending a bulk request to fetch more suppliers than the default limit
 func (cli *Client) GetSuppliersBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetSuppliersResponseBulk, error) {
-	var suppliersResp GetSuppliersResponseBulk
-	bulkInputs := make([]common.BulkInput, 0, len(bulkFilters))
-	for _, bulkFilterMap := range bulkFilters {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "getSuppliers",
-			Filters:    bulkFilterMap,
-		})
-	}
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return suppliersResp, err
-	}
+	return common.CallBulk[GetSuppliersResponseBulk](ctx, cli.Client, "getSuppliers", bulkFilters, baseFilters)
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
+//GetSuppliersWithFilter is GetSuppliers with a typed filter
+func (cli *Client) GetSuppliersWithFilter(ctx context.Context, filter SupplierFilter) ([]Supplier, error) {
+	filters, err := filter.ToFilters()
 	if err != nil {
-		return suppliersResp, err
+		return nil, err
 	}
 
-	if err := json.Unmarshal(body, &suppliersResp); err != nil {
-		return suppliersResp, fmt.Errorf("ERPLY API: failed to unmarshal GetSuppliersResponseBulk from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&suppliersResp.Status) {
-		return suppliersResp, sharedCommon.NewErplyError(suppliersResp.Status.ErrorCode.String(), suppliersResp.Status.Request+": "+suppliersResp.Status.ResponseStatus, suppliersResp.Status.ErrorCode)
-	}
+	return cli.GetSuppliers(ctx, filters)
+}
 
-	for _, supplierBulkItem := range suppliersResp.BulkItems {
-		if !common.IsJSONResponseOK(&supplierBulkItem.Status.Status) {
-			return suppliersResp, sharedCommon.NewErplyError(supplierBulkItem.Status.ErrorCode.String(), supplierBulkItem.Status.Request+": "+supplierBulkItem.Status.ResponseStatus, suppliersResp.Status.ErrorCode)
-		}
+//GetSuppliersBulkWithFilters is GetSuppliersBulk with typed filters of the sub-requests
+func (cli *Client) GetSuppliersBulkWithFilters(ctx context.Context, filters []SupplierFilter, baseFilters map[string]string) (GetSuppliersResponseBulk, error) {
+	bulkFilters, err := sharedCommon.ToBulkFilters(filters)
+	if err != nil {
+		return GetSuppliersResponseBulk{}, err
 	}
 
-	return suppliersResp, nil
+	return cli.GetSuppliersBulk(ctx, bulkFilters, baseFilters)
 }
 
 func (cli *Client) SaveSupplier(ctx context.Context, filters map[string]string) (*CustomerImportReport, error) {
-	resp, err := cli.SendRequest(ctx, "saveSupplier", filters)
+	res, err := common.Call[PostCustomerResponse](ctx, cli.Client, "saveSupplier", filters)
 	if err != nil {
-		return nil, sharedCommon.NewFromError("PostSupplier request failed", err, 0)
-	}
-	res := &PostCustomerResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("unmarshaling CustomerImportReport failed", err, 0)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
+		return nil, err
 	}
 
 	if len(res.CustomerImportReports) == 0 {
@@ -83,117 +54,18 @@ func (cli *Client) SaveSupplier(ctx context.Context, filters map[string]string)
 }
 
 func (cli *Client) SaveSupplierBulk(ctx context.Context, supplierMap []map[string]interface{}, attrs map[string]string) (SaveSuppliersResponseBulk, error) {
-	var saveSuppliersResponseBulk SaveSuppliersResponseBulk
-
-	if len(supplierMap) > sharedCommon.MaxBulkRequestsCount {
-		return saveSuppliersResponseBulk, fmt.Errorf("cannot save more than %d suppliers in one request", sharedCommon.MaxBulkRequestsCount)
-	}
-
-	bulkInputs := make([]common.BulkInput, 0, len(supplierMap))
-	for _, supplier := range supplierMap {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "saveSupplier",
-			Filters:    supplier,
-		})
-	}
-
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, attrs)
-	if err != nil {
-		return saveSuppliersResponseBulk, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return saveSuppliersResponseBulk, err
-	}
-
-	if err := json.Unmarshal(body, &saveSuppliersResponseBulk); err != nil {
-		return saveSuppliersResponseBulk, fmt.Errorf("ERPLY API: failed to unmarshal SaveSuppliersResponseBulk from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&saveSuppliersResponseBulk.Status) {
-		return saveSuppliersResponseBulk, sharedCommon.NewErplyError(
-			saveSuppliersResponseBulk.Status.ErrorCode.String(),
-			saveSuppliersResponseBulk.Status.Request+": "+saveSuppliersResponseBulk.Status.ResponseStatus,
-			saveSuppliersResponseBulk.Status.ErrorCode,
-		)
-	}
-
-	for _, supplierBulkItem := range saveSuppliersResponseBulk.BulkItems {
-		if !common.IsJSONResponseOK(&supplierBulkItem.Status.Status) {
-			return saveSuppliersResponseBulk, sharedCommon.NewErplyError(
-				supplierBulkItem.Status.ErrorCode.String(),
-				fmt.Sprintf("%+v", supplierBulkItem.Status),
-				supplierBulkItem.Status.ErrorCode,
-			)
-		}
-	}
-
-	return saveSuppliersResponseBulk, nil
+	return common.CallBulk[SaveSuppliersResponseBulk](ctx, cli.Client, "saveSupplier", supplierMap, attrs)
 }
 
 // DeleteSupplier https://learn-api.erply.com/requests/deletesupplier/
 func (cli *Client) DeleteSupplier(ctx context.Context, filters map[string]string) error {
-	resp, err := cli.SendRequest(ctx, "deleteSupplier", filters)
+	_, err := common.Call[DeleteSupplierResponse](ctx, cli.Client, "deleteSupplier", filters)
 	if err != nil {
 		return err
 	}
-	var res DeleteSupplierResponse
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return sharedCommon.NewFromError("failed to unmarshal DeleteSupplierResponse ", err, 0)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 	return nil
 }
 
 func (cli *Client) DeleteSupplierBulk(ctx context.Context, supplierMap []map[string]interface{}, attrs map[string]string) (DeleteSuppliersResponseBulk, error) {
-	var deleteSupplierResponse DeleteSuppliersResponseBulk
-
-	if len(supplierMap) > sharedCommon.MaxBulkRequestsCount {
-		return deleteSupplierResponse, fmt.Errorf("cannot delete more than %d suppliers in one request", sharedCommon.MaxBulkRequestsCount)
-	}
-
-	bulkInputs := make([]common.BulkInput, 0, len(supplierMap))
-	for _, filter := range supplierMap {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "deleteSupplier",
-			Filters:    filter,
-		})
-	}
-
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, attrs)
-	if err != nil {
-		return deleteSupplierResponse, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return deleteSupplierResponse, err
-	}
-
-	if err := json.Unmarshal(body, &deleteSupplierResponse); err != nil {
-		return deleteSupplierResponse, fmt.Errorf("ERPLY API: failed to unmarshal DeleteSuppliersResponseBulk from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&deleteSupplierResponse.Status) {
-		return deleteSupplierResponse, sharedCommon.NewErplyError(
-			deleteSupplierResponse.Status.ErrorCode.String(),
-			deleteSupplierResponse.Status.Request+": "+deleteSupplierResponse.Status.ResponseStatus,
-			deleteSupplierResponse.Status.ErrorCode,
-		)
-	}
-
-	for _, supplierBulkItem := range deleteSupplierResponse.BulkItems {
-		if !common.IsJSONResponseOK(&supplierBulkItem.Status.Status) {
-			return deleteSupplierResponse, sharedCommon.NewErplyError(
-				supplierBulkItem.Status.ErrorCode.String(),
-				fmt.Sprintf("%+v", supplierBulkItem.Status),
-				deleteSupplierResponse.Status.ErrorCode,
-			)
-		}
-	}
-
-	return deleteSupplierResponse, nil
+	return common.CallBulk[DeleteSuppliersResponseBulk](ctx, cli.Client, "deleteSupplier", supplierMap, attrs)
 }
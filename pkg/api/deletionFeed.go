@@ -0,0 +1,232 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	//DeleteOperation is the operation of the deleted records in the operations log
+	DeleteOperation = "delete"
+	//DeletionCheckpointSuffix is added to the entity name to get the checkpoint key of its deletions,
+	//so DeletionFeed and sharedCommon.DeltaSync can share the same sharedCommon.CheckpointStore
+	DeletionCheckpointSuffix = ":deletions"
+)
+
+//DeletionEntity maps an entity of the mirror to its table in the operations log
+type DeletionEntity struct {
+	Name      string //the same name as in sharedCommon.SyncEntity, e.g. "products"
+	TableName string //tableName of the operations log, e.g. "products"
+}
+
+//the entities which are mirrored with sharedCommon.DeltaSync and their tables in the operations log
+var (
+	ProductDeletions       = DeletionEntity{Name: "products", TableName: "products"}
+	CustomerDeletions      = DeletionEntity{Name: "customers", TableName: "customers"}
+	SupplierDeletions      = DeletionEntity{Name: "suppliers", TableName: "suppliers"}
+	AddressDeletions       = DeletionEntity{Name: "addresses", TableName: "addresses"}
+	SalesDocumentDeletions = DeletionEntity{Name: "salesDocuments", TableName: "invoices"}
+)
+
+//Tombstone tells that a record was deleted, so the mirrors should remove it
+type Tombstone struct {
+	Entity    string //DeletionEntity.Name
+	ID        int    //id of the deleted record, e.g. productID
+	DeletedAt int64  //unix timestamp of the deletion
+	DeletedBy string //username of the user who deleted the record
+	LogID     int
+}
+
+//TombstoneSink receives the deletions, the deletes should be idempotent as the deletions close to the checkpoint
+//and the deletions of a failed run are given again in the next run
+type TombstoneSink interface {
+	Delete(ctx context.Context, tombstones []Tombstone) error
+}
+
+//TombstoneSinkFunc allows using ordinary functions as TombstoneSink
+type TombstoneSinkFunc func(ctx context.Context, tombstones []Tombstone) error
+
+//Delete TombstoneSink interface implementation
+func (tsf TombstoneSinkFunc) Delete(ctx context.Context, tombstones []Tombstone) error {
+	return tsf(ctx, tombstones)
+}
+
+//DeletionFeedSettings configures DeletionFeed
+type DeletionFeedSettings struct {
+	CheckpointStore sharedCommon.CheckpointStore //sharedCommon.NewInMemoryCheckpointStore if not set
+	PageSize        int                          //records count of one getUserOperationsLog call, sharedCommon.MaxCountPerBulkRequestItem if not set
+	SafetyWindow    time.Duration                //sharedCommon.DefaultSyncSafetyWindow if not set
+}
+
+//DeletionResult is the outcome of a run of one entity
+type DeletionResult struct {
+	Entity             string
+	DeletedCount       int
+	PreviousCheckpoint sharedCommon.Checkpoint
+	Checkpoint         sharedCommon.Checkpoint //equals PreviousCheckpoint if the run failed
+}
+
+//DeletionFeed pages through the delete operations of the operations log since the checkpoint of the previous run
+//and gives them to TombstoneSink. The checkpoint is kept the same way as in sharedCommon.DeltaSync, it's saved once
+//per run after the sink accepted all deletions of the run.
+type DeletionFeed struct {
+	manager     Manager
+	settings    DeletionFeedSettings
+	sink        TombstoneSink
+	entityLocks map[string]*sync.Mutex
+	lock        sync.Mutex
+	timeNow     func() time.Time
+}
+
+//NewDeletionFeed creates DeletionFeed which reads the operations log with the manager, e.g. Client
+func NewDeletionFeed(manager Manager, settings DeletionFeedSettings, sink TombstoneSink) *DeletionFeed {
+	if settings.CheckpointStore == nil {
+		settings.CheckpointStore = sharedCommon.NewInMemoryCheckpointStore()
+	}
+	if settings.PageSize <= 0 || settings.PageSize > sharedCommon.MaxCountPerBulkRequestItem {
+		settings.PageSize = sharedCommon.MaxCountPerBulkRequestItem
+	}
+	if settings.SafetyWindow <= 0 {
+		settings.SafetyWindow = sharedCommon.DefaultSyncSafetyWindow
+	}
+
+	return &DeletionFeed{
+		manager:     manager,
+		settings:    settings,
+		sink:        sink,
+		entityLocks: map[string]*sync.Mutex{},
+		timeNow:     time.Now,
+	}
+}
+
+//RunAll reads the deletions of the entities one after another, a failed entity doesn't stop the others, the errors are joined
+func (df *DeletionFeed) RunAll(ctx context.Context, entities ...DeletionEntity) ([]DeletionResult, error) {
+	results := make([]DeletionResult, 0, len(entities))
+	var errs []error
+	for _, entity := range entities {
+		result, err := df.Run(ctx, entity)
+		results = append(results, result)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+//Run gives the deletions of the entity since its checkpoint to the sink, runs of the same entity don't overlap
+func (df *DeletionFeed) Run(ctx context.Context, entity DeletionEntity) (DeletionResult, error) {
+	entityLock := df.getEntityLock(entity.Name)
+	entityLock.Lock()
+	defer entityLock.Unlock()
+
+	result := DeletionResult{Entity: entity.Name}
+	checkpointKey := entity.Name + DeletionCheckpointSuffix
+
+	previousCheckpoint, found, err := df.settings.CheckpointStore.LoadCheckpoint(ctx, checkpointKey)
+	if err != nil {
+		return result, fmt.Errorf("failed to load the checkpoint of %s deletions: %w", entity.Name, err)
+	}
+	if !found {
+		previousCheckpoint = sharedCommon.Checkpoint{Entity: checkpointKey}
+	}
+	result.PreviousCheckpoint = previousCheckpoint
+	result.Checkpoint = previousCheckpoint
+
+	startTime := df.timeNow()
+	maxTimestamp, deletedCount, err := df.readDeletions(ctx, entity, previousCheckpoint)
+	result.DeletedCount = deletedCount
+	if err != nil {
+		return result, fmt.Errorf("failed to read %s deletions: %w", entity.Name, err)
+	}
+
+	checkpoint := previousCheckpoint.Advance(maxTimestamp, startTime, df.settings.SafetyWindow)
+	if err := df.settings.CheckpointStore.SaveCheckpoint(ctx, checkpoint); err != nil {
+		return result, fmt.Errorf("failed to save the checkpoint of %s deletions: %w", entity.Name, err)
+	}
+	result.Checkpoint = checkpoint
+
+	return result, nil
+}
+
+//readDeletions pages through the operations log and gives every page to the sink, it returns the highest timestamp
+//of all the read log entries
+func (df *DeletionFeed) readDeletions(ctx context.Context, entity DeletionEntity, checkpoint sharedCommon.Checkpoint) (int64, int, error) {
+	filters := map[string]string{
+		"tableName":     entity.TableName,
+		"recordsOnPage": strconv.Itoa(df.settings.PageSize),
+	}
+	if checkpoint.ChangedSince > 0 {
+		filters["addedFrom"] = strconv.FormatInt(checkpoint.ChangedSince, 10)
+	}
+
+	var maxTimestamp int64
+	deletedCount := 0
+	readCount := 0
+	for pageNo := 1; ; pageNo++ {
+		if ctx.Err() != nil {
+			return maxTimestamp, deletedCount, ctx.Err()
+		}
+
+		filters["pageNo"] = strconv.Itoa(pageNo)
+		resp, err := df.manager.GetUserOperationsLog(ctx, filters)
+		if err != nil {
+			return maxTimestamp, deletedCount, err
+		}
+
+		tombstones := make([]Tombstone, 0, len(resp.OperationLogs))
+		for _, operationLog := range resp.OperationLogs {
+			//the checkpoint moves past the other operations too, otherwise they are read again on every run
+			if int64(operationLog.Timestamp) > maxTimestamp {
+				maxTimestamp = int64(operationLog.Timestamp)
+			}
+			if operationLog.Operation != DeleteOperation || operationLog.TableName != entity.TableName {
+				continue
+			}
+
+			tombstone := Tombstone{
+				Entity:    entity.Name,
+				ID:        operationLog.ItemID,
+				DeletedAt: int64(operationLog.Timestamp),
+				DeletedBy: operationLog.Username,
+				LogID:     operationLog.LogID,
+			}
+			tombstones = append(tombstones, tombstone)
+		}
+
+		if len(tombstones) > 0 {
+			if err := df.sink.Delete(ctx, tombstones); err != nil {
+				return maxTimestamp, deletedCount, err
+			}
+			deletedCount += len(tombstones)
+		}
+
+		readCount += len(resp.OperationLogs)
+		//the records total is a string for this API method, the page size is used if it's not given
+		recordsTotal, err := strconv.Atoi(resp.Status.RecordsTotal)
+		if len(resp.OperationLogs) < df.settings.PageSize || (err == nil && readCount >= recordsTotal) {
+			return maxTimestamp, deletedCount, nil
+		}
+	}
+}
+
+func (df *DeletionFeed) getEntityLock(entity string) *sync.Mutex {
+	df.lock.Lock()
+	defer df.lock.Unlock()
+
+	entityLock, ok := df.entityLocks[entity]
+	if !ok {
+		entityLock = &sync.Mutex{}
+		df.entityLocks[entity] = entityLock
+	}
+
+	return entityLock
+}
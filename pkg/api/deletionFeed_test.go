@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"errors"
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/erply/api-go-wrapper/pkg/api/erplytest"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type tombstoneSinkMock struct {
+	tombstones []Tombstone
+	calls      int
+	err        error
+}
+
+func (tsm *tombstoneSinkMock) Delete(ctx context.Context, tombstones []Tombstone) error {
+	if tsm.err != nil {
+		return tsm.err
+	}
+	tsm.calls++
+	tsm.tombstones = append(tsm.tombstones, tombstones...)
+
+	return nil
+}
+
+func (tsm *tombstoneSinkMock) ids() []int {
+	ids := make([]int, 0, len(tsm.tombstones))
+	for _, tombstone := range tsm.tombstones {
+		ids = append(ids, tombstone.ID)
+	}
+
+	return ids
+}
+
+func newDeletionFeedTestClient(t *testing.T) (*erplytest.Server, *Client) {
+	srv := erplytest.NewServer()
+	cli, err := NewClientWithURL("somesess", "someclient", "", srv.URL, nil, nil)
+	assert.NoError(t, err)
+
+	return srv, cli
+}
+
+func deleteProducts(t *testing.T, cli *Client, ids ...int) {
+	for _, id := range ids {
+		assert.NoError(t, cli.ProductManager.DeleteProduct(context.Background(), map[string]string{"productID": strconv.Itoa(id)}))
+	}
+}
+
+func TestDeletionFeed(t *testing.T) {
+	srv, cli := newDeletionFeedTestClient(t)
+	defer srv.Close()
+
+	for id := 1; id <= 5; id++ {
+		srv.AddRecords(erplytest.EntityProducts, erplytest.Record{"productID": id})
+	}
+	srv.AddRecords(erplytest.EntityCustomers, erplytest.Record{"customerID": 7})
+	deleteProducts(t, cli, 1, 2, 3)
+	assert.NoError(t, cli.CustomerManager.DeleteCustomer(context.Background(), map[string]string{"customerID": "7"}))
+
+	store := sharedCommon.NewInMemoryCheckpointStore()
+	sink := &tombstoneSinkMock{}
+	feed := NewDeletionFeed(cli, DeletionFeedSettings{CheckpointStore: store, PageSize: 2}, sink)
+	feed.timeNow = func() time.Time {
+		return time.Now().Add(time.Hour)
+	}
+
+	res, err := feed.Run(context.Background(), ProductDeletions)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, res.DeletedCount)
+	assert.Equal(t, []int{1, 2, 3}, sink.ids())
+	assert.Equal(t, 2, sink.calls)
+	assert.Equal(t, "products", sink.tombstones[0].Entity)
+	assert.Equal(t, erplytest.OperationsLogUsername, sink.tombstones[0].DeletedBy)
+	assert.Equal(t, sink.tombstones[2].DeletedAt, res.Checkpoint.ChangedSince)
+	assert.Equal(t, "products:deletions", res.Checkpoint.Entity)
+	assert.Equal(t, 2, srv.RequestsCount("getUserOperationsLog"))
+
+	checkpoint, found, err := store.LoadCheckpoint(context.Background(), "products:deletions")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, res.Checkpoint, checkpoint)
+
+	//the next run starts from the checkpoint, the deletions of the same second are given again
+	deleteProducts(t, cli, 4)
+	sink.tombstones = nil
+
+	res, err = feed.Run(context.Background(), ProductDeletions)
+	assert.NoError(t, err)
+	assert.Contains(t, sink.ids(), 4)
+	for _, req := range srv.Requests() {
+		if req.Method == "getUserOperationsLog" && req.Filters["pageNo"] == "1" && req.Filters["addedFrom"] != "" {
+			assert.Equal(t, "products", req.Filters["tableName"])
+			assert.Equal(t, strconv.FormatInt(checkpoint.ChangedSince, 10), req.Filters["addedFrom"])
+		}
+	}
+
+	sink.tombstones = nil
+	results, err := feed.RunAll(context.Background(), CustomerDeletions, SalesDocumentDeletions)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, []int{7}, sink.ids())
+	assert.Equal(t, "customers", sink.tombstones[0].Entity)
+	assert.Equal(t, 0, results[1].DeletedCount)
+}
+
+func TestDeletionFeedErrors(t *testing.T) {
+	srv, cli := newDeletionFeedTestClient(t)
+	defer srv.Close()
+
+	srv.AddRecords(erplytest.EntityProducts, erplytest.Record{"productID": 1})
+	deleteProducts(t, cli, 1)
+
+	store := sharedCommon.NewInMemoryCheckpointStore()
+	assert.NoError(t, store.SaveCheckpoint(context.Background(), sharedCommon.Checkpoint{Entity: "products:deletions", ChangedSince: 1}))
+
+	sink := &tombstoneSinkMock{err: errors.New("some sink error")}
+	feed := NewDeletionFeed(cli, DeletionFeedSettings{CheckpointStore: store}, sink)
+
+	res, err := feed.Run(context.Background(), ProductDeletions)
+	assert.EqualError(t, err, "failed to read products deletions: some sink error")
+	assert.Equal(t, int64(1), res.Checkpoint.ChangedSince)
+
+	sink.err = nil
+	srv.FailNext("getUserOperationsLog", sharedCommon.InvalidValue, 1)
+	_, err = feed.Run(context.Background(), ProductDeletions)
+	assert.Error(t, err)
+	var erplyErr *sharedCommon.ErplyError
+	assert.True(t, errors.As(err, &erplyErr))
+
+	checkpoint, _, err := store.LoadCheckpoint(context.Background(), "products:deletions")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), checkpoint.ChangedSince)
+	assert.Empty(t, sink.tombstones)
+}
+
+func TestDeletionFeedCheckpointCoversOtherOperations(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"status":{"request":"getUserOperationsLog","responseStatus":"ok","recordsTotal":"3"},"records":[
+			{"logID":1,"timestamp":100,"tableName":"products","itemID":1,"operation":"delete"},
+			{"logID":2,"timestamp":200,"tableName":"products","itemID":2,"operation":"update"},
+			{"logID":3,"timestamp":300,"tableName":"products","itemID":3,"operation":"add"}
+		]}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	cli, err := NewClientWithURL("somesess", "someclient", "", srv.URL, nil, nil)
+	assert.NoError(t, err)
+
+	sink := &tombstoneSinkMock{}
+	feed := NewDeletionFeed(cli, DeletionFeedSettings{CheckpointStore: sharedCommon.NewInMemoryCheckpointStore()}, sink)
+	feed.timeNow = func() time.Time {
+		return time.Unix(10000, 0)
+	}
+
+	res, err := feed.Run(context.Background(), ProductDeletions)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, sink.ids())
+	assert.Equal(t, int64(300), res.Checkpoint.ChangedSince)
+}
@@ -0,0 +1,28 @@
+package documents
+
+import (
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"time"
+)
+
+//PurchaseDocumentFilter is a typed filter of getPurchaseDocuments
+type PurchaseDocumentFilter struct {
+	ID                    int       `filter:"id"`
+	IDs                   []int     `filter:"ids"`
+	Type                  string    `filter:"type"`
+	Number                string    `filter:"number"`
+	SupplierID            int       `filter:"supplierID"`
+	WarehouseID           int       `filter:"warehouseID"`
+	Confirmed             *bool     `filter:"confirmed"`
+	DateFrom              time.Time `filter:"dateFrom,date"`
+	DateTo                time.Time `filter:"dateTo,date"`
+	ChangedSince          time.Time `filter:"changedSince"`
+	GetRowsForAllInvoices bool      `filter:"getRowsForAllInvoices"`
+	sharedCommon.Paging
+	Extra map[string]string `filter:",extra"` //parameters which have no field
+}
+
+//ToFilters sharedCommon.Filter interface implementation
+func (pdf PurchaseDocumentFilter) ToFilters() (map[string]string, error) {
+	return sharedCommon.EncodeFilters(pdf)
+}
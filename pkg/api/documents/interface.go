@@ -5,4 +5,6 @@ import "context"
 type Manager interface {
 	GetPurchaseDocuments(ctx context.Context, filters map[string]string) ([]PurchaseDocument, error)
 	GetPurchaseDocumentsBulk(ctx context.Context, bulkRequest []map[string]interface{}, baseFilters map[string]string) (GetPurchaseDocumentResponseBulk, error)
+	GetPurchaseDocumentsWithFilter(ctx context.Context, filter PurchaseDocumentFilter) ([]PurchaseDocument, error)
+	GetPurchaseDocumentsBulkWithFilters(ctx context.Context, filters []PurchaseDocumentFilter, baseFilters map[string]string) (GetPurchaseDocumentResponseBulk, error)
 }
@@ -2,66 +2,39 @@ package documents
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"github.com/erply/api-go-wrapper/internal/common"
 	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
-	"io/ioutil"
 )
 
 func (cli *Client) GetPurchaseDocuments(ctx context.Context, filters map[string]string) ([]PurchaseDocument, error) {
-	resp, err := cli.SendRequest(ctx, "getPurchaseDocuments", filters)
+	res, err := common.Call[GetPurchaseDocumentsResponse](ctx, cli.Client, "getPurchaseDocuments", filters)
 	if err != nil {
 		return nil, err
 	}
-	var res GetPurchaseDocumentsResponse
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return []PurchaseDocument{}, err
-	}
-
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("ERPLY API: failed to unmarshal GetPurchaseDocumentsResponse from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 
 	return res.PurchaseDocuments, nil
 }
 
 func (cli *Client) GetPurchaseDocumentsBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetPurchaseDocumentResponseBulk, error) {
-	var bulkResp GetPurchaseDocumentResponseBulk
-	bulkInputs := make([]common.BulkInput, 0, len(bulkFilters))
-	for _, bulkFilterMap := range bulkFilters {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "getPurchaseDocuments",
-			Filters:    bulkFilterMap,
-		})
-	}
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
+	return common.CallBulk[GetPurchaseDocumentResponseBulk](ctx, cli.Client, "getPurchaseDocuments", bulkFilters, baseFilters)
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
+//GetPurchaseDocumentsWithFilter is GetPurchaseDocuments with a typed filter
+func (cli *Client) GetPurchaseDocumentsWithFilter(ctx context.Context, filter PurchaseDocumentFilter) ([]PurchaseDocument, error) {
+	filters, err := filter.ToFilters()
 	if err != nil {
-		return bulkResp, err
+		return nil, err
 	}
 
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal GetPurchaseDocumentResponseBulk from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, sharedCommon.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
+	return cli.GetPurchaseDocuments(ctx, filters)
+}
 
-	for _, bulkItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&bulkItem.Status.Status) {
-			return bulkResp, sharedCommon.NewErplyError(bulkItem.Status.ErrorCode.String(), bulkItem.Status.Request+": "+bulkItem.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-		}
+//GetPurchaseDocumentsBulkWithFilters is GetPurchaseDocumentsBulk with typed filters of the sub-requests
+func (cli *Client) GetPurchaseDocumentsBulkWithFilters(ctx context.Context, filters []PurchaseDocumentFilter, baseFilters map[string]string) (GetPurchaseDocumentResponseBulk, error) {
+	bulkFilters, err := sharedCommon.ToBulkFilters(filters)
+	if err != nil {
+		return GetPurchaseDocumentResponseBulk{}, err
 	}
 
-	return bulkResp, nil
+	return cli.GetPurchaseDocumentsBulk(ctx, bulkFilters, baseFilters)
 }
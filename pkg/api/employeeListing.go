@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+)
+
+type EmployeesListingDataProvider struct {
+	erplyAPI Manager
+}
+
+func NewEmployeesListingDataProvider(erplyClient Manager) *EmployeesListingDataProvider {
+	return &EmployeesListingDataProvider{
+		erplyAPI: erplyClient,
+	}
+}
+
+func (eldp *EmployeesListingDataProvider) Count(ctx context.Context, filters map[string]interface{}) (int, error) {
+	filters["recordsOnPage"] = 1
+	filters["pageNo"] = 1
+
+	resp, err := eldp.erplyAPI.GetEmployeesBulk(ctx, []map[string]interface{}{filters}, map[string]string{})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(resp.BulkItems) == 0 {
+		return 0, nil
+	}
+
+	return resp.BulkItems[0].Status.RecordsTotal, nil
+}
+
+func (eldp *EmployeesListingDataProvider) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	resp, err := eldp.erplyAPI.GetEmployeesBulk(ctx, bulkFilters, map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	for _, bulkItem := range resp.BulkItems {
+		for i := range bulkItem.Employees {
+			callback(bulkItem.Employees[i])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,537 @@
+package erplytest
+
+import (
+	"fmt"
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"strconv"
+	"strings"
+)
+
+//names of the entities which are kept by the server
+const (
+	EntityProducts            = "products"
+	EntityCustomers           = "customers"
+	EntityWarehouses          = "warehouses"
+	EntitySalesDocuments      = "salesDocuments"
+	EntitySupplierPriceLists  = "supplierPriceLists"
+	EntityProductsInPriceList = "productsInPriceList"
+)
+
+//entityStore keeps the records of one entity, the values given in save requests are converted to the types
+//which the models of the library expect, other values are kept as strings
+type entityStore struct {
+	idField      string
+	intFields    map[string]bool
+	floatFields  map[string]bool
+	filterFields []string
+	records      map[int]Record
+	lastID       int
+	//stringID is set for the entities which models have the id as string, e.g. warehouse.Warehouse
+	stringID bool
+	//tableName is the table of the deleted records in the operations log
+	tableName string
+}
+
+func newEntityStore(idField string, intFields, floatFields, filterFields []string) *entityStore {
+	es := &entityStore{
+		idField:      idField,
+		intFields:    map[string]bool{idField: true, "added": true, "lastModified": true},
+		floatFields:  map[string]bool{},
+		filterFields: append([]string{idField}, filterFields...),
+		records:      map[int]Record{},
+	}
+	for _, field := range intFields {
+		es.intFields[field] = true
+	}
+	for _, field := range floatFields {
+		es.floatFields[field] = true
+	}
+
+	return es
+}
+
+func (s *Server) registerEntities() {
+	s.entities[EntityProducts] = newEntityStore(
+		"productID",
+		[]string{"active", "groupID", "categoryID", "unitID", "supplierID", "brandID", "vatrateID", "parentProductID", "priorityGroupID"},
+		[]string{"price", "cost", "netWeight", "grossWeight", "volume"},
+		[]string{"code", "code2", "active", "groupID", "categoryID", "supplierID", "type"},
+	)
+	s.entities[EntityCustomers] = newEntityStore(
+		"customerID",
+		[]string{"id", "groupID", "payerID", "paymentDays", "credit", "companyTypeID", "emailEnabled", "taxExempt", "salesBlocked"},
+		nil,
+		[]string{"code", "email", "groupID", "customerType"},
+	)
+	s.entities[EntityWarehouses] = newEntityStore(
+		"warehouseID",
+		[]string{"addressID", "usesLocalQuickButtons", "defaultCustomerGroupID", "isOfflineInventory"},
+		nil,
+		[]string{"code"},
+	)
+	s.entities[EntityWarehouses].stringID = true
+	s.entities[EntitySalesDocuments] = newEntityStore(
+		"id",
+		[]string{"warehouseID", "clientID", "customerID", "payerID", "addressID", "contactID", "employeeID", "pointOfSaleID", "projectID", "paymentTypeID"},
+		[]string{"netTotal", "vatTotal", "rounding", "total"},
+		[]string{"type", "number", "clientID", "warehouseID", "confirmed"},
+	)
+	s.entities[EntitySupplierPriceLists] = newEntityStore(
+		"supplierPriceListID",
+		[]string{"supplierID"},
+		nil,
+		[]string{"supplierID", "active"},
+	)
+	s.entities[EntityProductsInPriceList] = newEntityStore(
+		"priceListProductID",
+		[]string{"priceListID", "productID", "amount", "subsidyTypeID", "page", "forecastUnits"},
+		[]string{"price", "subsidy"},
+		[]string{"priceListID", "productID"},
+	)
+	s.entities[EntityProducts].tableName = "products"
+	s.entities[EntityCustomers].tableName = "customers"
+	s.entities[EntitySalesDocuments].tableName = "invoices"
+
+	s.handlers["getProducts"] = s.getHandler(EntityProducts, "productIDs")
+	s.handlers["saveProduct"] = s.saveHandler(EntityProducts, func(rec Record) interface{} {
+		return Record{"productID": rec["productID"]}
+	})
+	s.handlers["deleteProduct"] = s.deleteHandler(EntityProducts)
+
+	s.handlers["getCustomers"] = s.getHandler(EntityCustomers, "customerIDs")
+	s.handlers["saveCustomer"] = s.saveHandler(EntityCustomers, func(rec Record) interface{} {
+		return Record{"clientID": rec["customerID"], "customerID": rec["customerID"], "alreadyExists": false}
+	})
+	s.handlers["deleteCustomer"] = s.deleteHandler(EntityCustomers)
+
+	s.handlers["getWarehouses"] = s.getHandler(EntityWarehouses, "warehouseIDs")
+
+	s.handlers["getSalesDocuments"] = s.getHandler(EntitySalesDocuments, "ids")
+	s.handlers["saveSalesDocument"] = s.saveSalesDocument
+	s.handlers["deleteSalesDocument"] = s.deleteHandler(EntitySalesDocuments)
+
+	s.handlers["getSupplierPriceLists"] = s.getHandler(EntitySupplierPriceLists, "supplierPriceListIDs")
+	s.handlers["saveSupplierPriceList"] = s.saveHandler(EntitySupplierPriceLists, func(rec Record) interface{} {
+		return Record{"supplierPriceListID": rec["supplierPriceListID"]}
+	})
+	s.handlers["getProductsInPriceList"] = s.getHandler(EntityProductsInPriceList, "priceListProductIDs")
+
+	s.handlers[getUserOperationsLogMethod] = s.getUserOperationsLog
+}
+
+//AddRecords stores the records of the entity as they are, the records without id get the next free id
+func (s *Server) AddRecords(entity string, records ...Record) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	es := s.mustGetEntity(entity)
+	now := int(s.timeNow().Unix())
+	for _, rec := range records {
+		stored := rec.copy()
+		if _, ok := stored["lastModified"]; !ok {
+			stored["lastModified"] = now
+		}
+		if _, ok := stored["added"]; !ok {
+			stored["added"] = now
+		}
+		es.put(stored)
+	}
+}
+
+//Records gives the records of the entity ordered by id, e.g. to check the saved data
+func (s *Server) Records(entity string) []Record {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.mustGetEntity(entity).all()
+}
+
+//Record gives the record of the entity by id
+func (s *Server) Record(entity string, id int) (Record, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	rec, ok := s.mustGetEntity(entity).records[id]
+	return rec.copy(), ok
+}
+
+func (r Record) copy() Record {
+	if r == nil {
+		return nil
+	}
+
+	res := make(Record, len(r))
+	for k, v := range r {
+		res[k] = v
+	}
+
+	return res
+}
+
+func (s *Server) mustGetEntity(entity string) *entityStore {
+	es, ok := s.entities[entity]
+	if !ok {
+		panic(fmt.Sprintf("erplytest: unknown entity %s", entity))
+	}
+
+	return es
+}
+
+//getHandler lists the records of the entity, they are filtered by the id, the comma separated list of ids in idsFilter,
+//the filter fields of the entity and changedSince
+func (s *Server) getHandler(entity, idsFilter string) MethodHandler {
+	return func(filters map[string]string) ([]interface{}, int, error) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		es := s.entities[entity]
+
+		var ids map[int]bool
+		if rawIDs, ok := filters[idsFilter]; ok {
+			ids = map[int]bool{}
+			for _, rawID := range strings.Split(rawIDs, ",") {
+				id, err := strconv.Atoi(strings.TrimSpace(rawID))
+				if err != nil {
+					return nil, 0, NewAPIError(sharedCommon.InvalidValue, idsFilter)
+				}
+				ids[id] = true
+			}
+		}
+
+		changedSince := 0
+		if rawChangedSince, ok := filters["changedSince"]; ok {
+			var err error
+			changedSince, err = strconv.Atoi(rawChangedSince)
+			if err != nil {
+				return nil, 0, NewAPIError(sharedCommon.InvalidValue, "changedSince")
+			}
+		}
+
+		matched := make([]Record, 0, len(es.records))
+		for _, rec := range es.all() {
+			if ids != nil && !ids[toInt(rec[es.idField])] {
+				continue
+			}
+			if changedSince > 0 && toInt(rec["lastModified"]) < changedSince {
+				continue
+			}
+			if !es.matches(rec, filters) {
+				continue
+			}
+			matched = append(matched, rec)
+		}
+
+		page, err := Page(matched, filters)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return page, len(matched), nil
+	}
+}
+
+//saveHandler creates a record if the id is not given or updates the existing record
+func (s *Server) saveHandler(entity string, toResult func(rec Record) interface{}) MethodHandler {
+	return func(filters map[string]string) ([]interface{}, int, error) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		rec, err := s.upsert(s.entities[entity], filters)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return []interface{}{toResult(rec)}, 1, nil
+	}
+}
+
+func (s *Server) deleteHandler(entity string) MethodHandler {
+	return func(filters map[string]string) ([]interface{}, int, error) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		es := s.entities[entity]
+		rawID, ok := filters[es.idField]
+		if !ok {
+			return nil, 0, NewAPIError(sharedCommon.RequiredParamMissing, es.idField)
+		}
+
+		id, err := strconv.Atoi(rawID)
+		if _, exists := es.records[id]; err != nil || !exists {
+			return nil, 0, NewAPIError(sharedCommon.InvalidClassifierID, es.idField)
+		}
+		delete(es.records, id)
+		s.logDeletion(es.tableName, id)
+
+		return nil, 0, nil
+	}
+}
+
+//logDeletion adds a delete operation to the operations log which is given by getUserOperationsLog
+func (s *Server) logDeletion(tableName string, id int) {
+	if tableName == "" {
+		return
+	}
+
+	s.operationsLog = append(s.operationsLog, Record{
+		"logID":     len(s.operationsLog) + 1,
+		"username":  OperationsLogUsername,
+		"timestamp": int(s.timeNow().Unix()),
+		"tableName": tableName,
+		"itemID":    id,
+		"operation": "delete",
+	})
+}
+
+//getUserOperationsLog lists the delete operations ordered by logID, they are filtered by tableName,
+//addedFrom and addedTo which are unix timestamps
+func (s *Server) getUserOperationsLog(filters map[string]string) ([]interface{}, int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	timeFilters := map[string]int{}
+	for _, filter := range []string{"addedFrom", "addedTo"} {
+		rawValue, ok := filters[filter]
+		if !ok {
+			continue
+		}
+		value, err := strconv.Atoi(rawValue)
+		if err != nil {
+			return nil, 0, NewAPIError(sharedCommon.InvalidValue, filter)
+		}
+		timeFilters[filter] = value
+	}
+
+	matched := make([]Record, 0, len(s.operationsLog))
+	for _, rec := range s.operationsLog {
+		if tableName, ok := filters["tableName"]; ok && rec["tableName"] != tableName {
+			continue
+		}
+		if addedFrom, ok := timeFilters["addedFrom"]; ok && toInt(rec["timestamp"]) < addedFrom {
+			continue
+		}
+		if addedTo, ok := timeFilters["addedTo"]; ok && toInt(rec["timestamp"]) > addedTo {
+			continue
+		}
+		matched = append(matched, rec.copy())
+	}
+
+	page, err := Page(matched, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return page, len(matched), nil
+}
+
+//saveSalesDocument stores the document with its rows given as productID1, amount1, price1 etc.
+func (s *Server) saveSalesDocument(filters map[string]string) ([]interface{}, int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	docFilters := make(map[string]string, len(filters))
+	rowFilters := map[int]map[string]string{}
+	for k, v := range filters {
+		field, index := splitIndexedParam(k)
+		if index == 0 {
+			docFilters[k] = v
+			continue
+		}
+		if rowFilters[index] == nil {
+			rowFilters[index] = map[string]string{}
+		}
+		rowFilters[index][field] = v
+	}
+
+	es := s.entities[EntitySalesDocuments]
+	if _, ok := docFilters["type"]; !ok {
+		if _, isUpdate := docFilters[es.idField]; !isUpdate {
+			docFilters["type"] = "INVWAYBILL"
+		}
+	}
+
+	rec, err := s.upsert(es, docFilters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(rowFilters) > 0 {
+		rows := make([]interface{}, 0, len(rowFilters))
+		resultRows := make([]interface{}, 0, len(rowFilters))
+		net := 0.0
+		for index := 1; index <= len(rowFilters); index++ {
+			row, ok := rowFilters[index]
+			if !ok {
+				return nil, 0, NewAPIError(sharedCommon.WrongRowsSequence, fmt.Sprintf("productID%d", index))
+			}
+
+			amount, _ := strconv.ParseFloat(row["amount"], 64)
+			price, _ := strconv.ParseFloat(row["price"], 64)
+			net += amount * price
+
+			//the rows of sales documents have only string values
+			storedRow := Record{"rowID": strconv.Itoa(index), "stableRowID": strconv.Itoa(index)}
+			for k, v := range row {
+				storedRow[k] = v
+			}
+			rows = append(rows, storedRow)
+			resultRows = append(resultRows, Record{
+				"rowID":       index,
+				"stableRowID": index,
+				"productID":   toInt(row["productID"]),
+				"amount":      row["amount"],
+			})
+		}
+		rec["rows"] = rows
+		rec["netTotal"] = net
+		rec["total"] = net
+
+		return []interface{}{Record{
+			"invoiceID": rec["id"],
+			"invoiceNo": rec["number"],
+			"net":       net,
+			"total":     net,
+			"rows":      resultRows,
+		}}, 1, nil
+	}
+
+	return []interface{}{Record{"invoiceID": rec["id"], "invoiceNo": rec["number"]}}, 1, nil
+}
+
+//upsert creates or updates a record from the filters of a save request
+func (s *Server) upsert(es *entityStore, filters map[string]string) (Record, error) {
+	now := int(s.timeNow().Unix())
+
+	rec := Record{"added": now}
+	if rawID, ok := filters[es.idField]; ok && rawID != "" && rawID != "0" {
+		id, err := strconv.Atoi(rawID)
+		existing, exists := es.records[id]
+		if err != nil || !exists {
+			return nil, NewAPIError(sharedCommon.InvalidClassifierID, es.idField)
+		}
+		rec = existing
+	}
+
+	for k, v := range filters {
+		if isSystemParam(k) || k == es.idField {
+			continue
+		}
+		converted, err := es.convert(k, v)
+		if err != nil {
+			return nil, err
+		}
+		rec[k] = converted
+	}
+	rec["lastModified"] = now
+
+	es.put(rec)
+
+	if es == s.entities[EntitySalesDocuments] {
+		if _, ok := rec["number"]; !ok {
+			rec["number"] = strconv.Itoa(toInt(rec[es.idField]))
+		}
+	}
+
+	return rec, nil
+}
+
+func (es *entityStore) put(rec Record) {
+	id := toInt(rec[es.idField])
+	if id == 0 {
+		es.lastID++
+		id = es.lastID
+	} else if id > es.lastID {
+		es.lastID = id
+	}
+	if es.stringID {
+		rec[es.idField] = strconv.Itoa(id)
+	} else {
+		rec[es.idField] = id
+	}
+	es.records[id] = rec
+}
+
+//all gives copies of the records ordered by id, so they can be encoded while other requests change the store
+func (es *entityStore) all() []Record {
+	res := make([]Record, 0, len(es.records))
+	for _, rec := range es.records {
+		res = append(res, rec.copy())
+	}
+	sortRecords(res, es.idField)
+
+	return res
+}
+
+func (es *entityStore) matches(rec Record, filters map[string]string) bool {
+	for _, field := range es.filterFields {
+		expected, ok := filters[field]
+		if !ok {
+			continue
+		}
+		actual, ok := rec[field]
+		if !ok || fmt.Sprint(actual) != expected {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (es *entityStore) convert(field, value string) (interface{}, error) {
+	switch {
+	case es.intFields[field]:
+		if value == "" {
+			return 0, nil
+		}
+		converted, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, NewAPIError(sharedCommon.InvalidValue, field)
+		}
+		return converted, nil
+	case es.floatFields[field]:
+		if value == "" {
+			return 0.0, nil
+		}
+		converted, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, NewAPIError(sharedCommon.InvalidValue, field)
+		}
+		return converted, nil
+	}
+
+	return value, nil
+}
+
+//isSystemParam tells if the parameter belongs to the request and not to the saved entity
+func isSystemParam(param string) bool {
+	switch param {
+	case requestNameParam, bulkNameParam, bulkIDParam, sessionKeyParam, "clientCode", "partnerKey", "setContentType", "version", "lang":
+		return true
+	}
+
+	return false
+}
+
+//splitIndexedParam splits the parameters of rows like productID1 to the name and the index,
+//the index is 0 for the parameters without it
+func splitIndexedParam(param string) (string, int) {
+	i := len(param)
+	for i > 0 && param[i-1] >= '0' && param[i-1] <= '9' {
+		i--
+	}
+	if i == len(param) || i == 0 {
+		return param, 0
+	}
+
+	index, err := strconv.Atoi(param[i:])
+	if err != nil {
+		return param, 0
+	}
+
+	switch param[:i] {
+	case "productID", "serviceID", "itemName", "vatrateID", "amount", "price", "discount", "rowID", "stableRowID":
+		return param[:i], index
+	}
+
+	return param, 0
+}
@@ -0,0 +1,420 @@
+package erplytest
+
+import (
+	"encoding/json"
+	"fmt"
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//DefaultRecordsOnPage is used if a get request has no recordsOnPage filter
+	DefaultRecordsOnPage = 20
+
+	requestNameParam  = "request"
+	bulkRequestsParam = "requests"
+	bulkNameParam     = "requestName"
+	bulkIDParam       = "requestID"
+	sessionKeyParam   = "sessionKey"
+
+	//getUserOperationsLogMethod gives recordsTotal as a string like the API does
+	getUserOperationsLogMethod = "getUserOperationsLog"
+	//OperationsLogUsername is the username of the operations made through the server
+	OperationsLogUsername = "erplytest"
+)
+
+//Record is an entity stored by the fake server, it's encoded to JSON as is
+type Record map[string]interface{}
+
+//APIError is returned by a MethodHandler to give an error status in the response
+type APIError struct {
+	Code  sharedCommon.ApiError
+	Field string
+}
+
+func (e *APIError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("%s, error field: %s", e.Code.String(), e.Field)
+	}
+	return e.Code.String()
+}
+
+//NewAPIError creates APIError
+func NewAPIError(code sharedCommon.ApiError, field string) *APIError {
+	return &APIError{Code: code, Field: field}
+}
+
+//MethodHandler handles one API method, it gives the records of the response and the total count of the matched records,
+//the filters contain the paging parameters, the records should be paged with Page
+type MethodHandler func(filters map[string]string) (records []interface{}, recordsTotal int, err error)
+
+//ReceivedRequest is a request or a bulk sub-request which the server got
+type ReceivedRequest struct {
+	Method  string
+	Filters map[string]string
+	IsBulk  bool
+}
+
+type injectedFailure struct {
+	code  sharedCommon.ApiError
+	times int
+}
+
+//Server is an in-memory fake of the Erply JSON API for integration tests, it keeps products, customers, warehouses,
+//sales documents and price lists, logs their deletions, supports bulk requests, paging and error statuses.
+//Point the client to it with common.NewClientWithURL(sessionKey, clientCode, "", server.URL, nil, nil).
+type Server struct {
+	*httptest.Server
+
+	//SessionKey is required in every request if set, otherwise APISessionExpired error is given
+	SessionKey string
+
+	entities      map[string]*entityStore
+	operationsLog []Record
+	handlers      map[string]MethodHandler
+	failures      map[string]*injectedFailure
+	requests      []ReceivedRequest
+	timeNow       func() time.Time
+	lock          sync.Mutex
+	callsLock     sync.Mutex
+}
+
+//NewServer starts the fake server with no records, close it after usage
+func NewServer() *Server {
+	s := &Server{
+		entities: map[string]*entityStore{},
+		handlers: map[string]MethodHandler{},
+		failures: map[string]*injectedFailure{},
+		timeNow:  time.Now,
+	}
+	s.registerEntities()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+//HandleMethod registers a handler for an API method, it replaces the built-in handler of the method if there is one
+func (s *Server) HandleMethod(method string, handler MethodHandler) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.handlers[method] = handler
+}
+
+//FailNext makes the next calls of the API method give the error code, times is the count of failed calls,
+//use a negative value to fail all calls until ClearFailures is called
+func (s *Server) FailNext(method string, code sharedCommon.ApiError, times int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.failures[method] = &injectedFailure{code: code, times: times}
+}
+
+//ClearFailures removes all failures which were set by FailNext
+func (s *Server) ClearFailures() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.failures = map[string]*injectedFailure{}
+}
+
+//Requests gives all requests and bulk sub-requests which the server got
+func (s *Server) Requests() []ReceivedRequest {
+	s.callsLock.Lock()
+	defer s.callsLock.Unlock()
+
+	return append([]ReceivedRequest{}, s.requests...)
+}
+
+//RequestsCount gives the count of calls of the API method including bulk sub-requests
+func (s *Server) RequestsCount(method string) int {
+	count := 0
+	for _, req := range s.Requests() {
+		if req.Method == method {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	startTime := s.timeNow()
+	if err := r.ParseForm(); err != nil {
+		s.writeJSON(w, response{Status: s.errorStatus("", NewAPIError(sharedCommon.MalformedRequest, ""), startTime)})
+		return
+	}
+
+	filters := make(map[string]string, len(r.Form))
+	for k := range r.Form {
+		filters[k] = r.Form.Get(k)
+	}
+
+	if s.SessionKey != "" && filters[sessionKeyParam] != s.SessionKey {
+		s.writeJSON(w, response{Status: s.errorStatus(filters[requestNameParam], NewAPIError(sharedCommon.APISessionExpired, ""), startTime)})
+		return
+	}
+
+	if bulkRequests, ok := filters[bulkRequestsParam]; ok {
+		s.writeJSON(w, s.handleBulk(bulkRequests, filters, startTime))
+		return
+	}
+
+	method := filters[requestNameParam]
+	s.logRequest(ReceivedRequest{Method: method, Filters: filters})
+
+	records, recordsTotal, err := s.call(method, filters)
+	if err != nil {
+		s.writeResponse(w, response{Status: s.errorStatus(method, err, startTime)})
+		return
+	}
+
+	s.writeResponse(w, response{
+		Status:  s.okStatus(method, recordsTotal, len(records), startTime),
+		Records: records,
+	})
+}
+
+//writeResponse encodes the response, recordsTotal is given as a string for the methods which have it so in the API
+func (s *Server) writeResponse(w http.ResponseWriter, resp response) {
+	if resp.Status.Request != getUserOperationsLogMethod {
+		s.writeJSON(w, resp)
+		return
+	}
+
+	s.writeJSON(w, stringTotalResponse{
+		Status:  stringTotalStatus{Status: resp.Status, RecordsTotal: strconv.Itoa(resp.Status.RecordsTotal)},
+		Records: resp.Records,
+	})
+}
+
+func (s *Server) handleBulk(bulkRequests string, baseFilters map[string]string, startTime time.Time) bulkResponse {
+	decoder := json.NewDecoder(strings.NewReader(bulkRequests))
+	decoder.UseNumber()
+
+	var subRequests []map[string]interface{}
+	if err := decoder.Decode(&subRequests); err != nil {
+		return bulkResponse{Status: s.errorStatus("", NewAPIError(sharedCommon.MalformedRequest, bulkRequestsParam), startTime)}
+	}
+
+	if len(subRequests) > sharedCommon.MaxBulkRequestsCount {
+		return bulkResponse{Status: s.errorStatus("", NewAPIError(sharedCommon.TooManyBulkSubRequests, bulkRequestsParam), startTime)}
+	}
+
+	resp := bulkResponse{
+		Status:    s.okStatus("", 0, 0, startTime),
+		BulkItems: make([]bulkResponseItem, 0, len(subRequests)),
+	}
+	//the id of the document created by the last saveSalesDocument sub-request replaces CURRENT_INVOICE_ID
+	currentInvoiceID := ""
+	var currentInvoiceErr error
+	for _, subRequest := range subRequests {
+		filters := make(map[string]string, len(baseFilters)+len(subRequest))
+		for k, v := range baseFilters {
+			if k == bulkRequestsParam {
+				continue
+			}
+			filters[k] = v
+		}
+		for k, v := range subRequest {
+			filters[k] = fmt.Sprint(v)
+		}
+
+		method := filters[bulkNameParam]
+		s.logRequest(ReceivedRequest{Method: method, Filters: filters, IsBulk: true})
+
+		itemStartTime := s.timeNow()
+		records, recordsTotal, err := s.callWithCurrentInvoice(method, filters, currentInvoiceID, currentInvoiceErr)
+		if method == sharedCommon.SaveSalesDocumentMethod {
+			currentInvoiceID, currentInvoiceErr = getInvoiceID(records), err
+		}
+		item := bulkResponseItem{Records: records}
+		if err != nil {
+			item.Status.Status = s.errorStatus(method, err, itemStartTime)
+		} else {
+			item.Status.Status = s.okStatus(method, recordsTotal, len(records), itemStartTime)
+		}
+		item.Status.RequestName = method
+		item.Status.RequestID = filters[bulkIDParam]
+
+		resp.BulkItems = append(resp.BulkItems, item)
+	}
+
+	return resp
+}
+
+func (s *Server) call(method string, filters map[string]string) ([]interface{}, int, error) {
+	s.lock.Lock()
+	handler, ok := s.handlers[method]
+	failure := s.failures[method]
+	if failure != nil && failure.times != 0 {
+		failure.times--
+		s.lock.Unlock()
+		return nil, 0, NewAPIError(failure.code, "")
+	}
+	s.lock.Unlock()
+
+	if !ok {
+		return nil, 0, NewAPIError(sharedCommon.UnknownApi, requestNameParam)
+	}
+
+	return handler(filters)
+}
+
+//callWithCurrentInvoice calls the method replacing CURRENT_INVOICE_ID with the id of the document from the preceding
+//saveSalesDocument sub-request, the sub-request fails if that document wasn't created
+func (s *Server) callWithCurrentInvoice(method string, filters map[string]string, invoiceID string, invoiceErr error) ([]interface{}, int, error) {
+	for k, v := range filters {
+		if v != sharedCommon.CurrentInvoiceID {
+			continue
+		}
+
+		if invoiceErr != nil || invoiceID == "" {
+			code := sharedCommon.BulkSubRequestDocumentError
+			if apiErr, ok := invoiceErr.(*APIError); ok && apiErr.Code == sharedCommon.IdenticalRecordExists {
+				code = sharedCommon.BulkSubRequestDuplicateError
+			}
+			return nil, 0, NewAPIError(code, k)
+		}
+		filters[k] = invoiceID
+	}
+
+	return s.call(method, filters)
+}
+
+func getInvoiceID(records []interface{}) string {
+	if len(records) == 0 {
+		return ""
+	}
+	if rec, ok := records[0].(Record); ok {
+		return fmt.Sprint(rec["invoiceID"])
+	}
+
+	return ""
+}
+
+func (s *Server) logRequest(req ReceivedRequest) {
+	s.callsLock.Lock()
+	defer s.callsLock.Unlock()
+
+	s.requests = append(s.requests, req)
+}
+
+type response struct {
+	Status  sharedCommon.Status `json:"status"`
+	Records []interface{}       `json:"records"`
+}
+
+type stringTotalStatus struct {
+	sharedCommon.Status
+	RecordsTotal string `json:"recordsTotal"`
+}
+
+type stringTotalResponse struct {
+	Status  stringTotalStatus `json:"status"`
+	Records []interface{}     `json:"records"`
+}
+
+type bulkResponseItem struct {
+	Status  sharedCommon.StatusBulk `json:"status"`
+	Records []interface{}           `json:"records"`
+}
+
+type bulkResponse struct {
+	Status    sharedCommon.Status `json:"status"`
+	BulkItems []bulkResponseItem  `json:"requests"`
+}
+
+func (s *Server) okStatus(method string, recordsTotal, recordsInResponse int, startTime time.Time) sharedCommon.Status {
+	now := s.timeNow()
+	return sharedCommon.Status{
+		Request:           method,
+		RequestUnixTime:   int(now.Unix()),
+		ResponseStatus:    "ok",
+		GenerationTime:    now.Sub(startTime).Seconds(),
+		RecordsTotal:      recordsTotal,
+		RecordsInResponse: recordsInResponse,
+	}
+}
+
+func (s *Server) errorStatus(method string, err error, startTime time.Time) sharedCommon.Status {
+	status := s.okStatus(method, 0, 0, startTime)
+	status.ResponseStatus = "error"
+
+	if apiErr, ok := err.(*APIError); ok {
+		status.ErrorCode = apiErr.Code
+		status.ErrorField = apiErr.Field
+	} else {
+		status.ErrorCode = sharedCommon.DbError
+	}
+
+	return status
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+//Page gives the page of the records selected by the pageNo and recordsOnPage filters as Erply does,
+//pageNo starts from 1, recordsOnPage is DefaultRecordsOnPage by default and can't be more than 100
+func Page(records []Record, filters map[string]string) ([]interface{}, error) {
+	recordsOnPage := DefaultRecordsOnPage
+	if rawValue, ok := filters["recordsOnPage"]; ok {
+		value, err := strconv.Atoi(rawValue)
+		if err != nil || value < 1 || value > sharedCommon.MaxCountPerBulkRequestItem {
+			return nil, NewAPIError(sharedCommon.InvalidValue, "recordsOnPage")
+		}
+		recordsOnPage = value
+	}
+
+	pageNo := 1
+	if rawValue, ok := filters["pageNo"]; ok {
+		value, err := strconv.Atoi(rawValue)
+		if err != nil || value < 1 {
+			return nil, NewAPIError(sharedCommon.InvalidValue, "pageNo")
+		}
+		pageNo = value
+	}
+
+	res := make([]interface{}, 0, recordsOnPage)
+	for i := (pageNo - 1) * recordsOnPage; i < len(records) && len(res) < recordsOnPage; i++ {
+		res = append(res, records[i])
+	}
+
+	return res, nil
+}
+
+//sortRecords orders records by the id field as the API does by default
+func sortRecords(records []Record, idField string) {
+	sort.Slice(records, func(i, j int) bool {
+		return toInt(records[i][idField]) < toInt(records[j][idField])
+	})
+}
+
+func toInt(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case json.Number:
+		i, _ := strconv.Atoi(v.String())
+		return i
+	case string:
+		i, _ := strconv.Atoi(v)
+		return i
+	}
+
+	return 0
+}
@@ -0,0 +1,408 @@
+package erplytest
+
+import (
+	"context"
+	"errors"
+	"github.com/erply/api-go-wrapper/internal/common"
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/erply/api-go-wrapper/pkg/api/customers"
+	"github.com/erply/api-go-wrapper/pkg/api/prices"
+	"github.com/erply/api-go-wrapper/pkg/api/products"
+	"github.com/erply/api-go-wrapper/pkg/api/sales"
+	"github.com/erply/api-go-wrapper/pkg/api/warehouse"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newTestServer() (*Server, *common.Client) {
+	srv := NewServer()
+	srv.SessionKey = "somesess"
+
+	return srv, common.NewClientWithURL("somesess", "someclient", "", srv.URL, nil, nil)
+}
+
+func addTestProducts(srv *Server, count int) {
+	for i := 1; i <= count; i++ {
+		srv.AddRecords(EntityProducts, Record{"productID": i, "code": "code" + string(rune('a'+i%26)), "active": i % 2})
+	}
+}
+
+func TestProductsCRUD(t *testing.T) {
+	srv, cli := newTestServer()
+	defer srv.Close()
+
+	productsCli := products.NewClient(cli)
+	ctx := context.Background()
+
+	saveRes, err := productsCli.SaveProduct(ctx, map[string]string{"code": "001", "name": "Coffee", "price": "2.5", "groupID": "3"})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, 1, saveRes.ProductID)
+
+	_, err = productsCli.SaveProduct(ctx, map[string]string{"productID": "1", "name": "Dark coffee"})
+	assert.NoError(t, err)
+
+	prods, err := productsCli.GetProducts(ctx, map[string]string{"productID": "1"})
+	assert.NoError(t, err)
+	assert.Len(t, prods, 1)
+	assert.Equal(t, "001", prods[0].Code)
+	assert.Equal(t, 2.5, prods[0].Price)
+	assert.Equal(t, uint(3), prods[0].GroupID)
+
+	rec, ok := srv.Record(EntityProducts, 1)
+	assert.True(t, ok)
+	assert.Equal(t, "Dark coffee", rec["name"])
+
+	_, err = productsCli.SaveProduct(ctx, map[string]string{"productID": "100", "name": "Tea"})
+	assertAPIError(t, err, sharedCommon.InvalidClassifierID)
+
+	assert.NoError(t, productsCli.DeleteProduct(ctx, map[string]string{"productID": "1"}))
+	assertAPIError(t, productsCli.DeleteProduct(ctx, map[string]string{"productID": "1"}), sharedCommon.InvalidClassifierID)
+
+	assert.Len(t, srv.Records(EntityProducts), 0)
+}
+
+func TestPagingAndBulk(t *testing.T) {
+	srv, cli := newTestServer()
+	defer srv.Close()
+	addTestProducts(srv, 45)
+
+	productsCli := products.NewClient(cli)
+
+	bulkResp, err := productsCli.GetProductsBulk(
+		context.Background(),
+		[]map[string]interface{}{
+			{"recordsOnPage": 20, "pageNo": 1, "requestID": 1},
+			{"recordsOnPage": 20, "pageNo": 3, "requestID": 2},
+			{"productIDs": "4,5,6", "active": 1, "requestID": 3},
+		},
+		map[string]string{},
+	)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+
+	assert.Len(t, bulkResp.BulkItems, 3)
+
+	assert.Equal(t, 45, bulkResp.BulkItems[0].Status.RecordsTotal)
+	assert.Equal(t, 20, bulkResp.BulkItems[0].Status.RecordsInResponse)
+	assert.Equal(t, "1", bulkResp.BulkItems[0].Status.RequestID)
+	assert.Equal(t, "getProducts", bulkResp.BulkItems[0].Status.RequestName)
+	assert.Equal(t, 1, bulkResp.BulkItems[0].Products[0].ProductID)
+
+	assert.Len(t, bulkResp.BulkItems[1].Products, 5)
+	assert.Equal(t, 41, bulkResp.BulkItems[1].Products[0].ProductID)
+
+	assert.Len(t, bulkResp.BulkItems[2].Products, 1)
+	assert.Equal(t, 5, bulkResp.BulkItems[2].Products[0].ProductID)
+
+	assert.Equal(t, 3, srv.RequestsCount("getProducts"))
+
+	_, err = productsCli.GetProducts(context.Background(), map[string]string{"recordsOnPage": "1000"})
+	assertAPIError(t, err, sharedCommon.InvalidValue)
+}
+
+func TestTypedFilters(t *testing.T) {
+	srv, cli := newTestServer()
+	defer srv.Close()
+	addTestProducts(srv, 10)
+
+	productsCli := products.NewClient(cli)
+	active := false
+
+	prods, err := productsCli.GetProductsWithFilter(context.Background(), products.ProductFilter{
+		ProductIDs: []int{2, 3, 4},
+		Active:     &active,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, prods, 2)
+
+	reqs := srv.Requests()
+	assert.Equal(t, "2,3,4", reqs[len(reqs)-1].Filters["productIDs"])
+	assert.Equal(t, "0", reqs[len(reqs)-1].Filters["active"])
+
+	bulkResp, err := productsCli.GetProductsBulkWithFilters(
+		context.Background(),
+		[]products.ProductFilter{
+			{Paging: sharedCommon.Paging{RecordsOnPage: 5, PageNo: 2}},
+			{ProductID: 7},
+		},
+		map[string]string{},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, bulkResp.BulkItems, 2)
+	assert.Equal(t, 6, bulkResp.BulkItems[0].Products[0].ProductID)
+	assert.Len(t, bulkResp.BulkItems[1].Products, 1)
+	assert.Equal(t, 7, bulkResp.BulkItems[1].Products[0].ProductID)
+}
+
+func TestTypedSaveInputs(t *testing.T) {
+	srv, cli := newTestServer()
+	defer srv.Close()
+
+	price := 3.5
+	reports, err := sales.NewClient(cli).SaveSalesDocumentWithInput(context.Background(), sales.SaleDocumentInput{
+		WarehouseID: 1,
+		Rows: []sales.SaleDocumentRowInput{
+			{ProductID: 10, Amount: 2, Price: &price},
+			{ProductID: 11, Amount: 1, Price: &price},
+		},
+		Attributes: []sharedCommon.ObjAttribute{{AttributeName: "source", AttributeType: "text", AttributeValue: "web"}},
+	})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Len(t, reports, 1)
+	assert.Equal(t, 10.5, reports[0].Total)
+	assert.Len(t, reports[0].Rows, 2)
+
+	reqs := srv.Requests()
+	assert.Equal(t, "11", reqs[len(reqs)-1].Filters["productID2"])
+	assert.Equal(t, "source", reqs[len(reqs)-1].Filters["attributeName1"])
+	assert.Equal(t, "web", reqs[len(reqs)-1].Filters["attributeValue1"])
+
+	active := false
+	bulkResp, err := products.NewClient(cli).SaveProductBulkWithInputs(
+		context.Background(),
+		[]products.ProductInput{{Code: "001", Active: &active}, {Code: "002", GroupID: 3}},
+		map[string]string{},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, bulkResp.BulkItems, 2)
+
+	rec, ok := srv.Record(EntityProducts, 1)
+	assert.True(t, ok)
+	assert.Equal(t, 0, rec["active"])
+	rec, ok = srv.Record(EntityProducts, 2)
+	assert.True(t, ok)
+	assert.Equal(t, 3, rec["groupID"])
+}
+
+func TestBulkSubRequestErrors(t *testing.T) {
+	srv, cli := newTestServer()
+	defer srv.Close()
+
+	_, err := common.CallBulkInputs[products.GetProductsResponseBulk](
+		context.Background(),
+		cli,
+		[]common.BulkInput{
+			{MethodName: "getProducts", Filters: map[string]interface{}{}},
+			{MethodName: "getUnknownThings", Filters: map[string]interface{}{}},
+		},
+		map[string]string{},
+	)
+	assertAPIError(t, err, sharedCommon.UnknownApi)
+
+	bulkResp, err := common.CallBulk[products.GetProductsResponseBulk](
+		context.Background(),
+		cli,
+		"getProducts",
+		[]map[string]interface{}{{"pageNo": 1}, {"pageNo": "x"}},
+		map[string]string{},
+	)
+	assertAPIError(t, err, sharedCommon.InvalidValue)
+	bulkErr, ok := sharedCommon.AsBulkError(err)
+	assert.True(t, ok)
+	if ok {
+		assert.Equal(t, []int{1}, bulkErr.FailedIndexes())
+	}
+	assert.Len(t, bulkResp.BulkItems, 2)
+	assert.Equal(t, "ok", bulkResp.BulkItems[0].Status.ResponseStatus)
+	assert.Equal(t, "pageNo", bulkResp.BulkItems[1].Status.ErrorField)
+}
+
+func TestRetryFailedBulkItems(t *testing.T) {
+	srv, cli := newTestServer()
+	defer srv.Close()
+
+	productsCli := products.NewClient(cli)
+	inputs := []map[string]interface{}{
+		{"code": "001"},
+		{"code": "002"},
+		{"productID": 100, "code": "003"},
+		{"code": "004"},
+	}
+	productIDs := make([]int, len(inputs))
+	saveProducts := func(ctx context.Context, items []map[string]interface{}, indexes []int) error {
+		bulkResp, err := productsCli.SaveProductBulk(ctx, items, map[string]string{})
+		for i, bulkItem := range bulkResp.BulkItems {
+			if bulkItem.Status.ResponseStatus == "ok" && len(bulkItem.Products) > 0 {
+				productIDs[indexes[i]] = bulkItem.Products[0].ProductID
+			}
+		}
+		return err
+	}
+
+	srv.FailNext("saveProduct", sharedCommon.HourlyRequestQuota, 2)
+	err := saveProducts(context.Background(), inputs, []int{0, 1, 2, 3})
+	bulkErr, ok := sharedCommon.AsBulkError(err)
+	assert.True(t, ok)
+	if !ok {
+		return
+	}
+	assert.Equal(t, []int{0, 1, 2}, bulkErr.FailedIndexes())
+
+	failedItems, indexes := sharedCommon.FailedBulkItems(inputs, err)
+	assert.Len(t, failedItems, 3)
+	assert.Equal(t, []int{0, 1, 2}, indexes)
+
+	err = sharedCommon.RetryFailedBulkItems(
+		context.Background(),
+		inputs,
+		err,
+		sharedCommon.RetryPolicy{MaxAttempts: 3, RetryableCodes: sharedCommon.DefaultRetryableCodes},
+		func(time.Duration) {},
+		saveProducts,
+	)
+
+	//the item with the wrong productID fails permanently and is not repeated after the first retry
+	bulkErr, ok = sharedCommon.AsBulkError(err)
+	assert.True(t, ok)
+	if !ok {
+		return
+	}
+	assert.Equal(t, []int{2}, bulkErr.FailedIndexes())
+	assert.Equal(t, sharedCommon.InvalidClassifierID, bulkErr.ErrorAt(2).Code)
+	assert.Equal(t, []int{2, 3, 0, 1}, productIDs)
+	assert.Equal(t, 6, srv.RequestsCount("saveProduct"))
+}
+
+func TestInjectedFailuresAndSession(t *testing.T) {
+	srv, cli := newTestServer()
+	defer srv.Close()
+	addTestProducts(srv, 1)
+
+	productsCli := products.NewClient(cli)
+
+	srv.FailNext("getProducts", sharedCommon.HourlyRequestQuota, 1)
+	_, err := productsCli.GetProducts(context.Background(), map[string]string{})
+	assertAPIError(t, err, sharedCommon.HourlyRequestQuota)
+
+	prods, err := productsCli.GetProducts(context.Background(), map[string]string{})
+	assert.NoError(t, err)
+	assert.Len(t, prods, 1)
+
+	otherCli := products.NewClient(common.NewClientWithURL("othersess", "someclient", "", srv.URL, nil, nil))
+	_, err = otherCli.GetProducts(context.Background(), map[string]string{})
+	assertAPIError(t, err, sharedCommon.APISessionExpired)
+}
+
+func TestCustomersAndWarehouses(t *testing.T) {
+	srv, cli := newTestServer()
+	defer srv.Close()
+	srv.AddRecords(EntityWarehouses, Record{"warehouseID": 2, "name": "Main", "code": "MAIN"})
+
+	customersCli := customers.NewClient(cli)
+	report, err := customersCli.SaveCustomer(context.Background(), map[string]string{"firstName": "John", "lastName": "Smith", "groupID": "5"})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, 1, report.CustomerID)
+
+	custs, err := customersCli.GetCustomers(context.Background(), map[string]string{"customerIDs": "1"})
+	assert.NoError(t, err)
+	assert.Len(t, custs, 1)
+	assert.Equal(t, "Smith", custs[0].LastName)
+	assert.Equal(t, 5, custs[0].GroupID)
+
+	warehouses, err := warehouse.NewClient(cli).GetWarehouses(context.Background(), map[string]string{"code": "MAIN"})
+	assert.NoError(t, err)
+	assert.Len(t, warehouses, 1)
+	assert.Equal(t, "2", warehouses[0].WarehouseID)
+}
+
+func TestSalesDocumentsAndPriceLists(t *testing.T) {
+	srv, cli := newTestServer()
+	defer srv.Close()
+
+	salesCli := sales.NewClient(cli)
+	reports, err := salesCli.SaveSalesDocument(context.Background(), map[string]string{
+		"warehouseID": "1",
+		"productID1":  "10",
+		"amount1":     "2",
+		"price1":      "3.5",
+		"productID2":  "11",
+		"amount2":     "1",
+		"price2":      "1",
+	})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Len(t, reports, 1)
+	assert.Equal(t, "1", reports[0].InvoiceID.String())
+	assert.Equal(t, 8.0, reports[0].Total)
+	assert.Len(t, reports[0].Rows, 2)
+	assert.Equal(t, 11, reports[0].Rows[1].ProductID)
+
+	docs, err := salesCli.GetSalesDocuments(context.Background(), map[string]string{"id": "1"})
+	assert.NoError(t, err)
+	assert.Len(t, docs, 1)
+	assert.Equal(t, 1, docs[0].WarehouseID)
+	assert.Equal(t, "INVWAYBILL", docs[0].Type)
+	assert.Len(t, docs[0].InvoiceRows, 2)
+	assert.Equal(t, "10", docs[0].InvoiceRows[0].ProductID)
+
+	srv.AddRecords(
+		EntityProductsInPriceList,
+		Record{"priceListID": 1, "productID": 10, "price": 3.5},
+		Record{"priceListID": 2, "productID": 10, "price": 4},
+	)
+	pricesCli := prices.NewClient(cli)
+	priceListSaveRes, err := pricesCli.SaveSupplierPriceList(context.Background(), map[string]string{"name": "Supplier", "supplierID": "3"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, priceListSaveRes.SupplierPriceListID)
+
+	priceLists, err := pricesCli.GetSupplierPriceLists(context.Background(), map[string]string{"supplierID": "3"})
+	assert.NoError(t, err)
+	assert.Len(t, priceLists, 1)
+	assert.Equal(t, "Supplier", priceLists[0].Name)
+
+	productsInPriceList, err := pricesCli.GetProductsInPriceList(context.Background(), map[string]string{"priceListID": "2"})
+	assert.NoError(t, err)
+	assert.Len(t, productsInPriceList, 1)
+	assert.Equal(t, float32(4), productsInPriceList[0].Price)
+}
+
+func TestListerAgainstServer(t *testing.T) {
+	srv, cli := newTestServer()
+	defer srv.Close()
+	addTestProducts(srv, 250)
+
+	lister := sharedCommon.NewLister(
+		sharedCommon.ListingSettings{MaxItemsPerRequest: 300, MaxFetchersCount: 2},
+		products.NewListingDataProvider(products.NewClient(cli)),
+		nil,
+	)
+
+	ids := map[int]bool{}
+	for item := range lister.Get(context.Background(), map[string]interface{}{}) {
+		assert.NoError(t, item.Err)
+		if item.Err != nil {
+			return
+		}
+		ids[item.Payload.(products.Product).ProductID] = true
+	}
+
+	assert.Len(t, ids, 250)
+}
+
+func assertAPIError(t *testing.T, err error, code sharedCommon.ApiError) {
+	assert.Error(t, err)
+	if err == nil {
+		return
+	}
+
+	var erplyErr *sharedCommon.ErplyError
+	ok := errors.As(err, &erplyErr)
+	assert.True(t, ok, "unexpected error %v", err)
+	if ok {
+		assert.Equal(t, code, erplyErr.Code)
+	}
+}
@@ -46,6 +46,7 @@ func CreateInstallation(baseUrl, partnerKey string, filters map[string]string, h
 	if err != nil {
 		return nil, sharedCommon.NewFromError("CreateInstallation: error sending POST request", err, 0)
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		return nil, sharedCommon.NewFromError(fmt.Sprintf("CreateInstallation: bad response status code: %d", resp.StatusCode), nil, 0)
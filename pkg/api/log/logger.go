@@ -1,6 +1,10 @@
 package log
 
-import "log"
+import (
+	"fmt"
+	"log"
+	"strings"
+)
 
 var Log Logger
 
@@ -17,16 +21,130 @@ const (
 	Error
 )
 
+func (t Type) String() string {
+	switch t {
+	case Debug:
+		return "DEBUG"
+	case Info:
+		return "INFO"
+	case Warn:
+		return "WARN"
+	case Error:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(t))
+	}
+}
+
+//names of the fields which are used in the logs of the API calls
+const (
+	FieldMethod        = "method"
+	FieldClientCode    = "clientCode"
+	FieldDuration      = "duration"
+	FieldErrorCode     = "errorCode"
+	FieldHTTPStatus    = "httpStatus"
+	FieldAttempt       = "attempt"
+	FieldMaxAttempts   = "maxAttempts"
+	FieldFilters       = "filters"
+	FieldRequestsCount = "requestsCount"
+	FieldError         = "error"
+)
+
 type Logger interface {
 	Log(t Type, message string, arguments ...interface{})
 }
 
+//Field is a key value pair attached to a log message
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+//F creates a log field
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+//StructuredLogger is implemented by loggers which support key value fields, the fields given to it are already redacted
+type StructuredLogger interface {
+	Logger
+	LogFields(t Type, message string, fields ...Field)
+	Enabled(t Type) bool
+}
+
+//Enabled tells if the current logger will write messages of the given type, loggers which are not structured are
+//assumed to write everything except NullLogger
+func Enabled(t Type) bool {
+	switch logger := Log.(type) {
+	case NullLogger, *NullLogger:
+		return false
+	case StructuredLogger:
+		return logger.Enabled(t)
+	default:
+		return true
+	}
+}
+
+//LogFields redacts secrets in the fields and gives them to the current logger, if the logger is not structured,
+//the fields are appended to the message as key=value pairs, nothing is redacted if the message type is disabled
+func LogFields(t Type, message string, fields ...Field) {
+	if !Enabled(t) {
+		return
+	}
+
+	redactedFields := RedactFields(fields)
+
+	if structuredLogger, ok := Log.(StructuredLogger); ok {
+		structuredLogger.LogFields(t, message, redactedFields...)
+		return
+	}
+
+	Log.Log(t, "%s", formatFields(message, redactedFields))
+}
+
+func formatFields(message string, fields []Field) string {
+	if len(fields) == 0 {
+		return message
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(message)
+	for _, field := range fields {
+		sb.WriteString(fmt.Sprintf(" %s=%v", field.Key, field.Value))
+	}
+
+	return sb.String()
+}
+
 type NullLogger struct{}
 
 func (nl NullLogger) Log(t Type, message string, arguments ...interface{}) {}
 
-type StdLogger struct{}
+func (nl NullLogger) LogFields(t Type, message string, fields ...Field) {}
+
+func (nl NullLogger) Enabled(t Type) bool {
+	return false
+}
+
+//StdLogger writes to the standard logger, messages below Level are skipped, zero Level means all messages
+type StdLogger struct {
+	Level Type
+}
 
 func (sl StdLogger) Log(t Type, message string, arguments ...interface{}) {
-	log.Printf(message, arguments...)
+	if t < sl.Level {
+		return
+	}
+	log.Printf("[%s] %s", t, RedactString(fmt.Sprintf(message, arguments...)))
+}
+
+func (sl StdLogger) LogFields(t Type, message string, fields ...Field) {
+	if t < sl.Level {
+		return
+	}
+	log.Printf("[%s] %s", t, formatFields(message, fields))
+}
+
+func (sl StdLogger) Enabled(t Type) bool {
+	return t >= sl.Level
 }
@@ -0,0 +1,114 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	stdlog "log"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+)
+
+type loggerMock struct {
+	messages []string
+}
+
+func (lm *loggerMock) Log(t Type, message string, arguments ...interface{}) {
+	lm.messages = append(lm.messages, fmt.Sprintf("%s %s", t, fmt.Sprintf(message, arguments...)))
+}
+
+func setLogger(t *testing.T, logger Logger) {
+	oldLogger := Log
+	Log = logger
+	t.Cleanup(func() {
+		Log = oldLogger
+	})
+}
+
+func TestLogFieldsWithPrintfLogger(t *testing.T) {
+	logger := &loggerMock{}
+	setLogger(t, logger)
+
+	LogFields(
+		Debug,
+		"got API response",
+		F(FieldMethod, "getProducts"),
+		F(FieldDuration, time.Second),
+		F(FieldFilters, map[string]string{"sessionKey": "someKey"}),
+	)
+
+	assert.Equal(t, []string{"DEBUG got API response method=getProducts duration=1s filters=map[sessionKey:[REDACTED]]"}, logger.messages)
+}
+
+func TestSlogLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	setLogger(t, NewSlogLogger(slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo}))))
+
+	LogFields(Debug, "skipped message")
+	LogFields(
+		Warn,
+		"request failed",
+		F(FieldMethod, "getProducts"),
+		F(FieldErrorCode, 1002),
+		F(FieldFilters, map[string]string{"password": "somePass"}),
+	)
+	Log.Log(Error, "failed with jwt=%s", "someToken")
+
+	output := buf.String()
+	assert.NotContains(t, output, "skipped message")
+	assert.Contains(t, output, `level=WARN msg="request failed" method=getProducts errorCode=1002 filters=map[password:[REDACTED]]`)
+	assert.Contains(t, output, `level=ERROR msg="failed with jwt=[REDACTED]"`)
+	assert.NotContains(t, output, "somePass")
+	assert.NotContains(t, output, "someToken")
+}
+
+func TestStdLoggerLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	stdLogger := StdLogger{Level: Info}
+
+	flags := stdlog.Flags()
+	stdlog.SetFlags(0)
+	stdlog.SetOutput(buf)
+	defer func() {
+		stdlog.SetFlags(flags)
+		stdlog.SetOutput(os.Stderr)
+	}()
+
+	stdLogger.Log(Debug, "skipped message")
+	stdLogger.LogFields(Info, "some message", F(FieldMethod, "getProducts"))
+
+	assert.Equal(t, "[INFO] some message method=getProducts\n", buf.String())
+}
+
+type formatCounter struct {
+	count int
+}
+
+func (fc *formatCounter) String() string {
+	fc.count++
+	return "someValue"
+}
+
+func TestLogFieldsSkipsRedactionOfDisabledMessages(t *testing.T) {
+	counter := &formatCounter{}
+
+	setLogger(t, NullLogger{})
+	assert.False(t, Enabled(Error))
+	LogFields(Error, "skipped message", F("value", counter))
+
+	setLogger(t, StdLogger{Level: Warn})
+	assert.False(t, Enabled(Debug))
+	assert.True(t, Enabled(Warn))
+	LogFields(Debug, "skipped message", F("value", counter))
+
+	setLogger(t, NewSlogLogger(slog.New(slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelInfo}))))
+	assert.False(t, Enabled(Debug))
+	LogFields(Debug, "skipped message", F("value", counter))
+
+	assert.Equal(t, 0, counter.count)
+
+	setLogger(t, &loggerMock{})
+	assert.True(t, Enabled(Debug))
+}
@@ -0,0 +1,249 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+//RedactedValue replaces the values of secret fields in logs
+const RedactedValue = "[REDACTED]"
+
+//DefaultRedactedFields are the fields which are always redacted, the names are case insensitive
+var DefaultRedactedFields = []string{
+	"sessionKey",
+	"password",
+	"pass",
+	"jwt",
+	"token",
+	"accessToken",
+	"refreshToken",
+	"identityToken",
+	"apiKey",
+	"secret",
+	"authorization",
+}
+
+//jwtRegex matches JSON web tokens wherever they appear in a text
+var jwtRegex = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+//Redactor hides the values of secret fields in maps, URLs and texts
+type Redactor struct {
+	fields     map[string]bool
+	valueRegex *regexp.Regexp
+	lock       sync.RWMutex
+}
+
+//NewRedactor creates Redactor which hides the given fields
+func NewRedactor(fields ...string) *Redactor {
+	r := &Redactor{
+		fields: map[string]bool{},
+		lock:   sync.RWMutex{},
+	}
+	r.AddFields(fields...)
+
+	return r
+}
+
+//AddFields adds more fields to redact
+func (r *Redactor) AddFields(fields ...string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, field := range fields {
+		if field == "" {
+			continue
+		}
+		r.fields[strings.ToLower(field)] = true
+	}
+
+	quotedFields := make([]string, 0, len(r.fields))
+	for field := range r.fields {
+		quotedFields = append(quotedFields, regexp.QuoteMeta(field))
+	}
+
+	//matches key=value, key: value and "key":"value" forms
+	r.valueRegex = regexp.MustCompile(`(?i)("?\b(?:` + strings.Join(quotedFields, "|") + `)"?\s*[:=]\s*"?)([^&\s",}\]]+)`)
+}
+
+//IsRedactedField tells if the values of the field should be hidden
+func (r *Redactor) IsRedactedField(field string) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.fields[strings.ToLower(field)]
+}
+
+//RedactString hides the values of the secret fields and JSON web tokens in the text
+func (r *Redactor) RedactString(text string) string {
+	r.lock.RLock()
+	valueRegex := r.valueRegex
+	r.lock.RUnlock()
+
+	if valueRegex != nil {
+		text = valueRegex.ReplaceAllString(text, "${1}"+RedactedValue)
+	}
+
+	return jwtRegex.ReplaceAllString(text, RedactedValue)
+}
+
+//RedactURL hides the values of the secret query parameters
+func (r *Redactor) RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return r.RedactString(rawURL)
+	}
+
+	u.RawQuery = r.RedactValues(u.Query()).Encode()
+
+	return u.String()
+}
+
+//RedactValues gives a copy of the values where the secret fields are hidden
+func (r *Redactor) RedactValues(values url.Values) url.Values {
+	res := make(url.Values, len(values))
+	for key, vals := range values {
+		res[key] = r.redactStrings(key, vals)
+	}
+
+	return res
+}
+
+//RedactError gives an error with the same message where secrets are hidden, e.g. the URL of a failed HTTP request
+func (r *Redactor) RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if urlErr, ok := err.(*url.Error); ok {
+		return &url.Error{
+			Op:  urlErr.Op,
+			URL: r.RedactURL(urlErr.URL),
+			Err: urlErr.Err,
+		}
+	}
+
+	msg := err.Error()
+	redactedMsg := r.RedactString(msg)
+	if redactedMsg == msg {
+		return err
+	}
+
+	return errors.New(redactedMsg)
+}
+
+//Redact gives the value of the field with hidden secrets, maps are copied, numbers and booleans are kept as they are,
+//other values are converted to text
+func (r *Redactor) Redact(key string, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	if r.IsRedactedField(key) {
+		return RedactedValue
+	}
+
+	switch v := value.(type) {
+	case string:
+		return r.RedactString(v)
+	case error:
+		return r.RedactError(v).Error()
+	case time.Duration, time.Time:
+		return v
+	case map[string]string:
+		res := make(map[string]string, len(v))
+		for k, val := range v {
+			if r.IsRedactedField(k) {
+				res[k] = RedactedValue
+				continue
+			}
+			res[k] = r.RedactString(val)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			res[k] = r.Redact(k, val)
+		}
+		return res
+	case url.Values:
+		return r.RedactValues(v)
+	case http.Header:
+		res := make(http.Header, len(v))
+		for k, vals := range v {
+			res[k] = r.redactStrings(k, vals)
+		}
+		return res
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return value
+	}
+
+	if stringer, ok := value.(fmt.Stringer); ok {
+		return r.RedactString(stringer.String())
+	}
+
+	return r.RedactString(fmt.Sprintf("%+v", value))
+}
+
+//RedactFields gives a copy of the fields with hidden secrets
+func (r *Redactor) RedactFields(fields []Field) []Field {
+	res := make([]Field, 0, len(fields))
+	for _, field := range fields {
+		res = append(res, Field{Key: field.Key, Value: r.Redact(field.Key, field.Value)})
+	}
+
+	return res
+}
+
+func (r *Redactor) redactStrings(key string, vals []string) []string {
+	res := make([]string, 0, len(vals))
+	for _, val := range vals {
+		if r.IsRedactedField(key) {
+			res = append(res, RedactedValue)
+			continue
+		}
+		res = append(res, r.RedactString(val))
+	}
+
+	return res
+}
+
+//DefaultRedactor is used by the package level redaction functions and LogFields
+var DefaultRedactor = NewRedactor(DefaultRedactedFields...)
+
+//AddRedactedFields configures more fields which are hidden in logs
+func AddRedactedFields(fields ...string) {
+	DefaultRedactor.AddFields(fields...)
+}
+
+//RedactString hides secrets in the text with the DefaultRedactor
+func RedactString(text string) string {
+	return DefaultRedactor.RedactString(text)
+}
+
+//RedactURL hides secret query parameters with the DefaultRedactor
+func RedactURL(rawURL string) string {
+	return DefaultRedactor.RedactURL(rawURL)
+}
+
+//RedactError hides secrets in the error message with the DefaultRedactor
+func RedactError(err error) error {
+	return DefaultRedactor.RedactError(err)
+}
+
+//RedactFields hides secrets in the fields with the DefaultRedactor
+func RedactFields(fields []Field) []Field {
+	return DefaultRedactor.RedactFields(fields)
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+//SlogLogger adapts slog.Logger to the Logger interface, e.g. log.Log = log.NewSlogLogger(slog.Default())
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+//NewSlogLogger creates SlogLogger, nil logger means slog.Default()
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SlogLogger{logger: logger}
+}
+
+func (sl *SlogLogger) Log(t Type, message string, arguments ...interface{}) {
+	sl.logger.Log(context.Background(), toSlogLevel(t), RedactString(fmt.Sprintf(message, arguments...)))
+}
+
+func (sl *SlogLogger) LogFields(t Type, message string, fields ...Field) {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, field := range fields {
+		attrs = append(attrs, slog.Any(field.Key, field.Value))
+	}
+
+	sl.logger.LogAttrs(context.Background(), toSlogLevel(t), message, attrs...)
+}
+
+func (sl *SlogLogger) Enabled(t Type) bool {
+	return sl.logger.Enabled(context.Background(), toSlogLevel(t))
+}
+
+func toSlogLevel(t Type) slog.Level {
+	switch t {
+	case Debug:
+		return slog.LevelDebug
+	case Info:
+		return slog.LevelInfo
+	case Warn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
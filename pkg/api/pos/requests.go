@@ -2,39 +2,23 @@ package pos
 
 import (
 	"context"
-	"encoding/json"
 	"github.com/erply/api-go-wrapper/internal/common"
-	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
 )
 
 // GetPointsOfSale will list points of sale according to specified filters.
 func (cli *Client) GetPointsOfSale(ctx context.Context, filters map[string]string) ([]PointOfSale, error) {
-	resp, err := cli.SendRequest(ctx, "getPointsOfSale", filters)
+	res, err := common.Call[GetPointsOfSaleResponse](ctx, cli.Client, "getPointsOfSale", filters)
 	if err != nil {
 		return nil, err
 	}
-	var res GetPointsOfSaleResponse
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("failed to unmarshal GetPointsOfSaleResponse", err, 0)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 	return res.PointsOfSale, nil
 }
 
 // GetClockIns will list clocking of employees according to the specified filters.
 func (cli *Client) GetClockIns(ctx context.Context, filters map[string]string) ([]Clocking, error) {
-	resp, err := cli.SendRequest(ctx, "getClockIns", filters)
+	res, err := common.Call[GetClockInsResponse](ctx, cli.Client, "getClockIns", filters)
 	if err != nil {
 		return nil, err
 	}
-	var res GetClockInsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("failed to unmarshal GetClockInsResponse", err, 0)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 	return res.ClockIns, nil
 }
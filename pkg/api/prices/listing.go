@@ -0,0 +1,89 @@
+package prices
+
+import (
+	"context"
+)
+
+type SupplierPriceListsListingDataProvider struct {
+	erplyAPI Manager
+}
+
+func NewSupplierPriceListsListingDataProvider(erplyClient Manager) *SupplierPriceListsListingDataProvider {
+	return &SupplierPriceListsListingDataProvider{
+		erplyAPI: erplyClient,
+	}
+}
+
+func (spldp *SupplierPriceListsListingDataProvider) Count(ctx context.Context, filters map[string]interface{}) (int, error) {
+	filters["recordsOnPage"] = 1
+	filters["pageNo"] = 1
+
+	resp, err := spldp.erplyAPI.GetSupplierPriceListsBulk(ctx, []map[string]interface{}{filters}, map[string]string{})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(resp.BulkItems) == 0 {
+		return 0, nil
+	}
+
+	return resp.BulkItems[0].Status.RecordsTotal, nil
+}
+
+func (spldp *SupplierPriceListsListingDataProvider) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	resp, err := spldp.erplyAPI.GetSupplierPriceListsBulk(ctx, bulkFilters, map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	for _, bulkItem := range resp.BulkItems {
+		for i := range bulkItem.PriceLists {
+			callback(bulkItem.PriceLists[i])
+		}
+	}
+
+	return nil
+}
+
+type ProductsInPriceListListingDataProvider struct {
+	erplyAPI Manager
+}
+
+func NewProductsInPriceListListingDataProvider(erplyClient Manager) *ProductsInPriceListListingDataProvider {
+	return &ProductsInPriceListListingDataProvider{
+		erplyAPI: erplyClient,
+	}
+}
+
+func (pipldp *ProductsInPriceListListingDataProvider) Count(ctx context.Context, filters map[string]interface{}) (int, error) {
+	filters["recordsOnPage"] = 1
+	filters["pageNo"] = 1
+
+	resp, err := pipldp.erplyAPI.GetProductsInPriceListBulk(ctx, []map[string]interface{}{filters}, map[string]string{})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(resp.BulkItems) == 0 {
+		return 0, nil
+	}
+
+	return resp.BulkItems[0].Status.RecordsTotal, nil
+}
+
+func (pipldp *ProductsInPriceListListingDataProvider) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	resp, err := pipldp.erplyAPI.GetProductsInPriceListBulk(ctx, bulkFilters, map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	for _, bulkItem := range resp.BulkItems {
+		for i := range bulkItem.PriceLists {
+			callback(bulkItem.PriceLists[i])
+		}
+	}
+
+	return nil
+}
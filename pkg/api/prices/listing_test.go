@@ -0,0 +1,598 @@
+package prices
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/erply/api-go-wrapper/internal/common"
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+)
+
+func sendSupplierPriceListsResponse(w http.ResponseWriter, errStatus sharedCommon.ApiError, totalCount int, idsBulk [][]int) error {
+	bulkResp := GetPriceListsResponseBulk{
+		Status: sharedCommon.Status{ResponseStatus: "ok"},
+	}
+
+	bulkItems := make([]GetPriceListsResponseBulkItem, 0, len(idsBulk))
+	for _, ids := range idsBulk {
+		records := make([]PriceList, 0, len(ids))
+		for _, id := range ids {
+			records = append(records, PriceList{
+				ID: id,
+			})
+		}
+		statusBulk := sharedCommon.StatusBulk{}
+		if errStatus == 0 {
+			statusBulk.ResponseStatus = "ok"
+		} else {
+			statusBulk.ResponseStatus = "not ok"
+		}
+		statusBulk.RecordsTotal = totalCount
+		statusBulk.ErrorCode = errStatus
+		statusBulk.RecordsInResponse = len(ids)
+
+		bulkItems = append(bulkItems, GetPriceListsResponseBulkItem{
+			Status:     statusBulk,
+			PriceLists: records,
+		})
+	}
+	bulkResp.BulkItems = bulkItems
+
+	jsonRaw, err := json.Marshal(bulkResp)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(jsonRaw)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func TestSupplierPriceListsListingCountSuccess(t *testing.T) {
+	const totalCount = 10
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsedRequest, err := common.ExtractBulkFiltersFromRequest(r)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		assert.Equal(t, "someclient", parsedRequest["clientCode"])
+		assert.Equal(t, "somesess", parsedRequest["sessionKey"])
+		requests := parsedRequest["requests"].([]map[string]interface{})
+		assert.Equal(t, float64(1), requests[0]["pageNo"])
+		assert.Equal(t, float64(1), requests[0]["recordsOnPage"])
+		assert.Equal(t, "getSupplierPriceLists", requests[0]["requestName"])
+		assert.Equal(t, "smeval", requests[0]["somekey"])
+
+		err = sendSupplierPriceListsResponse(w, 0, totalCount, [][]int{{1}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	pricesClient := NewClient(baseClient)
+	dataProvider := NewSupplierPriceListsListingDataProvider(pricesClient)
+
+	actualCount, err := dataProvider.Count(context.Background(), map[string]interface{}{"somekey": "smeval", "pageNo": 1, "recordsOnPage": 1})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, totalCount, actualCount)
+}
+
+func TestSupplierPriceListsListingCountError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := sendSupplierPriceListsResponse(w, sharedCommon.MalformedRequest, 0, [][]int{{1}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	pricesClient := NewClient(baseClient)
+	dataProvider := NewSupplierPriceListsListingDataProvider(pricesClient)
+
+	actualCount, err := dataProvider.Count(context.Background(), map[string]interface{}{"somekey": "smeval"})
+	assert.Error(t, err)
+	if err == nil {
+		return
+	}
+	assert.Contains(t, err.Error(), sharedCommon.MalformedRequest.String())
+	assert.Equal(t, 0, actualCount)
+}
+
+func TestSupplierPriceListsListingCountWithNoBulkItems(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := sendSupplierPriceListsResponse(w, 0, 0, [][]int{})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	pricesClient := NewClient(baseClient)
+	dataProvider := NewSupplierPriceListsListingDataProvider(pricesClient)
+
+	actualCount, err := dataProvider.Count(context.Background(), map[string]interface{}{"somekey": "smeval"})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, 0, actualCount)
+}
+
+func TestSupplierPriceListsReadSuccess(t *testing.T) {
+	const limit = 2
+	const offset = 1
+	const totalCount = 10
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsedRequest, err := common.ExtractBulkFiltersFromRequest(r)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		assert.Equal(t, "someclient", parsedRequest["clientCode"])
+		assert.Equal(t, "somesess", parsedRequest["sessionKey"])
+
+		requests := parsedRequest["requests"].([]map[string]interface{})
+		assert.Len(t, requests, 1)
+
+		assert.Equal(t, float64(offset), requests[0]["pageNo"])
+		assert.Equal(t, float64(limit), requests[0]["recordsOnPage"])
+
+		assert.Equal(t, "getSupplierPriceLists", requests[0]["requestName"])
+		assert.Equal(t, "smeval", requests[0]["somekey"])
+
+		err = sendSupplierPriceListsResponse(w, 0, totalCount, [][]int{{1, 2}, {3, 4}, {5}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	pricesClient := NewClient(baseClient)
+	dataProvider := NewSupplierPriceListsListingDataProvider(pricesClient)
+
+	actualIDs := make([]int, 0, 5)
+	err := dataProvider.Read(
+		context.Background(),
+		[]map[string]interface{}{
+			{
+				"somekey":       "smeval",
+				"pageNo":        1,
+				"recordsOnPage": 2,
+			},
+		},
+		func(item interface{}) {
+			assert.IsType(t, item, PriceList{})
+			actualIDs = append(actualIDs, item.(PriceList).ID)
+		},
+	)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, actualIDs)
+}
+
+func TestSupplierPriceListsReadError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := sendSupplierPriceListsResponse(w, sharedCommon.MalformedRequest, 10, [][]int{{1}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	pricesClient := NewClient(baseClient)
+	dataProvider := NewSupplierPriceListsListingDataProvider(pricesClient)
+
+	err := dataProvider.Read(
+		context.Background(),
+		[]map[string]interface{}{{"somekey": "smeval"}},
+		func(item interface{}) {},
+	)
+	assert.Error(t, err)
+	if err == nil {
+		return
+	}
+
+	assert.Contains(t, err.Error(), sharedCommon.MalformedRequest.String())
+}
+
+func TestSupplierPriceListsReadSuccessIntegration(t *testing.T) {
+	const totalCount = 11
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsedRequest, err := common.ExtractBulkFiltersFromRequest(r)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		requests := parsedRequest["requests"].([]map[string]interface{})
+		assert.Len(t, requests, 1)
+
+		if requests[0]["pageNo"] == float64(1) {
+			err = sendSupplierPriceListsResponse(w, 0, totalCount, [][]int{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}})
+		} else {
+			err = sendSupplierPriceListsResponse(w, 0, totalCount, [][]int{{11}})
+		}
+
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	pricesClient := NewClient(baseClient)
+	dataProvider := NewSupplierPriceListsListingDataProvider(pricesClient)
+
+	lister := sharedCommon.NewLister(
+		sharedCommon.ListingSettings{
+			MaxRequestsCountPerSecond: 0,
+			StreamBufferLength:        10,
+			MaxItemsPerRequest:        10,
+			MaxFetchersCount:          10,
+		},
+		dataProvider,
+		func(sleepTime time.Duration) {},
+	)
+
+	itemsChan := lister.Get(context.Background(), map[string]interface{}{})
+
+	actualIDs := collectSupplierPriceListsIDsFromChannel(itemsChan)
+	sort.Ints(actualIDs)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, actualIDs)
+}
+
+func collectSupplierPriceListsIDsFromChannel(itemsStream sharedCommon.ItemsStream) []int {
+	actualIDs := make([]int, 0)
+	doneChan := make(chan struct{}, 1)
+	go func() {
+		defer close(doneChan)
+		for item := range itemsStream {
+			actualIDs = append(actualIDs, item.Payload.(PriceList).ID)
+		}
+	}()
+
+mainLoop:
+	for {
+		select {
+		case <-doneChan:
+			break mainLoop
+		case <-time.After(time.Second * 5):
+			break mainLoop
+		}
+	}
+
+	return actualIDs
+}
+
+func sendProductsInPriceListResponse(w http.ResponseWriter, errStatus sharedCommon.ApiError, totalCount int, idsBulk [][]int) error {
+	bulkResp := GetProductsInPriceListResponseBulk{
+		Status: sharedCommon.Status{ResponseStatus: "ok"},
+	}
+
+	bulkItems := make([]GetProductsInPriceListResponseBulkItem, 0, len(idsBulk))
+	for _, ids := range idsBulk {
+		records := make([]ProductsInPriceList, 0, len(ids))
+		for _, id := range ids {
+			records = append(records, ProductsInPriceList{
+				PriceListProductID: id,
+			})
+		}
+		statusBulk := sharedCommon.StatusBulk{}
+		if errStatus == 0 {
+			statusBulk.ResponseStatus = "ok"
+		} else {
+			statusBulk.ResponseStatus = "not ok"
+		}
+		statusBulk.RecordsTotal = totalCount
+		statusBulk.ErrorCode = errStatus
+		statusBulk.RecordsInResponse = len(ids)
+
+		bulkItems = append(bulkItems, GetProductsInPriceListResponseBulkItem{
+			Status:     statusBulk,
+			PriceLists: records,
+		})
+	}
+	bulkResp.BulkItems = bulkItems
+
+	jsonRaw, err := json.Marshal(bulkResp)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(jsonRaw)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func TestProductsInPriceListListingCountSuccess(t *testing.T) {
+	const totalCount = 10
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsedRequest, err := common.ExtractBulkFiltersFromRequest(r)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		assert.Equal(t, "someclient", parsedRequest["clientCode"])
+		assert.Equal(t, "somesess", parsedRequest["sessionKey"])
+		requests := parsedRequest["requests"].([]map[string]interface{})
+		assert.Equal(t, float64(1), requests[0]["pageNo"])
+		assert.Equal(t, float64(1), requests[0]["recordsOnPage"])
+		assert.Equal(t, "getProductsInPriceList", requests[0]["requestName"])
+		assert.Equal(t, "smeval", requests[0]["somekey"])
+
+		err = sendProductsInPriceListResponse(w, 0, totalCount, [][]int{{1}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	pricesClient := NewClient(baseClient)
+	dataProvider := NewProductsInPriceListListingDataProvider(pricesClient)
+
+	actualCount, err := dataProvider.Count(context.Background(), map[string]interface{}{"somekey": "smeval", "pageNo": 1, "recordsOnPage": 1})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, totalCount, actualCount)
+}
+
+func TestProductsInPriceListListingCountError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := sendProductsInPriceListResponse(w, sharedCommon.MalformedRequest, 0, [][]int{{1}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	pricesClient := NewClient(baseClient)
+	dataProvider := NewProductsInPriceListListingDataProvider(pricesClient)
+
+	actualCount, err := dataProvider.Count(context.Background(), map[string]interface{}{"somekey": "smeval"})
+	assert.Error(t, err)
+	if err == nil {
+		return
+	}
+	assert.Contains(t, err.Error(), sharedCommon.MalformedRequest.String())
+	assert.Equal(t, 0, actualCount)
+}
+
+func TestProductsInPriceListListingCountWithNoBulkItems(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := sendProductsInPriceListResponse(w, 0, 0, [][]int{})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	pricesClient := NewClient(baseClient)
+	dataProvider := NewProductsInPriceListListingDataProvider(pricesClient)
+
+	actualCount, err := dataProvider.Count(context.Background(), map[string]interface{}{"somekey": "smeval"})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, 0, actualCount)
+}
+
+func TestProductsInPriceListReadSuccess(t *testing.T) {
+	const limit = 2
+	const offset = 1
+	const totalCount = 10
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsedRequest, err := common.ExtractBulkFiltersFromRequest(r)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		assert.Equal(t, "someclient", parsedRequest["clientCode"])
+		assert.Equal(t, "somesess", parsedRequest["sessionKey"])
+
+		requests := parsedRequest["requests"].([]map[string]interface{})
+		assert.Len(t, requests, 1)
+
+		assert.Equal(t, float64(offset), requests[0]["pageNo"])
+		assert.Equal(t, float64(limit), requests[0]["recordsOnPage"])
+
+		assert.Equal(t, "getProductsInPriceList", requests[0]["requestName"])
+		assert.Equal(t, "smeval", requests[0]["somekey"])
+
+		err = sendProductsInPriceListResponse(w, 0, totalCount, [][]int{{1, 2}, {3, 4}, {5}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	pricesClient := NewClient(baseClient)
+	dataProvider := NewProductsInPriceListListingDataProvider(pricesClient)
+
+	actualIDs := make([]int, 0, 5)
+	err := dataProvider.Read(
+		context.Background(),
+		[]map[string]interface{}{
+			{
+				"somekey":       "smeval",
+				"pageNo":        1,
+				"recordsOnPage": 2,
+			},
+		},
+		func(item interface{}) {
+			assert.IsType(t, item, ProductsInPriceList{})
+			actualIDs = append(actualIDs, item.(ProductsInPriceList).PriceListProductID)
+		},
+	)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, actualIDs)
+}
+
+func TestProductsInPriceListReadError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := sendProductsInPriceListResponse(w, sharedCommon.MalformedRequest, 10, [][]int{{1}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	pricesClient := NewClient(baseClient)
+	dataProvider := NewProductsInPriceListListingDataProvider(pricesClient)
+
+	err := dataProvider.Read(
+		context.Background(),
+		[]map[string]interface{}{{"somekey": "smeval"}},
+		func(item interface{}) {},
+	)
+	assert.Error(t, err)
+	if err == nil {
+		return
+	}
+
+	assert.Contains(t, err.Error(), sharedCommon.MalformedRequest.String())
+}
+
+func TestProductsInPriceListReadSuccessIntegration(t *testing.T) {
+	const totalCount = 11
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsedRequest, err := common.ExtractBulkFiltersFromRequest(r)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		requests := parsedRequest["requests"].([]map[string]interface{})
+		assert.Len(t, requests, 1)
+
+		if requests[0]["pageNo"] == float64(1) {
+			err = sendProductsInPriceListResponse(w, 0, totalCount, [][]int{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}})
+		} else {
+			err = sendProductsInPriceListResponse(w, 0, totalCount, [][]int{{11}})
+		}
+
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	pricesClient := NewClient(baseClient)
+	dataProvider := NewProductsInPriceListListingDataProvider(pricesClient)
+
+	lister := sharedCommon.NewLister(
+		sharedCommon.ListingSettings{
+			MaxRequestsCountPerSecond: 0,
+			StreamBufferLength:        10,
+			MaxItemsPerRequest:        10,
+			MaxFetchersCount:          10,
+		},
+		dataProvider,
+		func(sleepTime time.Duration) {},
+	)
+
+	itemsChan := lister.Get(context.Background(), map[string]interface{}{})
+
+	actualIDs := collectProductsInPriceListIDsFromChannel(itemsChan)
+	sort.Ints(actualIDs)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, actualIDs)
+}
+
+func collectProductsInPriceListIDsFromChannel(itemsStream sharedCommon.ItemsStream) []int {
+	actualIDs := make([]int, 0)
+	doneChan := make(chan struct{}, 1)
+	go func() {
+		defer close(doneChan)
+		for item := range itemsStream {
+			actualIDs = append(actualIDs, item.Payload.(ProductsInPriceList).PriceListProductID)
+		}
+	}()
+
+mainLoop:
+	for {
+		select {
+		case <-doneChan:
+			break mainLoop
+		case <-time.After(time.Second * 5):
+			break mainLoop
+		}
+	}
+
+	return actualIDs
+}
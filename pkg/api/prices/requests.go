@@ -2,31 +2,14 @@ package prices
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"github.com/erply/api-go-wrapper/internal/common"
-	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
-	"io/ioutil"
 )
 
 func (cli *Client) GetSupplierPriceLists(ctx context.Context, filters map[string]string) ([]PriceList, error) {
-	resp, err := cli.SendRequest(ctx, "getSupplierPriceLists", filters)
+	res, err := common.Call[GetPriceListsResponse](ctx, cli.Client, "getSupplierPriceLists", filters)
 	if err != nil {
 		return nil, err
 	}
-	var res GetPriceListsResponse
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return []PriceList{}, err
-	}
-
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("ERPLY API: failed to unmarshal GetPriceListsResponse from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 
 	return res.PriceLists, nil
 }
@@ -40,24 +23,11 @@ func (cli *Client) EditProductToSupplierPriceList(ctx context.Context, filters m
 }
 
 func (cli *Client) persistProductToSupplierPriceList(ctx context.Context, method string, filters map[string]string) (*ChangeProductToSupplierPriceListResult, error) {
-	resp, err := cli.SendRequest(ctx, method, filters)
-	if err != nil {
-		return nil, sharedCommon.NewFromError(method+" request failed", err, 0)
-	}
-	body, err := ioutil.ReadAll(resp.Body)
+	res, err := common.Call[ChangeProductToSupplierPriceListResponse](ctx, cli.Client, method, filters)
 	if err != nil {
 		return nil, err
 	}
 
-	res := &ChangeProductToSupplierPriceListResponse{}
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("ERPLY API: failed to unmarshal ChangeProductToSupplierPriceListResponse from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
-
 	if len(res.ChangeProductToSupplierPriceListResult) == 0 {
 		return nil, nil
 	}
@@ -67,12 +37,6 @@ func (cli *Client) persistProductToSupplierPriceList(ctx context.Context, method
 
 //ChangeProductToSupplierPriceListBulk wraps both additions and edits as addProductToSupplierPriceList or editProductInSupplierPriceList
 func (cli *Client) ChangeProductToSupplierPriceListBulk(ctx context.Context, bulkRequest []map[string]interface{}, baseFilters map[string]string) (ChangeProductToSupplierPriceListResponseBulk, error) {
-	var bulkResp ChangeProductToSupplierPriceListResponseBulk
-
-	if len(bulkRequest) > sharedCommon.MaxBulkRequestsCount {
-		return bulkResp, fmt.Errorf("cannot add more than %d products to price list in one bulk request", sharedCommon.MaxBulkRequestsCount)
-	}
-
 	bulkInputs := make([]common.BulkInput, 0, len(bulkRequest))
 	for _, prodPrice := range bulkRequest {
 		_, isEditMode := prodPrice["supplierPriceListProductID"]
@@ -86,227 +50,54 @@ func (cli *Client) ChangeProductToSupplierPriceListBulk(ctx context.Context, bul
 		})
 	}
 
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal ChangeProductToSupplierPriceListResponseBulk from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, sharedCommon.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
-
-	for _, bulkRespItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&bulkRespItem.Status.Status) {
-			return bulkResp, sharedCommon.NewErplyError(
-				bulkRespItem.Status.ErrorCode.String(),
-				fmt.Sprintf("%+v", bulkRespItem.Status),
-				bulkResp.Status.ErrorCode,
-			)
-		}
-	}
-
-	return bulkResp, nil
+	return common.CallBulkInputs[ChangeProductToSupplierPriceListResponseBulk](ctx, cli.Client, bulkInputs, baseFilters)
 }
 
 func (cli *Client) GetSupplierPriceListsBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetPriceListsResponseBulk, error) {
-	var bulkResp GetPriceListsResponseBulk
-	bulkInputs := make([]common.BulkInput, 0, len(bulkFilters))
-	for _, bulkFilterMap := range bulkFilters {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "getSupplierPriceLists",
-			Filters:    bulkFilterMap,
-		})
-	}
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal GetPriceListsResponseBulk from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, sharedCommon.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
-
-	for _, prodBulkItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&prodBulkItem.Status.Status) {
-			return bulkResp, sharedCommon.NewErplyError(prodBulkItem.Status.ErrorCode.String(), prodBulkItem.Status.Request+": "+prodBulkItem.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-		}
-	}
-
-	return bulkResp, nil
+	return common.CallBulk[GetPriceListsResponseBulk](ctx, cli.Client, "getSupplierPriceLists", bulkFilters, baseFilters)
 }
 
 func (cli *Client) GetProductsInSupplierPriceList(ctx context.Context, filters map[string]string) ([]ProductsInSupplierPriceList, error) {
-	resp, err := cli.SendRequest(ctx, "getProductsInSupplierPriceList", filters)
+	res, err := common.Call[ProductsInSupplierPriceListResponse](ctx, cli.Client, "getProductsInSupplierPriceList", filters)
 	if err != nil {
 		return nil, err
 	}
-	var res ProductsInSupplierPriceListResponse
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return []ProductsInSupplierPriceList{}, err
-	}
-
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("ERPLY API: failed to unmarshal ProductsInSupplierPriceListResponse from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 
 	return res.ProductsInSupplierPriceList, nil
 }
 
 func (cli *Client) GetProductsInSupplierPriceListBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (ProductsInSupplierPriceListResponseBulk, error) {
-	var bulkResp ProductsInSupplierPriceListResponseBulk
-	bulkInputs := make([]common.BulkInput, 0, len(bulkFilters))
-	for _, bulkFilterMap := range bulkFilters {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "getProductsInSupplierPriceList",
-			Filters:    bulkFilterMap,
-		})
-	}
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal ProductsInSupplierPriceListResponseBulk from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, sharedCommon.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
-
-	for _, prodBulkItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&prodBulkItem.Status.Status) {
-			return bulkResp, sharedCommon.NewErplyError(prodBulkItem.Status.ErrorCode.String(), prodBulkItem.Status.Request+": "+prodBulkItem.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-		}
-	}
-
-	return bulkResp, nil
+	return common.CallBulk[ProductsInSupplierPriceListResponseBulk](ctx, cli.Client, "getProductsInSupplierPriceList", bulkFilters, baseFilters)
 }
 
 func (cli *Client) GetProductsInPriceList(ctx context.Context, filters map[string]string) ([]ProductsInPriceList, error) {
-	resp, err := cli.SendRequest(ctx, "getProductsInPriceList", filters)
+	res, err := common.Call[GetProductsInPriceListResponse](ctx, cli.Client, "getProductsInPriceList", filters)
 	if err != nil {
 		return nil, err
 	}
-	var res GetProductsInPriceListResponse
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return []ProductsInPriceList{}, err
-	}
-
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("ERPLY API: failed to unmarshal GetProductsInPriceListResponse from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 
 	return res.PriceLists, nil
 }
 
 func (cli *Client) GetProductsInPriceListWithStatus(ctx context.Context, filters map[string]string) (GetProductsInPriceListResponse, error) {
-	resp, err := cli.SendRequest(ctx, "getProductsInPriceList", filters)
+	res, err := common.Call[GetProductsInPriceListResponse](ctx, cli.Client, "getProductsInPriceList", filters)
 	if err != nil {
 		return GetProductsInPriceListResponse{}, err
 	}
-	var res GetProductsInPriceListResponse
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return res, err
-	}
-
-	if err := json.Unmarshal(body, &res); err != nil {
-		return res, fmt.Errorf("ERPLY API: failed to unmarshal GetProductsInPriceListResponse from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return res, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 
 	return res, nil
 }
 
 func (cli *Client) GetProductsInPriceListBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetProductsInPriceListResponseBulk, error) {
-	var bulkResp GetProductsInPriceListResponseBulk
-	bulkInputs := make([]common.BulkInput, 0, len(bulkFilters))
-	for _, bulkFilterMap := range bulkFilters {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "getProductsInPriceList",
-			Filters:    bulkFilterMap,
-		})
-	}
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal GetProductsInPriceListResponseBulk from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, sharedCommon.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
-
-	for _, prodBulkItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&prodBulkItem.Status.Status) {
-			return bulkResp, sharedCommon.NewErplyError(prodBulkItem.Status.ErrorCode.String(), prodBulkItem.Status.Request+": "+prodBulkItem.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-		}
-	}
-
-	return bulkResp, nil
+	return common.CallBulk[GetProductsInPriceListResponseBulk](ctx, cli.Client, "getProductsInPriceList", bulkFilters, baseFilters)
 }
 
 func (cli *Client) DeleteProductsFromSupplierPriceList(ctx context.Context, filters map[string]string) (*DeleteProductsFromSupplierPriceListResult, error) {
-	resp, err := cli.SendRequest(ctx, "deleteProductsFromSupplierPriceList", filters)
-	if err != nil {
-		return nil, sharedCommon.NewFromError("deleteProductsFromSupplierPriceList request failed", err, 0)
-	}
-	body, err := ioutil.ReadAll(resp.Body)
+	res, err := common.Call[DeleteProductsFromSupplierPriceListResponse](ctx, cli.Client, "deleteProductsFromSupplierPriceList", filters)
 	if err != nil {
 		return nil, err
 	}
 
-	res := &DeleteProductsFromSupplierPriceListResponse{}
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("ERPLY API: failed to unmarshal DeleteProductsFromSupplierPriceListResponse from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
-
 	if len(res.DeleteProductsFromSupplierPriceListResult) == 0 {
 		return nil, nil
 	}
@@ -315,70 +106,15 @@ func (cli *Client) DeleteProductsFromSupplierPriceList(ctx context.Context, filt
 }
 
 func (cli *Client) DeleteProductsFromSupplierPriceListBulk(ctx context.Context, bulkRequest []map[string]interface{}, baseFilters map[string]string) (DeleteProductsFromSupplierPriceListResponseBulk, error) {
-	var bulkResp DeleteProductsFromSupplierPriceListResponseBulk
-
-	if len(bulkRequest) > sharedCommon.MaxBulkRequestsCount {
-		return bulkResp, fmt.Errorf("cannot delete more than %d products from price list in one bulk request", sharedCommon.MaxBulkRequestsCount)
-	}
-
-	bulkInputs := make([]common.BulkInput, 0, len(bulkRequest))
-	for _, bulkInput := range bulkRequest {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "deleteProductsFromSupplierPriceList",
-			Filters:    bulkInput,
-		})
-	}
-
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal DeleteProductsFromSupplierPriceListResponseBulk from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, sharedCommon.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
-
-	for _, bulkRespItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&bulkRespItem.Status.Status) {
-			return bulkResp, sharedCommon.NewErplyError(
-				bulkRespItem.Status.ErrorCode.String(),
-				fmt.Sprintf("%+v", bulkRespItem.Status),
-				bulkResp.Status.ErrorCode,
-			)
-		}
-	}
-
-	return bulkResp, nil
+	return common.CallBulk[DeleteProductsFromSupplierPriceListResponseBulk](ctx, cli.Client, "deleteProductsFromSupplierPriceList", bulkRequest, baseFilters)
 }
 
 func (cli *Client) SaveSupplierPriceList(ctx context.Context, filters map[string]string) (*SaveSupplierPriceListResult, error) {
-	resp, err := cli.SendRequest(ctx, "saveSupplierPriceList", filters)
-	if err != nil {
-		return nil, sharedCommon.NewFromError("saveSupplierPriceList request failed", err, 0)
-	}
-	body, err := ioutil.ReadAll(resp.Body)
+	res, err := common.Call[SaveSupplierPriceListResultResponse](ctx, cli.Client, "saveSupplierPriceList", filters)
 	if err != nil {
 		return nil, err
 	}
 
-	res := &SaveSupplierPriceListResultResponse{}
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("ERPLY API: failed to unmarshal SaveSupplierPriceListResultResponse from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
-
 	if len(res.SaveSupplierPriceListResult) == 0 {
 		return nil, nil
 	}
@@ -387,70 +123,15 @@ func (cli *Client) SaveSupplierPriceList(ctx context.Context, filters map[string
 }
 
 func (cli *Client) SaveSupplierPriceListBulk(ctx context.Context, bulkRequest []map[string]interface{}, baseFilters map[string]string) (SaveSupplierPriceListResponseBulk, error) {
-	var bulkResp SaveSupplierPriceListResponseBulk
-
-	if len(bulkRequest) > sharedCommon.MaxBulkRequestsCount {
-		return bulkResp, fmt.Errorf("cannot save more than %d price lists in one bulk request", sharedCommon.MaxBulkRequestsCount)
-	}
-
-	bulkInputs := make([]common.BulkInput, 0, len(bulkRequest))
-	for _, bulkInput := range bulkRequest {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "saveSupplierPriceList",
-			Filters:    bulkInput,
-		})
-	}
-
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal SaveSupplierPriceListResponseBulk from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, sharedCommon.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
-
-	for _, bulkRespItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&bulkRespItem.Status.Status) {
-			return bulkResp, sharedCommon.NewErplyError(
-				bulkRespItem.Status.ErrorCode.String(),
-				fmt.Sprintf("%+v", bulkRespItem.Status),
-				bulkResp.Status.ErrorCode,
-			)
-		}
-	}
-
-	return bulkResp, nil
+	return common.CallBulk[SaveSupplierPriceListResponseBulk](ctx, cli.Client, "saveSupplierPriceList", bulkRequest, baseFilters)
 }
 
 func (cli *Client) SavePriceList(ctx context.Context, filters map[string]string) (*SavePriceListResult, error) {
-	resp, err := cli.SendRequest(ctx, "savePriceList", filters)
-	if err != nil {
-		return nil, sharedCommon.NewFromError("savePriceList request failed", err, 0)
-	}
-	body, err := ioutil.ReadAll(resp.Body)
+	res, err := common.Call[SavePriceListResultResponse](ctx, cli.Client, "savePriceList", filters)
 	if err != nil {
 		return nil, err
 	}
 
-	res := &SavePriceListResultResponse{}
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("ERPLY API: failed to unmarshal SavePriceListResultResponse from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
-
 	if len(res.SavePriceListResults) == 0 {
 		return nil, nil
 	}
@@ -459,49 +140,7 @@ func (cli *Client) SavePriceList(ctx context.Context, filters map[string]string)
 }
 
 func (cli *Client) SavePriceListBulk(ctx context.Context, bulkRequest []map[string]interface{}, baseFilters map[string]string) (SavePriceListResponseBulk, error) {
-	var bulkResp SavePriceListResponseBulk
-
-	if len(bulkRequest) > sharedCommon.MaxBulkRequestsCount {
-		return bulkResp, fmt.Errorf("cannot save more than %d price lists in one bulk request", sharedCommon.MaxBulkRequestsCount)
-	}
-
-	bulkInputs := make([]common.BulkInput, 0, len(bulkRequest))
-	for _, bulkInput := range bulkRequest {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "savePriceList",
-			Filters:    bulkInput,
-		})
-	}
-
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal SavePriceListResponseBulk from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, sharedCommon.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
-
-	for _, bulkRespItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&bulkRespItem.Status.Status) {
-			return bulkResp, sharedCommon.NewErplyError(
-				bulkRespItem.Status.ErrorCode.String(),
-				fmt.Sprintf("%+v", bulkRespItem.Status),
-				bulkResp.Status.ErrorCode,
-			)
-		}
-	}
-
-	return bulkResp, nil
+	return common.CallBulk[SavePriceListResponseBulk](ctx, cli.Client, "savePriceList", bulkRequest, baseFilters)
 }
 
 func (cli *Client) AddProductToPriceList(ctx context.Context, filters map[string]string) (*ChangeProductToPriceListResult, error) {
@@ -513,24 +152,11 @@ func (cli *Client) EditProductToPriceList(ctx context.Context, filters map[strin
 }
 
 func (cli *Client) persistProductToPriceList(ctx context.Context, method string, filters map[string]string) (*ChangeProductToPriceListResult, error) {
-	resp, err := cli.SendRequest(ctx, method, filters)
-	if err != nil {
-		return nil, sharedCommon.NewFromError(method+" request failed", err, 0)
-	}
-	body, err := ioutil.ReadAll(resp.Body)
+	res, err := common.Call[ChangeProductToPriceListResponse](ctx, cli.Client, method, filters)
 	if err != nil {
 		return nil, err
 	}
 
-	res := &ChangeProductToPriceListResponse{}
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("ERPLY API: failed to unmarshal ChangeProductToPriceListResponse from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
-
 	if len(res.ChangeProductToPriceListResults) == 0 {
 		return nil, nil
 	}
@@ -539,12 +165,6 @@ func (cli *Client) persistProductToPriceList(ctx context.Context, method string,
 }
 
 func (cli *Client) ChangeProductToPriceListBulk(ctx context.Context, bulkRequest []map[string]interface{}, baseFilters map[string]string) (ChangeProductToPriceListResponseBulk, error) {
-	var bulkResp ChangeProductToPriceListResponseBulk
-
-	if len(bulkRequest) > sharedCommon.MaxBulkRequestsCount {
-		return bulkResp, fmt.Errorf("cannot add more than %d products to price list in one bulk request", sharedCommon.MaxBulkRequestsCount)
-	}
-
 	bulkInputs := make([]common.BulkInput, 0, len(bulkRequest))
 	for _, prodPrice := range bulkRequest {
 		_, isEditMode := prodPrice["priceListProductID"]
@@ -558,56 +178,15 @@ func (cli *Client) ChangeProductToPriceListBulk(ctx context.Context, bulkRequest
 		})
 	}
 
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal ChangeProductToPriceListBulk from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, sharedCommon.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
-
-	for _, bulkRespItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&bulkRespItem.Status.Status) {
-			return bulkResp, sharedCommon.NewErplyError(
-				bulkRespItem.Status.ErrorCode.String(),
-				fmt.Sprintf("%+v", bulkRespItem.Status),
-				bulkResp.Status.ErrorCode,
-			)
-		}
-	}
-
-	return bulkResp, nil
+	return common.CallBulkInputs[ChangeProductToPriceListResponseBulk](ctx, cli.Client, bulkInputs, baseFilters)
 }
 
 func (cli *Client) DeleteProductsFromPriceList(ctx context.Context, filters map[string]string) (*DeleteProductsFromPriceListResult, error) {
-	resp, err := cli.SendRequest(ctx, "deleteProductInPriceList", filters)
-	if err != nil {
-		return nil, sharedCommon.NewFromError("deleteProductInPriceList request failed", err, 0)
-	}
-	body, err := ioutil.ReadAll(resp.Body)
+	res, err := common.Call[DeleteProductsFromPriceListResponse](ctx, cli.Client, "deleteProductInPriceList", filters)
 	if err != nil {
 		return nil, err
 	}
 
-	res := &DeleteProductsFromPriceListResponse{}
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("ERPLY API: failed to unmarshal DeleteProductsFromPriceListResponse from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
-
 	if len(res.DeleteProductsFromPriceListResults) == 0 {
 		return nil, nil
 	}
@@ -619,47 +198,5 @@ func (cli *Client) DeleteProductsFromPriceListBulk(
 	ctx context.Context,
 	bulkRequest []map[string]interface{}, baseFilters map[string]string,
 ) (DeleteProductsFromPriceListResponseBulk, error) {
-	var bulkResp DeleteProductsFromPriceListResponseBulk
-
-	if len(bulkRequest) > sharedCommon.MaxBulkRequestsCount {
-		return bulkResp, fmt.Errorf("cannot delete more than %d products from price list in one bulk request", sharedCommon.MaxBulkRequestsCount)
-	}
-
-	bulkInputs := make([]common.BulkInput, 0, len(bulkRequest))
-	for _, bulkInput := range bulkRequest {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "deleteProductInPriceList",
-			Filters:    bulkInput,
-		})
-	}
-
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal DeleteProductsFromPriceListResponseBulk from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, sharedCommon.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
-
-	for _, bulkRespItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&bulkRespItem.Status.Status) {
-			return bulkResp, sharedCommon.NewErplyError(
-				bulkRespItem.Status.ErrorCode.String(),
-				fmt.Sprintf("%+v", bulkRespItem.Status),
-				bulkResp.Status.ErrorCode,
-			)
-		}
-	}
-
-	return bulkResp, nil
+	return common.CallBulk[DeleteProductsFromPriceListResponseBulk](ctx, cli.Client, "deleteProductInPriceList", bulkRequest, baseFilters)
 }
@@ -0,0 +1,56 @@
+package products
+
+import (
+	"context"
+	"github.com/erply/api-go-wrapper/internal/common"
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func newCassetteTestClient(t *testing.T) *Client {
+	recorder, err := sharedCommon.NewCassetteRecorder("testdata/products_cassette.json", sharedCommon.CassetteModeReplay, nil)
+	assert.NoError(t, err)
+
+	baseClient := common.NewClientWithURL("somesess", "someclient", "", "http://localhost", &http.Client{Transport: recorder}, nil)
+
+	return NewClient(baseClient)
+}
+
+func TestGetProductsFromCassette(t *testing.T) {
+	cli := newCassetteTestClient(t)
+
+	products, err := cli.GetProducts(context.Background(), map[string]string{"recordsOnPage": "2"})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+
+	assert.Len(t, products, 2)
+	assert.Equal(t, 1, products[0].ProductID)
+	assert.Equal(t, "C001", products[0].Code)
+	assert.Equal(t, 2, products[1].ProductID)
+}
+
+func TestGetProductsBulkFromCassette(t *testing.T) {
+	cli := newCassetteTestClient(t)
+
+	bulkResp, err := cli.GetProductsBulk(
+		context.Background(),
+		[]map[string]interface{}{
+			{"requestID": 1, "pageNo": 1, "recordsOnPage": 2},
+			{"requestID": 2, "pageNo": 2, "recordsOnPage": 2},
+		},
+		map[string]string{},
+	)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+
+	assert.Len(t, bulkResp.BulkItems, 2)
+	assert.Len(t, bulkResp.BulkItems[0].Products, 2)
+	assert.Equal(t, 3, bulkResp.BulkItems[1].Products[0].ProductID)
+	assert.Equal(t, "K001", bulkResp.BulkItems[1].Products[0].Code)
+}
@@ -0,0 +1,51 @@
+package products
+
+import (
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"time"
+)
+
+//ProductFilter is a typed filter of getProducts
+type ProductFilter struct {
+	ProductID               int       `filter:"productID"`
+	ProductIDs              []int     `filter:"productIDs"`
+	Type                    string    `filter:"type"`
+	Code                    string    `filter:"code"`
+	Code2                   string    `filter:"code2"`
+	Code3                   string    `filter:"code3"`
+	SupplierCode            string    `filter:"supplierCode"`
+	Name                    string    `filter:"name"`
+	SearchNameIncrementally string    `filter:"searchNameIncrementally"`
+	GroupID                 int       `filter:"groupID"`
+	CategoryID              int       `filter:"categoryID"`
+	BrandID                 int       `filter:"brandID"`
+	SupplierID              int       `filter:"supplierID"`
+	PriorityGroupID         int       `filter:"priorityGroupID"`
+	Active                  *bool     `filter:"active"`
+	Status                  string    `filter:"status"`
+	ChangedSince            time.Time `filter:"changedSince"`
+	AddedSince              time.Time `filter:"addedSince"`
+	WarehouseID             int       `filter:"warehouseID"`
+	PriceListID             int       `filter:"priceListID"`
+	GetStockInfo            bool      `filter:"getStockInfo"`
+	GetPriceListPrices      bool      `filter:"getPriceListPrices"`
+	GetFIFOCost             bool      `filter:"getFIFOCost"`
+	GetMatrixVariations     bool      `filter:"getMatrixVariations"`
+	IncludeMatrixVariations bool      `filter:"includeMatrixVariations"`
+	GetParameters           bool      `filter:"getParameters"`
+	GetPackageInfo          bool      `filter:"getPackageInfo"`
+	GetReplacementProducts  bool      `filter:"getReplacementProducts"`
+	GetRelatedProducts      bool      `filter:"getRelatedProducts"`
+	GetRecipes              bool      `filter:"getRecipes"`
+	GetContainerInfo        bool      `filter:"getContainerInfo"`
+	GetAllLanguages         bool      `filter:"getAllLanguages"`
+	GetPriorityGroups       bool      `filter:"getPriorityGroups"`
+	Lang                    string    `filter:"lang"`
+	sharedCommon.Paging
+	Extra map[string]string `filter:",extra"` //parameters which have no field
+}
+
+//ToFilters sharedCommon.Filter interface implementation
+func (pf ProductFilter) ToFilters() (map[string]string, error) {
+	return sharedCommon.EncodeFilters(pf)
+}
@@ -0,0 +1,49 @@
+package products
+
+import (
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+)
+
+//ProductInput is a typed input of saveProduct, set ProductID to update an existing product
+type ProductInput struct {
+	ProductID          int      `filter:"productID"`
+	Type               string   `filter:"type"`
+	GroupID            int      `filter:"groupID"`
+	UnitID             int      `filter:"unitID"`
+	Name               string   `filter:"name"`
+	Code               string   `filter:"code"`
+	Code2              string   `filter:"code2"`
+	Code3              string   `filter:"code3"`
+	SupplierCode       string   `filter:"supplierCode"`
+	CategoryID         int      `filter:"categoryID"`
+	SupplierID         int      `filter:"supplierID"`
+	BrandID            int      `filter:"brandID"`
+	PriorityGroupID    int      `filter:"priorityGroupID"`
+	VatrateID          int      `filter:"vatrateID"`
+	Price              *float64 `filter:"price"`
+	Cost               *float64 `filter:"cost"`
+	Active             *bool    `filter:"active"`
+	Status             string   `filter:"status"`
+	DisplayedInWebshop *bool    `filter:"displayedInWebshop"`
+	Description        string   `filter:"description"`
+	LongDesc           string   `filter:"longdesc"`
+	NetWeight          float64  `filter:"netWeight"`
+	GrossWeight        float64  `filter:"grossWeight"`
+	Volume             float64  `filter:"volume"`
+	Length             float64  `filter:"length"`
+	Width              float64  `filter:"width"`
+	Height             float64  `filter:"height"`
+	ParentProductID    int      `filter:"parentProductID"`
+
+	//sent as attributeName1, attributeType1, attributeValue1 and so on
+	Attributes []sharedCommon.ObjAttribute `filter:",indexed"`
+	//sent as longAttributeName1, longAttributeValue1 and so on
+	LongAttributes []sharedCommon.LongAttribute `filter:",indexed"`
+
+	Extra map[string]string `filter:",extra"` //parameters which have no field
+}
+
+//ToFilters sharedCommon.Filter interface implementation
+func (pi ProductInput) ToFilters() (map[string]string, error) {
+	return sharedCommon.EncodeFilters(pi)
+}
@@ -6,6 +6,8 @@ type Manager interface {
 	GetProducts(ctx context.Context, filters map[string]string) ([]Product, error)
 	GetProductsCount(ctx context.Context, filters map[string]string) (int, error)
 	GetProductsBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetProductsResponseBulk, error)
+	GetProductsWithFilter(ctx context.Context, filter ProductFilter) ([]Product, error)
+	GetProductsBulkWithFilters(ctx context.Context, filters []ProductFilter, baseFilters map[string]string) (GetProductsResponseBulk, error)
 	GetProductUnits(ctx context.Context, filters map[string]string) ([]ProductUnit, error)
 	GetProductCategories(ctx context.Context, filters map[string]string) ([]ProductCategory, error)
 	GetProductCategoriesBulk(
@@ -33,6 +35,8 @@ type Manager interface {
 	GetProductStockBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetProductStockResponseBulk, error)
 	SaveProduct(ctx context.Context, filters map[string]string) (SaveProductResult, error)
 	SaveProductBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (SaveProductResponseBulk, error)
+	SaveProductWithInput(ctx context.Context, input ProductInput) (SaveProductResult, error)
+	SaveProductBulkWithInputs(ctx context.Context, inputs []ProductInput, baseFilters map[string]string) (SaveProductResponseBulk, error)
 	DeleteProduct(ctx context.Context, filters map[string]string) error
 	DeleteProductBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (DeleteProductResponseBulk, error)
 	SaveAssortment(ctx context.Context, filters map[string]string) (SaveAssortmentResult, error)
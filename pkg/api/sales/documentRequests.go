@@ -2,25 +2,14 @@ package sales
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"github.com/erply/api-go-wrapper/internal/common"
 	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
-	"io/ioutil"
 )
 
 func (cli *Client) SaveSalesDocument(ctx context.Context, filters map[string]string) (SaleDocImportReports, error) {
-	resp, err := cli.SendRequest(ctx, "saveSalesDocument", filters)
+	res, err := common.Call[PostSalesDocumentResponse](ctx, cli.Client, "saveSalesDocument", filters)
 	if err != nil {
-		return nil, sharedCommon.NewFromError("PostSalesDocument request failed", err, 0)
-	}
-	res := &PostSalesDocumentResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("unmarshaling PostSalesDocumentResponse failed", err, 0)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
+		return nil, err
 	}
 
 	if len(res.ImportReports) == 0 {
@@ -34,38 +23,28 @@ func (cli *Client) SaveSalesDocumentBulk(
 	ctx context.Context,
 	bulkFilters []map[string]interface{},
 	baseFilters map[string]string,
-) (respBulk SaveSalesDocumentResponseBulk, err error) {
-	bulkInputs := make([]common.BulkInput, 0, len(bulkFilters))
-	for _, bulkFilterMap := range bulkFilters {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "saveSalesDocument",
-			Filters:    bulkFilterMap,
-		})
-	}
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return respBulk, err
-	}
+) (SaveSalesDocumentResponseBulk, error) {
+	return common.CallBulk[SaveSalesDocumentResponseBulk](ctx, cli.Client, "saveSalesDocument", bulkFilters, baseFilters)
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
+//SaveSalesDocumentWithInput is SaveSalesDocument with a typed input
+func (cli *Client) SaveSalesDocumentWithInput(ctx context.Context, input SaleDocumentInput) (SaleDocImportReports, error) {
+	filters, err := input.ToFilters()
 	if err != nil {
-		return respBulk, err
+		return nil, err
 	}
 
-	if err := json.Unmarshal(body, &respBulk); err != nil {
-		return respBulk, fmt.Errorf("ERPLY API: failed to unmarshal SaveSalesDocumentResponseBulk from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&respBulk.Status) {
-		return respBulk, sharedCommon.NewErplyError(respBulk.Status.ErrorCode.String(), respBulk.Status.Request+": "+respBulk.Status.ResponseStatus, respBulk.Status.ErrorCode)
-	}
+	return cli.SaveSalesDocument(ctx, filters)
+}
 
-	for _, bulkRespItem := range respBulk.BulkItems {
-		if !common.IsJSONResponseOK(&bulkRespItem.Status.Status) {
-			return respBulk, sharedCommon.NewErplyError(bulkRespItem.Status.ErrorCode.String(), bulkRespItem.Status.Request+": "+bulkRespItem.Status.ResponseStatus, respBulk.Status.ErrorCode)
-		}
+//SaveSalesDocumentBulkWithInputs is SaveSalesDocumentBulk with typed inputs of the sub-requests
+func (cli *Client) SaveSalesDocumentBulkWithInputs(ctx context.Context, inputs []SaleDocumentInput, baseFilters map[string]string) (SaveSalesDocumentResponseBulk, error) {
+	bulkFilters, err := sharedCommon.ToBulkFilters(inputs)
+	if err != nil {
+		return SaveSalesDocumentResponseBulk{}, err
 	}
 
-	return respBulk, nil
+	return cli.SaveSalesDocumentBulk(ctx, bulkFilters, baseFilters)
 }
 
 func (cli *Client) SavePurchaseDocument(ctx context.Context, filters map[string]string) (resp PurchaseDocImportReports, err error) {
@@ -86,60 +65,14 @@ func (cli *Client) SavePurchaseDocumentBulk(
 	ctx context.Context,
 	bulkFilters []map[string]interface{},
 	baseFilters map[string]string,
-) (respBulk SavePurchaseDocumentResponseBulk, err error) {
-	bulkInputs := make([]common.BulkInput, 0, len(bulkFilters))
-	for _, bulkFilterMap := range bulkFilters {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "savePurchaseDocument",
-			Filters:    bulkFilterMap,
-		})
-	}
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return respBulk, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return respBulk, err
-	}
-
-	if err := json.Unmarshal(body, &respBulk); err != nil {
-		return respBulk, fmt.Errorf("ERPLY API: failed to unmarshal SavePurchaseDocumentResponseBulk from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&respBulk.Status) {
-		return respBulk, sharedCommon.NewErplyError(
-			respBulk.Status.ErrorCode.String(),
-			respBulk.Status.Request+": "+respBulk.Status.ResponseStatus,
-			respBulk.Status.ErrorCode,
-		)
-	}
-
-	for _, bulkRespItem := range respBulk.BulkItems {
-		if !common.IsJSONResponseOK(&bulkRespItem.Status.Status) {
-			return respBulk, sharedCommon.NewErplyError(
-				bulkRespItem.Status.ErrorCode.String(),
-				bulkRespItem.Status.Request+": "+bulkRespItem.Status.ResponseStatus,
-				respBulk.Status.ErrorCode,
-			)
-		}
-	}
-
-	return respBulk, nil
+) (SavePurchaseDocumentResponseBulk, error) {
+	return common.CallBulk[SavePurchaseDocumentResponseBulk](ctx, cli.Client, "savePurchaseDocument", bulkFilters, baseFilters)
 }
 
 func (cli *Client) GetSalesDocuments(ctx context.Context, filters map[string]string) ([]SaleDocument, error) {
-	resp, err := cli.SendRequest(ctx, "getSalesDocuments", filters)
+	res, err := common.Call[GetSalesDocumentResponse](ctx, cli.Client, "getSalesDocuments", filters)
 	if err != nil {
-		return nil, sharedCommon.NewFromError("GetSalesDocument request failed", err, 0)
-	}
-	res := &GetSalesDocumentResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("unmarshaling GetSalesDocumentResponse failed", err, 0)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
+		return nil, err
 	}
 
 	if len(res.SalesDocuments) == 0 {
@@ -151,17 +84,9 @@ func (cli *Client) GetSalesDocuments(ctx context.Context, filters map[string]str
 }
 
 func (cli *Client) GetSalesDocumentsWithStatus(ctx context.Context, filters map[string]string) (*GetSalesDocumentResponse, error) {
-	resp, err := cli.SendRequest(ctx, "getSalesDocuments", filters)
+	res, err := common.Call[GetSalesDocumentResponse](ctx, cli.Client, "getSalesDocuments", filters)
 	if err != nil {
-		return nil, sharedCommon.NewFromError("GetSalesDocument request failed", err, 0)
-	}
-	res := &GetSalesDocumentResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("unmarshaling GetSalesDocumentResponse failed", err, 0)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
+		return nil, err
 	}
 
 	if len(res.SalesDocuments) == 0 {
@@ -169,65 +94,34 @@ func (cli *Client) GetSalesDocumentsWithStatus(ctx context.Context, filters map[
 		return nil, nil
 	}
 
-	return res, nil
+	return &res, nil
 }
 
 func (cli *Client) GetSalesDocumentsBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetSaleDocumentResponseBulk, error) {
-	var bulkResp GetSaleDocumentResponseBulk
-	bulkInputs := make([]common.BulkInput, 0, len(bulkFilters))
-	for _, bulkFilterMap := range bulkFilters {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "getSalesDocuments",
-			Filters:    bulkFilterMap,
-		})
-	}
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
+	return common.CallBulk[GetSaleDocumentResponseBulk](ctx, cli.Client, "getSalesDocuments", bulkFilters, baseFilters)
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
+//GetSalesDocumentsWithFilter is GetSalesDocuments with a typed filter
+func (cli *Client) GetSalesDocumentsWithFilter(ctx context.Context, filter SalesDocumentFilter) ([]SaleDocument, error) {
+	filters, err := filter.ToFilters()
 	if err != nil {
-		return bulkResp, err
+		return nil, err
 	}
 
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal GetSaleDocumentResponseBulk from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, sharedCommon.NewErplyError(
-			bulkResp.Status.ErrorCode.String(),
-			bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus,
-			bulkResp.Status.ErrorCode,
-		)
-	}
-
-	for _, bulkItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&bulkItem.Status.Status) {
-			return bulkResp, sharedCommon.NewErplyError(
-				bulkItem.Status.ErrorCode.String(),
-				bulkItem.Status.Request+": "+bulkItem.Status.ResponseStatus,
-				bulkItem.Status.ErrorCode,
-			)
-		}
-	}
-
-	return bulkResp, nil
+	return cli.GetSalesDocuments(ctx, filters)
 }
 
-func (cli *Client) DeleteDocument(ctx context.Context, filters map[string]string) error {
-	resp, err := cli.SendRequest(ctx, "deleteSalesDocument", filters)
+//GetSalesDocumentsBulkWithFilters is GetSalesDocumentsBulk with typed filters of the sub-requests
+func (cli *Client) GetSalesDocumentsBulkWithFilters(ctx context.Context, filters []SalesDocumentFilter, baseFilters map[string]string) (GetSaleDocumentResponseBulk, error) {
+	bulkFilters, err := sharedCommon.ToBulkFilters(filters)
 	if err != nil {
-		return sharedCommon.NewFromError("DeleteDocumentsByIds request failed", err, 0)
-	}
-	res := &GetSalesDocumentResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return sharedCommon.NewFromError("unmarshaling DeleteDocumentsByIds failed", err, 0)
+		return GetSaleDocumentResponseBulk{}, err
 	}
 
-	if !common.IsJSONResponseOK(&res.Status) {
-		return sharedCommon.NewFromResponseStatus(&res.Status)
-	}
+	return cli.GetSalesDocumentsBulk(ctx, bulkFilters, baseFilters)
+}
 
-	return nil
+func (cli *Client) DeleteDocument(ctx context.Context, filters map[string]string) error {
+	_, err := common.Call[GetSalesDocumentResponse](ctx, cli.Client, "deleteSalesDocument", filters)
+	return err
 }
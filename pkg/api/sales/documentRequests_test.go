@@ -325,7 +325,7 @@ func TestSaveSalesDocumentBulk(t *testing.T) {
 					Status: statusBulk,
 					Records: SaleDocImportReports{
 						{
-							InvoiceID: 123,
+							InvoiceID: "123",
 						},
 					},
 				},
@@ -333,7 +333,7 @@ func TestSaveSalesDocumentBulk(t *testing.T) {
 					Status: statusBulk,
 					Records: SaleDocImportReports{
 						{
-							InvoiceID: 124,
+							InvoiceID: "124",
 						},
 					},
 				},
@@ -381,9 +381,9 @@ func TestSaveSalesDocumentBulk(t *testing.T) {
 
 	assert.Equal(t, expectedStatus, bulkResp.BulkItems[0].Status)
 	assert.Len(t, bulkResp.BulkItems[0].Records, 1)
-	assert.Equal(t, 123, bulkResp.BulkItems[0].Records[0].InvoiceID)
+	assert.Equal(t, json.Number("123"), bulkResp.BulkItems[0].Records[0].InvoiceID)
 
 	assert.Equal(t, expectedStatus, bulkResp.BulkItems[1].Status)
 	assert.Len(t, bulkResp.BulkItems[1].Records, 1)
-	assert.Equal(t, 124, bulkResp.BulkItems[1].Records[0].InvoiceID)
+	assert.Equal(t, json.Number("124"), bulkResp.BulkItems[1].Records[0].InvoiceID)
 }
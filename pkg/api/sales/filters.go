@@ -0,0 +1,36 @@
+package sales
+
+import (
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"time"
+)
+
+//SalesDocumentFilter is a typed filter of getSalesDocuments
+type SalesDocumentFilter struct {
+	ID                    int       `filter:"id"`
+	IDs                   []int     `filter:"ids"`
+	Type                  string    `filter:"type"`
+	Number                string    `filter:"number"`
+	NumberFrom            string    `filter:"numberFrom"`
+	NumberTo              string    `filter:"numberTo"`
+	ClientID              int       `filter:"clientID"`
+	WarehouseID           int       `filter:"warehouseID"`
+	PointOfSaleID         int       `filter:"pointOfSaleID"`
+	ProjectID             int       `filter:"projectID"`
+	Confirmed             *bool     `filter:"confirmed"`
+	DateFrom              time.Time `filter:"dateFrom,date"`
+	DateTo                time.Time `filter:"dateTo,date"`
+	ChangedSince          time.Time `filter:"changedSince"`
+	GetRowsForAllInvoices bool      `filter:"getRowsForAllInvoices"`
+	GetReturnedPayments   bool      `filter:"getReturnedPayments"`
+	GetCOGS               bool      `filter:"getCOGS"`
+	GetAddedTimestamp     bool      `filter:"getAddedTimestamp"`
+	NonZeroRowsOnly       bool      `filter:"nonZeroRowsOnly"`
+	sharedCommon.Paging
+	Extra map[string]string `filter:",extra"` //parameters which have no field
+}
+
+//ToFilters sharedCommon.Filter interface implementation
+func (sdf SalesDocumentFilter) ToFilters() (map[string]string, error) {
+	return sharedCommon.EncodeFilters(sdf)
+}
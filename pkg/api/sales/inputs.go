@@ -0,0 +1,67 @@
+package sales
+
+import (
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"time"
+)
+
+//SaleDocumentInput is a typed input of saveSalesDocument, set ID to update an existing document.
+//Rows are sent as productID1, amount1, price1 and so on.
+type SaleDocumentInput struct {
+	ID                      int       `filter:"id"`
+	Type                    string    `filter:"type"`
+	CurrencyCode            string    `filter:"currencyCode"`
+	CurrencyRate            float64   `filter:"currencyRate"`
+	WarehouseID             int       `filter:"warehouseID"`
+	PointOfSaleID           int       `filter:"pointOfSaleID"`
+	Number                  string    `filter:"number"`
+	Date                    time.Time `filter:"date,date"`
+	Time                    string    `filter:"time"`
+	DeliveryDate            time.Time `filter:"deliveryDate,date"`
+	CustomerID              int       `filter:"customerID"`
+	PayerID                 int       `filter:"payerID"`
+	ShipToID                int       `filter:"shipToID"`
+	AddressID               int       `filter:"addressID"`
+	PayerAddressID          int       `filter:"payerAddressID"`
+	ShipToAddressID         int       `filter:"shipToAddressID"`
+	ContactID               int       `filter:"contactID"`
+	EmployeeID              int       `filter:"employeeID"`
+	ProjectID               int       `filter:"projectID"`
+	InvoiceState            string    `filter:"invoiceState"`
+	PaymentType             string    `filter:"paymentType"`
+	PaymentTypeID           int       `filter:"paymentTypeID"`
+	PaymentDays             int       `filter:"paymentDays"`
+	PaymentStatus           string    `filter:"paymentStatus"`
+	BaseDocumentIDs         []int     `filter:"baseDocumentIDs"`
+	Notes                   string    `filter:"notes"`
+	InternalNotes           string    `filter:"internalNotes"`
+	PackingUnitsDescription string    `filter:"packingUnitsDescription"`
+	ReferenceNumber         string    `filter:"referenceNumber"`
+	CustomReferenceNumber   string    `filter:"customReferenceNumber"`
+	ConfirmInvoice          *bool     `filter:"confirmInvoice"`
+
+	Rows []SaleDocumentRowInput `filter:",indexed"`
+	//sent as attributeName1, attributeType1, attributeValue1 and so on
+	Attributes []sharedCommon.ObjAttribute `filter:",indexed"`
+
+	Extra map[string]string `filter:",extra"` //parameters which have no field
+}
+
+//SaleDocumentRowInput is a row of SaleDocumentInput, either ProductID or ServiceID should be set
+type SaleDocumentRowInput struct {
+	RowID     int      `filter:"rowID"`
+	ProductID int      `filter:"productID"`
+	ServiceID int      `filter:"serviceID"`
+	ItemName  string   `filter:"itemName"`
+	VatrateID int      `filter:"vatrateID"`
+	Amount    float64  `filter:"amount"`
+	Price     *float64 `filter:"price"`
+	Discount  float64  `filter:"discount"`
+
+	Extra map[string]string `filter:",extra"` //row parameters which have no field, the row number is added to them
+}
+
+//ToFilters sharedCommon.Filter interface implementation
+func (sdi SaleDocumentInput) ToFilters() (map[string]string, error) {
+	return sharedCommon.EncodeFilters(sdi)
+}
@@ -5,6 +5,7 @@ import "context"
 type (
 	ProjectManager interface {
 		GetProjects(ctx context.Context, filters map[string]string) ([]Project, error)
+		GetProjectsBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetProjectsResponseBulk, error)
 		GetProjectStatus(ctx context.Context, filters map[string]string) ([]ProjectStatus, error)
 	}
 	DocumentManager interface {
@@ -14,9 +15,13 @@ type (
 			bulkFilters []map[string]interface{},
 			baseFilters map[string]string,
 		) (respBulk SaveSalesDocumentResponseBulk, err error)
+		SaveSalesDocumentWithInput(ctx context.Context, input SaleDocumentInput) (SaleDocImportReports, error)
+		SaveSalesDocumentBulkWithInputs(ctx context.Context, inputs []SaleDocumentInput, baseFilters map[string]string) (SaveSalesDocumentResponseBulk, error)
 		GetSalesDocuments(ctx context.Context, filters map[string]string) ([]SaleDocument, error)
 		GetSalesDocumentsWithStatus(ctx context.Context, filters map[string]string) (*GetSalesDocumentResponse, error)
 		GetSalesDocumentsBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetSaleDocumentResponseBulk, error)
+		GetSalesDocumentsWithFilter(ctx context.Context, filter SalesDocumentFilter) ([]SaleDocument, error)
+		GetSalesDocumentsBulkWithFilters(ctx context.Context, filters []SalesDocumentFilter, baseFilters map[string]string) (GetSaleDocumentResponseBulk, error)
 		DeleteDocument(ctx context.Context, filters map[string]string) error
 		SavePurchaseDocument(ctx context.Context, filters map[string]string) (PurchaseDocImportReports, error)
 		SavePurchaseDocumentBulk(
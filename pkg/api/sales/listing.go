@@ -87,3 +87,87 @@ func (vrldp *VatRatesListingDataProvider) Read(ctx context.Context, bulkFilters
 
 	return nil
 }
+
+type PaymentsListingDataProvider struct {
+	erplyAPI Manager
+}
+
+func NewPaymentsListingDataProvider(erplyClient Manager) *PaymentsListingDataProvider {
+	return &PaymentsListingDataProvider{
+		erplyAPI: erplyClient,
+	}
+}
+
+func (pldp *PaymentsListingDataProvider) Count(ctx context.Context, filters map[string]interface{}) (int, error) {
+	filters["recordsOnPage"] = 1
+	filters["pageNo"] = 1
+
+	resp, err := pldp.erplyAPI.GetPaymentsBulk(ctx, []map[string]interface{}{filters}, map[string]string{})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(resp.BulkItems) == 0 {
+		return 0, nil
+	}
+
+	return resp.BulkItems[0].Status.RecordsTotal, nil
+}
+
+func (pldp *PaymentsListingDataProvider) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	resp, err := pldp.erplyAPI.GetPaymentsBulk(ctx, bulkFilters, map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	for _, bulkItem := range resp.BulkItems {
+		for i := range bulkItem.PaymentInfos {
+			callback(bulkItem.PaymentInfos[i])
+		}
+	}
+
+	return nil
+}
+
+type ProjectsListingDataProvider struct {
+	erplyAPI Manager
+}
+
+func NewProjectsListingDataProvider(erplyClient Manager) *ProjectsListingDataProvider {
+	return &ProjectsListingDataProvider{
+		erplyAPI: erplyClient,
+	}
+}
+
+func (pldp *ProjectsListingDataProvider) Count(ctx context.Context, filters map[string]interface{}) (int, error) {
+	filters["recordsOnPage"] = 1
+	filters["pageNo"] = 1
+
+	resp, err := pldp.erplyAPI.GetProjectsBulk(ctx, []map[string]interface{}{filters}, map[string]string{})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(resp.BulkItems) == 0 {
+		return 0, nil
+	}
+
+	return resp.BulkItems[0].Status.RecordsTotal, nil
+}
+
+func (pldp *ProjectsListingDataProvider) Read(ctx context.Context, bulkFilters []map[string]interface{}, callback func(item interface{})) error {
+	resp, err := pldp.erplyAPI.GetProjectsBulk(ctx, bulkFilters, map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	for _, bulkItem := range resp.BulkItems {
+		for i := range bulkItem.Projects {
+			callback(bulkItem.Projects[i])
+		}
+	}
+
+	return nil
+}
@@ -573,3 +573,587 @@ mainLoop:
 
 	return actualIDs
 }
+
+func sendPaymentsResponse(w http.ResponseWriter, errStatus sharedCommon.ApiError, totalCount int, idsBulk [][]int) error {
+	bulkResp := GetPaymentsResponseBulk{
+		Status: sharedCommon.Status{ResponseStatus: "ok"},
+	}
+
+	bulkItems := make([]GetPaymentsBulkItem, 0, len(idsBulk))
+	for _, ids := range idsBulk {
+		records := make([]PaymentInfo, 0, len(ids))
+		for _, id := range ids {
+			records = append(records, PaymentInfo{
+				PaymentID: id,
+			})
+		}
+		statusBulk := sharedCommon.StatusBulk{}
+		if errStatus == 0 {
+			statusBulk.ResponseStatus = "ok"
+		} else {
+			statusBulk.ResponseStatus = "not ok"
+		}
+		statusBulk.RecordsTotal = totalCount
+		statusBulk.ErrorCode = errStatus
+		statusBulk.RecordsInResponse = len(ids)
+
+		bulkItems = append(bulkItems, GetPaymentsBulkItem{
+			Status:       statusBulk,
+			PaymentInfos: records,
+		})
+	}
+	bulkResp.BulkItems = bulkItems
+
+	jsonRaw, err := json.Marshal(bulkResp)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(jsonRaw)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func TestPaymentsListingCountSuccess(t *testing.T) {
+	const totalCount = 10
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsedRequest, err := common.ExtractBulkFiltersFromRequest(r)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		assert.Equal(t, "someclient", parsedRequest["clientCode"])
+		assert.Equal(t, "somesess", parsedRequest["sessionKey"])
+		requests := parsedRequest["requests"].([]map[string]interface{})
+		assert.Equal(t, float64(1), requests[0]["pageNo"])
+		assert.Equal(t, float64(1), requests[0]["recordsOnPage"])
+		assert.Equal(t, "getPayments", requests[0]["requestName"])
+		assert.Equal(t, "smeval", requests[0]["somekey"])
+
+		err = sendPaymentsResponse(w, 0, totalCount, [][]int{{1}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	salesClient := NewClient(baseClient)
+	dataProvider := NewPaymentsListingDataProvider(salesClient)
+
+	actualCount, err := dataProvider.Count(context.Background(), map[string]interface{}{"somekey": "smeval", "pageNo": 1, "recordsOnPage": 1})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, totalCount, actualCount)
+}
+
+func TestPaymentsListingCountError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := sendPaymentsResponse(w, sharedCommon.MalformedRequest, 0, [][]int{{1}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	salesClient := NewClient(baseClient)
+	dataProvider := NewPaymentsListingDataProvider(salesClient)
+
+	actualCount, err := dataProvider.Count(context.Background(), map[string]interface{}{"somekey": "smeval"})
+	assert.Error(t, err)
+	if err == nil {
+		return
+	}
+	assert.Contains(t, err.Error(), sharedCommon.MalformedRequest.String())
+	assert.Equal(t, 0, actualCount)
+}
+
+func TestPaymentsListingCountWithNoBulkItems(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := sendPaymentsResponse(w, 0, 0, [][]int{})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	salesClient := NewClient(baseClient)
+	dataProvider := NewPaymentsListingDataProvider(salesClient)
+
+	actualCount, err := dataProvider.Count(context.Background(), map[string]interface{}{"somekey": "smeval"})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, 0, actualCount)
+}
+
+func TestPaymentsReadSuccess(t *testing.T) {
+	const limit = 2
+	const offset = 1
+	const totalCount = 10
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsedRequest, err := common.ExtractBulkFiltersFromRequest(r)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		assert.Equal(t, "someclient", parsedRequest["clientCode"])
+		assert.Equal(t, "somesess", parsedRequest["sessionKey"])
+
+		requests := parsedRequest["requests"].([]map[string]interface{})
+		assert.Len(t, requests, 1)
+
+		assert.Equal(t, float64(offset), requests[0]["pageNo"])
+		assert.Equal(t, float64(limit), requests[0]["recordsOnPage"])
+
+		assert.Equal(t, "getPayments", requests[0]["requestName"])
+		assert.Equal(t, "smeval", requests[0]["somekey"])
+
+		err = sendPaymentsResponse(w, 0, totalCount, [][]int{{1, 2}, {3, 4}, {5}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	salesClient := NewClient(baseClient)
+	dataProvider := NewPaymentsListingDataProvider(salesClient)
+
+	actualIDs := make([]int, 0, 5)
+	err := dataProvider.Read(
+		context.Background(),
+		[]map[string]interface{}{
+			{
+				"somekey":       "smeval",
+				"pageNo":        1,
+				"recordsOnPage": 2,
+			},
+		},
+		func(item interface{}) {
+			assert.IsType(t, item, PaymentInfo{})
+			actualIDs = append(actualIDs, item.(PaymentInfo).PaymentID)
+		},
+	)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, actualIDs)
+}
+
+func TestPaymentsReadError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := sendPaymentsResponse(w, sharedCommon.MalformedRequest, 10, [][]int{{1}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	salesClient := NewClient(baseClient)
+	dataProvider := NewPaymentsListingDataProvider(salesClient)
+
+	err := dataProvider.Read(
+		context.Background(),
+		[]map[string]interface{}{{"somekey": "smeval"}},
+		func(item interface{}) {},
+	)
+	assert.Error(t, err)
+	if err == nil {
+		return
+	}
+
+	assert.Contains(t, err.Error(), sharedCommon.MalformedRequest.String())
+}
+
+func TestPaymentsReadSuccessIntegration(t *testing.T) {
+	const totalCount = 11
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsedRequest, err := common.ExtractBulkFiltersFromRequest(r)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		requests := parsedRequest["requests"].([]map[string]interface{})
+		assert.Len(t, requests, 1)
+
+		if requests[0]["pageNo"] == float64(1) {
+			err = sendPaymentsResponse(w, 0, totalCount, [][]int{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}})
+		} else {
+			err = sendPaymentsResponse(w, 0, totalCount, [][]int{{11}})
+		}
+
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	salesClient := NewClient(baseClient)
+	dataProvider := NewPaymentsListingDataProvider(salesClient)
+
+	lister := sharedCommon.NewLister(
+		sharedCommon.ListingSettings{
+			MaxRequestsCountPerSecond: 0,
+			StreamBufferLength:        10,
+			MaxItemsPerRequest:        10,
+			MaxFetchersCount:          10,
+		},
+		dataProvider,
+		func(sleepTime time.Duration) {},
+	)
+
+	itemsChan := lister.Get(context.Background(), map[string]interface{}{})
+
+	actualIDs := collectPaymentsIDsFromChannel(itemsChan)
+	sort.Ints(actualIDs)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, actualIDs)
+}
+
+func collectPaymentsIDsFromChannel(itemsStream sharedCommon.ItemsStream) []int {
+	actualIDs := make([]int, 0)
+	doneChan := make(chan struct{}, 1)
+	go func() {
+		defer close(doneChan)
+		for item := range itemsStream {
+			actualIDs = append(actualIDs, item.Payload.(PaymentInfo).PaymentID)
+		}
+	}()
+
+mainLoop:
+	for {
+		select {
+		case <-doneChan:
+			break mainLoop
+		case <-time.After(time.Second * 5):
+			break mainLoop
+		}
+	}
+
+	return actualIDs
+}
+
+func sendProjectsResponse(w http.ResponseWriter, errStatus sharedCommon.ApiError, totalCount int, idsBulk [][]int) error {
+	bulkResp := GetProjectsResponseBulk{
+		Status: sharedCommon.Status{ResponseStatus: "ok"},
+	}
+
+	bulkItems := make([]GetProjectsBulkItem, 0, len(idsBulk))
+	for _, ids := range idsBulk {
+		records := make([]Project, 0, len(ids))
+		for _, id := range ids {
+			records = append(records, Project{
+				ProjectID: uint(id),
+			})
+		}
+		statusBulk := sharedCommon.StatusBulk{}
+		if errStatus == 0 {
+			statusBulk.ResponseStatus = "ok"
+		} else {
+			statusBulk.ResponseStatus = "not ok"
+		}
+		statusBulk.RecordsTotal = totalCount
+		statusBulk.ErrorCode = errStatus
+		statusBulk.RecordsInResponse = len(ids)
+
+		bulkItems = append(bulkItems, GetProjectsBulkItem{
+			Status:   statusBulk,
+			Projects: records,
+		})
+	}
+	bulkResp.BulkItems = bulkItems
+
+	jsonRaw, err := json.Marshal(bulkResp)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(jsonRaw)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func TestProjectsListingCountSuccess(t *testing.T) {
+	const totalCount = 10
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsedRequest, err := common.ExtractBulkFiltersFromRequest(r)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		assert.Equal(t, "someclient", parsedRequest["clientCode"])
+		assert.Equal(t, "somesess", parsedRequest["sessionKey"])
+		requests := parsedRequest["requests"].([]map[string]interface{})
+		assert.Equal(t, float64(1), requests[0]["pageNo"])
+		assert.Equal(t, float64(1), requests[0]["recordsOnPage"])
+		assert.Equal(t, "getProjects", requests[0]["requestName"])
+		assert.Equal(t, "smeval", requests[0]["somekey"])
+
+		err = sendProjectsResponse(w, 0, totalCount, [][]int{{1}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	salesClient := NewClient(baseClient)
+	dataProvider := NewProjectsListingDataProvider(salesClient)
+
+	actualCount, err := dataProvider.Count(context.Background(), map[string]interface{}{"somekey": "smeval", "pageNo": 1, "recordsOnPage": 1})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, totalCount, actualCount)
+}
+
+func TestProjectsListingCountError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := sendProjectsResponse(w, sharedCommon.MalformedRequest, 0, [][]int{{1}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	salesClient := NewClient(baseClient)
+	dataProvider := NewProjectsListingDataProvider(salesClient)
+
+	actualCount, err := dataProvider.Count(context.Background(), map[string]interface{}{"somekey": "smeval"})
+	assert.Error(t, err)
+	if err == nil {
+		return
+	}
+	assert.Contains(t, err.Error(), sharedCommon.MalformedRequest.String())
+	assert.Equal(t, 0, actualCount)
+}
+
+func TestProjectsListingCountWithNoBulkItems(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := sendProjectsResponse(w, 0, 0, [][]int{})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	salesClient := NewClient(baseClient)
+	dataProvider := NewProjectsListingDataProvider(salesClient)
+
+	actualCount, err := dataProvider.Count(context.Background(), map[string]interface{}{"somekey": "smeval"})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, 0, actualCount)
+}
+
+func TestProjectsReadSuccess(t *testing.T) {
+	const limit = 2
+	const offset = 1
+	const totalCount = 10
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsedRequest, err := common.ExtractBulkFiltersFromRequest(r)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		assert.Equal(t, "someclient", parsedRequest["clientCode"])
+		assert.Equal(t, "somesess", parsedRequest["sessionKey"])
+
+		requests := parsedRequest["requests"].([]map[string]interface{})
+		assert.Len(t, requests, 1)
+
+		assert.Equal(t, float64(offset), requests[0]["pageNo"])
+		assert.Equal(t, float64(limit), requests[0]["recordsOnPage"])
+
+		assert.Equal(t, "getProjects", requests[0]["requestName"])
+		assert.Equal(t, "smeval", requests[0]["somekey"])
+
+		err = sendProjectsResponse(w, 0, totalCount, [][]int{{1, 2}, {3, 4}, {5}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	salesClient := NewClient(baseClient)
+	dataProvider := NewProjectsListingDataProvider(salesClient)
+
+	actualIDs := make([]int, 0, 5)
+	err := dataProvider.Read(
+		context.Background(),
+		[]map[string]interface{}{
+			{
+				"somekey":       "smeval",
+				"pageNo":        1,
+				"recordsOnPage": 2,
+			},
+		},
+		func(item interface{}) {
+			assert.IsType(t, item, Project{})
+			actualIDs = append(actualIDs, int(item.(Project).ProjectID))
+		},
+	)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, actualIDs)
+}
+
+func TestProjectsReadError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := sendProjectsResponse(w, sharedCommon.MalformedRequest, 10, [][]int{{1}})
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	salesClient := NewClient(baseClient)
+	dataProvider := NewProjectsListingDataProvider(salesClient)
+
+	err := dataProvider.Read(
+		context.Background(),
+		[]map[string]interface{}{{"somekey": "smeval"}},
+		func(item interface{}) {},
+	)
+	assert.Error(t, err)
+	if err == nil {
+		return
+	}
+
+	assert.Contains(t, err.Error(), sharedCommon.MalformedRequest.String())
+}
+
+func TestProjectsReadSuccessIntegration(t *testing.T) {
+	const totalCount = 11
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsedRequest, err := common.ExtractBulkFiltersFromRequest(r)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		requests := parsedRequest["requests"].([]map[string]interface{})
+		assert.Len(t, requests, 1)
+
+		if requests[0]["pageNo"] == float64(1) {
+			err = sendProjectsResponse(w, 0, totalCount, [][]int{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}})
+		} else {
+			err = sendProjectsResponse(w, 0, totalCount, [][]int{{11}})
+		}
+
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+	}))
+
+	defer srv.Close()
+
+	baseClient := common.NewClient("somesess", "someclient", "", nil, nil)
+	baseClient.Url = srv.URL
+	salesClient := NewClient(baseClient)
+	dataProvider := NewProjectsListingDataProvider(salesClient)
+
+	lister := sharedCommon.NewLister(
+		sharedCommon.ListingSettings{
+			MaxRequestsCountPerSecond: 0,
+			StreamBufferLength:        10,
+			MaxItemsPerRequest:        10,
+			MaxFetchersCount:          10,
+		},
+		dataProvider,
+		func(sleepTime time.Duration) {},
+	)
+
+	itemsChan := lister.Get(context.Background(), map[string]interface{}{})
+
+	actualIDs := collectProjectsIDsFromChannel(itemsChan)
+	sort.Ints(actualIDs)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, actualIDs)
+}
+
+func collectProjectsIDsFromChannel(itemsStream sharedCommon.ItemsStream) []int {
+	actualIDs := make([]int, 0)
+	doneChan := make(chan struct{}, 1)
+	go func() {
+		defer close(doneChan)
+		for item := range itemsStream {
+			actualIDs = append(actualIDs, int(item.Payload.(Project).ProjectID))
+		}
+	}()
+
+mainLoop:
+	for {
+		select {
+		case <-doneChan:
+			break mainLoop
+		case <-time.After(time.Second * 5):
+			break mainLoop
+		}
+	}
+
+	return actualIDs
+}
@@ -10,6 +10,16 @@ type (
 		Projects []Project      `json:"records"`
 	}
 
+	GetProjectsBulkItem struct {
+		Status   common2.StatusBulk `json:"status"`
+		Projects []Project          `json:"records"`
+	}
+
+	GetProjectsResponseBulk struct {
+		Status    common2.Status        `json:"status"`
+		BulkItems []GetProjectsBulkItem `json:"requests"`
+	}
+
 	GetProjectStatusesResponse struct {
 		Status          common2.Status  `json:"status"`
 		ProjectStatuses []ProjectStatus `json:"records"`
@@ -2,39 +2,28 @@ package sales
 
 import (
 	"context"
-	"encoding/json"
 	"github.com/erply/api-go-wrapper/internal/common"
-	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
 )
 
 // GetProjects will list projects according to specified filters.
 func (cli *Client) GetProjects(ctx context.Context, filters map[string]string) ([]Project, error) {
-	resp, err := cli.SendRequest(ctx, "getProjects", filters)
+	res, err := common.Call[GetProjectsResponse](ctx, cli.Client, "getProjects", filters)
 	if err != nil {
 		return nil, err
 	}
-	var res GetProjectsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("failed to unmarshal GetProjectsResponse", err, 0)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 	return res.Projects, nil
 }
 
+// GetProjectsBulk will list projects according to specified bulk filters.
+func (cli *Client) GetProjectsBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetProjectsResponseBulk, error) {
+	return common.CallBulk[GetProjectsResponseBulk](ctx, cli.Client, "getProjects", bulkFilters, baseFilters)
+}
+
 // GetProjectStatus will list projects statuses according to specified filters.
 func (cli *Client) GetProjectStatus(ctx context.Context, filters map[string]string) ([]ProjectStatus, error) {
-	resp, err := cli.SendRequest(ctx, "getProjectStatuses", filters)
+	res, err := common.Call[GetProjectStatusesResponse](ctx, cli.Client, "getProjectStatuses", filters)
 	if err != nil {
 		return nil, err
 	}
-	var res GetProjectStatusesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("failed to unmarshal GetProjectStatusesResponse", err, 0)
-	}
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
 	return res.ProjectStatuses, nil
 }
@@ -2,28 +2,16 @@ package sales
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
-	"fmt"
 	"github.com/erply/api-go-wrapper/internal/common"
-	common2 "github.com/erply/api-go-wrapper/pkg/api/common"
-	"io/ioutil"
 )
 
 //GetVatRatesByVatRateID ...
 func (cli *Client) GetVatRates(ctx context.Context, filters map[string]string) (VatRates, error) {
-	resp, err := cli.SendRequest(ctx, "getVatRates", filters)
+	res, err := common.Call[GetVatRatesResponse](ctx, cli.Client, "getVatRates", filters)
 	if err != nil {
 		return nil, err
 	}
-	res := &GetVatRatesResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, common2.NewFromError("unmarshaling GetVatRatesResponse failed", err, 0)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, common2.NewFromResponseStatus(&res.Status)
-	}
 	if res.VatRates == nil {
 		return nil, errors.New("no vat rates in response")
 	}
@@ -31,59 +19,15 @@ func (cli *Client) GetVatRates(ctx context.Context, filters map[string]string) (
 }
 
 func (cli *Client) GetVatRatesBulk(ctx context.Context, bulkFilters []map[string]interface{}, baseFilters map[string]string) (GetVatRatesResponseBulk, error) {
-	var bulkResp GetVatRatesResponseBulk
-	bulkInputs := make([]common.BulkInput, 0, len(bulkFilters))
-	for _, bulkFilterMap := range bulkFilters {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "getVatRates",
-			Filters:    bulkFilterMap,
-		})
-	}
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal GetVatRatesResponseBulk from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, common2.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
-
-	for _, bulkItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&bulkItem.Status.Status) {
-			return bulkResp, common2.NewErplyError(bulkItem.Status.ErrorCode.String(), bulkItem.Status.Request+": "+bulkItem.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-		}
-	}
-
-	return bulkResp, nil
+	return common.CallBulk[GetVatRatesResponseBulk](ctx, cli.Client, "getVatRates", bulkFilters, baseFilters)
 }
 
 func (cli *Client) SaveVatRate(ctx context.Context, filters map[string]string) (*SaveVatRateResult, error) {
-	resp, err := cli.SendRequest(ctx, "saveVatRate", filters)
-	if err != nil {
-		return nil, common2.NewFromError("saveVatRate request failed", err, 0)
-	}
-	body, err := ioutil.ReadAll(resp.Body)
+	res, err := common.Call[SaveVatRateResultResponse](ctx, cli.Client, "saveVatRate", filters)
 	if err != nil {
 		return nil, err
 	}
 
-	res := &SaveVatRateResultResponse{}
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("ERPLY API: failed to unmarshal SaveVatRateResultResponse from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, common2.NewFromResponseStatus(&res.Status)
-	}
-
 	if len(res.SaveVatRateResult) == 0 {
 		return nil, nil
 	}
@@ -92,70 +36,15 @@ func (cli *Client) SaveVatRate(ctx context.Context, filters map[string]string) (
 }
 
 func (cli *Client) SaveVatRateBulk(ctx context.Context, bulkRequest []map[string]interface{}, baseFilters map[string]string) (SaveVatRateResponseBulk, error) {
-	var bulkResp SaveVatRateResponseBulk
-
-	if len(bulkRequest) > common2.MaxBulkRequestsCount {
-		return bulkResp, fmt.Errorf("cannot save more than %d price lists in one bulk request", common2.MaxBulkRequestsCount)
-	}
-
-	bulkInputs := make([]common.BulkInput, 0, len(bulkRequest))
-	for _, bulkInput := range bulkRequest {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "saveVatRate",
-			Filters:    bulkInput,
-		})
-	}
-
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal SaveVatRateResponseBulk from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, common2.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
-
-	for _, bulkRespItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&bulkRespItem.Status.Status) {
-			return bulkResp, common2.NewErplyError(
-				bulkRespItem.Status.ErrorCode.String(),
-				fmt.Sprintf("%+v", bulkRespItem.Status),
-				bulkResp.Status.ErrorCode,
-			)
-		}
-	}
-
-	return bulkResp, nil
+	return common.CallBulk[SaveVatRateResponseBulk](ctx, cli.Client, "saveVatRate", bulkRequest, baseFilters)
 }
 
 func (cli *Client) SaveVatRateComponent(ctx context.Context, filters map[string]string) (*SaveVatRateComponentResult, error) {
-	resp, err := cli.SendRequest(ctx, "saveVatRateComponent", filters)
-	if err != nil {
-		return nil, common2.NewFromError("saveVatRateComponent request failed", err, 0)
-	}
-	body, err := ioutil.ReadAll(resp.Body)
+	res, err := common.Call[SaveVatRateComponentResultResponse](ctx, cli.Client, "saveVatRateComponent", filters)
 	if err != nil {
 		return nil, err
 	}
 
-	res := &SaveVatRateComponentResultResponse{}
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("ERPLY API: failed to unmarshal SaveVatRateComponentResultResponse from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, common2.NewFromResponseStatus(&res.Status)
-	}
-
 	if len(res.SaveVatRateComponentResult) == 0 {
 		return nil, nil
 	}
@@ -164,47 +53,5 @@ func (cli *Client) SaveVatRateComponent(ctx context.Context, filters map[string]
 }
 
 func (cli *Client) SaveVatRateComponentBulk(ctx context.Context, bulkRequest []map[string]interface{}, baseFilters map[string]string) (SaveVatRateComponentResponseBulk, error) {
-	var bulkResp SaveVatRateComponentResponseBulk
-
-	if len(bulkRequest) > common2.MaxBulkRequestsCount {
-		return bulkResp, fmt.Errorf("cannot save more than %d price lists in one bulk request", common2.MaxBulkRequestsCount)
-	}
-
-	bulkInputs := make([]common.BulkInput, 0, len(bulkRequest))
-	for _, bulkInput := range bulkRequest {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "saveVatRateComponent",
-			Filters:    bulkInput,
-		})
-	}
-
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return bulkResp, err
-	}
-
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal SaveVatRateComponentResponseBulk from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, common2.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
-
-	for _, bulkRespItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&bulkRespItem.Status.Status) {
-			return bulkResp, common2.NewErplyError(
-				bulkRespItem.Status.ErrorCode.String(),
-				fmt.Sprintf("%+v", bulkRespItem.Status),
-				bulkResp.Status.ErrorCode,
-			)
-		}
-	}
-
-	return bulkResp, nil
+	return common.CallBulk[SaveVatRateComponentResponseBulk](ctx, cli.Client, "saveVatRateComponent", bulkRequest, baseFilters)
 }
@@ -0,0 +1,21 @@
+package warehouse
+
+import (
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+	"time"
+)
+
+//WarehouseFilter is a typed filter of getWarehouses
+type WarehouseFilter struct {
+	WarehouseID  int       `filter:"warehouseID"`
+	WarehouseIDs []int     `filter:"warehouseIDs"`
+	Code         string    `filter:"code"`
+	ChangedSince time.Time `filter:"changedSince"`
+	sharedCommon.Paging
+	Extra map[string]string `filter:",extra"` //parameters which have no field
+}
+
+//ToFilters sharedCommon.Filter interface implementation
+func (wf WarehouseFilter) ToFilters() (map[string]string, error) {
+	return sharedCommon.EncodeFilters(wf)
+}
@@ -0,0 +1,49 @@
+package warehouse
+
+import (
+	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
+)
+
+//WarehouseInput is a typed input of saveWarehouse, set WarehouseID to update an existing warehouse
+type WarehouseInput struct {
+	WarehouseID            int    `filter:"warehouseID"`
+	Name                   string `filter:"name"`
+	Code                   string `filter:"code"`
+	AddressID              int    `filter:"addressID"`
+	Street                 string `filter:"street"`
+	Address2               string `filter:"address2"`
+	City                   string `filter:"city"`
+	State                  string `filter:"state"`
+	ZIPcode                string `filter:"ZIPcode"`
+	Country                string `filter:"country"`
+	StoreGroups            []int  `filter:"storeGroups"`
+	CompanyName            string `filter:"companyName"`
+	CompanyCode            string `filter:"companyCode"`
+	CompanyVatNumber       string `filter:"companyVatNumber"`
+	Phone                  string `filter:"phone"`
+	Fax                    string `filter:"fax"`
+	Email                  string `filter:"email"`
+	Website                string `filter:"website"`
+	BankName               string `filter:"bankName"`
+	BankAccountNumber      string `filter:"bankAccountNumber"`
+	Iban                   string `filter:"iban"`
+	Swift                  string `filter:"swift"`
+	PricelistID            int    `filter:"pricelistID"`
+	PricelistID2           int    `filter:"pricelistID2"`
+	PricelistID3           int    `filter:"pricelistID3"`
+	PricelistID4           int    `filter:"pricelistID4"`
+	PricelistID5           int    `filter:"pricelistID5"`
+	DefaultCustomerGroupID int    `filter:"defaultCustomerGroupID"`
+	IsOfflineInventory     *bool  `filter:"isOfflineInventory"`
+	TimeZone               string `filter:"timeZone"`
+
+	//sent as attributeName1, attributeType1, attributeValue1 and so on
+	Attributes []sharedCommon.ObjAttribute `filter:",indexed"`
+
+	Extra map[string]string `filter:",extra"` //parameters which have no field
+}
+
+//ToFilters sharedCommon.Filter interface implementation
+func (wi WarehouseInput) ToFilters() (map[string]string, error) {
+	return sharedCommon.EncodeFilters(wi)
+}
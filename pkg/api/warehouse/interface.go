@@ -13,8 +13,12 @@ type (
 			GetWarehousesResponseBulk,
 			error,
 		)
+		GetWarehousesWithFilter(ctx context.Context, filter WarehouseFilter) (Warehouses, error)
+		GetWarehousesBulkWithFilters(ctx context.Context, filters []WarehouseFilter, baseFilters map[string]string) (GetWarehousesResponseBulk, error)
 		SaveWarehouse(ctx context.Context, filters map[string]string) (*SaveWarehouseResult, error)
 		SaveWarehouseBulk(ctx context.Context, bulkRequest []map[string]interface{}, baseFilters map[string]string) (SaveWarehouseResponseBulk, error)
+		SaveWarehouseWithInput(ctx context.Context, input WarehouseInput) (*SaveWarehouseResult, error)
+		SaveWarehouseBulkWithInputs(ctx context.Context, inputs []WarehouseInput, baseFilters map[string]string) (SaveWarehouseResponseBulk, error)
 		InventoryManager
 	}
 )
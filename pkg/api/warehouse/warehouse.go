@@ -2,29 +2,17 @@ package warehouse
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"github.com/erply/api-go-wrapper/internal/common"
 	sharedCommon "github.com/erply/api-go-wrapper/pkg/api/common"
-	"io/ioutil"
 )
 
 //GetWarehouses ...
 func (cli *Client) GetWarehouses(ctx context.Context, filters map[string]string) (Warehouses, error) {
-	resp, err := cli.SendRequest(ctx, "getWarehouses", filters)
+	res, err := common.Call[GetWarehousesResponse](ctx, cli.Client, "getWarehouses", filters)
 	if err != nil {
 		return nil, err
 	}
 
-	res := &GetWarehousesResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("unmarshalling GetWarehousesResponse failed", err, 0)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
-
 	if len(res.Warehouses) == 0 {
 		return nil, nil
 	}
@@ -34,25 +22,16 @@ func (cli *Client) GetWarehouses(ctx context.Context, filters map[string]string)
 
 //GetWarehousesWithStatus ...
 func (cli *Client) GetWarehousesWithStatus(ctx context.Context, filters map[string]string) (*GetWarehousesResponse, error) {
-	resp, err := cli.SendRequest(ctx, "getWarehouses", filters)
+	res, err := common.Call[GetWarehousesResponse](ctx, cli.Client, "getWarehouses", filters)
 	if err != nil {
 		return nil, err
 	}
 
-	res := &GetWarehousesResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, sharedCommon.NewFromError("unmarshalling GetWarehousesResponse failed", err, 0)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
-
 	if len(res.Warehouses) == 0 {
 		return nil, nil
 	}
 
-	return res, nil
+	return &res, nil
 }
 
 func (cli *Client) GetWarehousesBulk(
@@ -63,59 +42,35 @@ func (cli *Client) GetWarehousesBulk(
 	GetWarehousesResponseBulk,
 	error,
 ) {
-	var bulkResp GetWarehousesResponseBulk
-	bulkInputs := make([]common.BulkInput, 0, len(bulkFilters))
-	for _, bulkFilterMap := range bulkFilters {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "getWarehouses",
-			Filters:    bulkFilterMap,
-		})
-	}
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
+	return common.CallBulk[GetWarehousesResponseBulk](ctx, cli.Client, "getWarehouses", bulkFilters, baseFilters)
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
+//GetWarehousesWithFilter is GetWarehouses with a typed filter
+func (cli *Client) GetWarehousesWithFilter(ctx context.Context, filter WarehouseFilter) (Warehouses, error) {
+	filters, err := filter.ToFilters()
 	if err != nil {
-		return bulkResp, err
+		return nil, err
 	}
 
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal GetWarehousesResponseBulk from '%s': %v", string(body), err)
-	}
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, sharedCommon.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
+	return cli.GetWarehouses(ctx, filters)
+}
 
-	for _, bulkItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&bulkItem.Status.Status) {
-			return bulkResp, sharedCommon.NewErplyError(bulkItem.Status.ErrorCode.String(), bulkItem.Status.Request+": "+bulkItem.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-		}
+//GetWarehousesBulkWithFilters is GetWarehousesBulk with typed filters of the sub-requests
+func (cli *Client) GetWarehousesBulkWithFilters(ctx context.Context, filters []WarehouseFilter, baseFilters map[string]string) (GetWarehousesResponseBulk, error) {
+	bulkFilters, err := sharedCommon.ToBulkFilters(filters)
+	if err != nil {
+		return GetWarehousesResponseBulk{}, err
 	}
 
-	return bulkResp, nil
+	return cli.GetWarehousesBulk(ctx, bulkFilters, baseFilters)
 }
 
 func (cli *Client) SaveWarehouse(ctx context.Context, filters map[string]string) (*SaveWarehouseResult, error) {
-	resp, err := cli.SendRequest(ctx, "saveWarehouse", filters)
-	if err != nil {
-		return nil, sharedCommon.NewFromError("saveWarehouse request failed", err, 0)
-	}
-	body, err := ioutil.ReadAll(resp.Body)
+	res, err := common.Call[SaveWarehouseResponse](ctx, cli.Client, "saveWarehouse", filters)
 	if err != nil {
 		return nil, err
 	}
 
-	res := &SaveWarehouseResponse{}
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("ERPLY API: failed to unmarshal SaveWarehouseResponse from '%s': %v", string(body), err)
-	}
-
-	if !common.IsJSONResponseOK(&res.Status) {
-		return nil, sharedCommon.NewFromResponseStatus(&res.Status)
-	}
-
 	if len(res.Results) == 0 {
 		return nil, nil
 	}
@@ -124,47 +79,25 @@ func (cli *Client) SaveWarehouse(ctx context.Context, filters map[string]string)
 }
 
 func (cli *Client) SaveWarehouseBulk(ctx context.Context, bulkRequest []map[string]interface{}, baseFilters map[string]string) (SaveWarehouseResponseBulk, error) {
-	var bulkResp SaveWarehouseResponseBulk
-
-	if len(bulkRequest) > sharedCommon.MaxBulkRequestsCount {
-		return bulkResp, fmt.Errorf("cannot save more than %d warehouses in one bulk request", sharedCommon.MaxBulkRequestsCount)
-	}
-
-	bulkInputs := make([]common.BulkInput, 0, len(bulkRequest))
-	for _, bulkInput := range bulkRequest {
-		bulkInputs = append(bulkInputs, common.BulkInput{
-			MethodName: "saveWarehouse",
-			Filters:    bulkInput,
-		})
-	}
-
-	resp, err := cli.SendRequestBulk(ctx, bulkInputs, baseFilters)
-	if err != nil {
-		return bulkResp, err
-	}
+	return common.CallBulk[SaveWarehouseResponseBulk](ctx, cli.Client, "saveWarehouse", bulkRequest, baseFilters)
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
+//SaveWarehouseWithInput is SaveWarehouse with a typed input
+func (cli *Client) SaveWarehouseWithInput(ctx context.Context, input WarehouseInput) (*SaveWarehouseResult, error) {
+	filters, err := input.ToFilters()
 	if err != nil {
-		return bulkResp, err
-	}
-
-	if err := json.Unmarshal(body, &bulkResp); err != nil {
-		return bulkResp, fmt.Errorf("ERPLY API: failed to unmarshal SaveWarehouseResponseBulk from '%s': %v", string(body), err)
+		return nil, err
 	}
 
-	if !common.IsJSONResponseOK(&bulkResp.Status) {
-		return bulkResp, sharedCommon.NewErplyError(bulkResp.Status.ErrorCode.String(), bulkResp.Status.Request+": "+bulkResp.Status.ResponseStatus, bulkResp.Status.ErrorCode)
-	}
+	return cli.SaveWarehouse(ctx, filters)
+}
 
-	for _, bulkRespItem := range bulkResp.BulkItems {
-		if !common.IsJSONResponseOK(&bulkRespItem.Status.Status) {
-			return bulkResp, sharedCommon.NewErplyError(
-				bulkRespItem.Status.ErrorCode.String(),
-				fmt.Sprintf("%+v", bulkRespItem.Status),
-				bulkResp.Status.ErrorCode,
-			)
-		}
+//SaveWarehouseBulkWithInputs is SaveWarehouseBulk with typed inputs of the sub-requests
+func (cli *Client) SaveWarehouseBulkWithInputs(ctx context.Context, inputs []WarehouseInput, baseFilters map[string]string) (SaveWarehouseResponseBulk, error) {
+	bulkFilters, err := sharedCommon.ToBulkFilters(inputs)
+	if err != nil {
+		return SaveWarehouseResponseBulk{}, err
 	}
 
-	return bulkResp, nil
+	return cli.SaveWarehouseBulk(ctx, bulkFilters, baseFilters)
 }